@@ -0,0 +1,595 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listWorkflowRunsTool = &mcp.Tool{
+	Name:        "list-workflow-runs",
+	Description: "A tool to list the recent GitHub Actions workflow runs, or Azure Pipelines runs, of a repository, newest first",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"workflow": {
+				Type:        "string",
+				Description: "Only return runs of this workflow, given by its file name (e.g., ci.yml) or display name (e.g., CI)",
+			},
+			"event": {
+				Type:        "string",
+				Description: "Only return runs triggered by this event (e.g., push, pull_request, schedule)",
+			},
+			"branch": {
+				Type:        "string",
+				Description: "Only return runs for this branch",
+			},
+			"status": {
+				Type:        "string",
+				Description: "Only return runs with this status or conclusion (e.g., in_progress, failure, success)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of runs to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"provider": providerProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListWorkflowRunsArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	Workflow string `json:"workflow,omitempty"`
+	Event    string `json:"event,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	RunNumber  int       `json:"run_number"`
+	Event      string    `json:"event"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	Branch     string    `json:"head_branch"`
+	HeadSHA    string    `json:"head_sha"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+	HTMLURL    string    `json:"html_url"`
+}
+
+type WorkflowRunListOutput struct {
+	Repository   string        `json:"repository"`
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+type workflowRun struct {
+	WorkflowRun
+	// Actor shadows WorkflowRun.Actor, which is flattened to the login.
+	Actor struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+}
+
+func (c *GithubClient) ListWorkflowRuns(ctx context.Context, req *mcp.CallToolRequest, args ListWorkflowRunsArgs) (*mcp.CallToolResult, WorkflowRunListOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, WorkflowRunListOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, WorkflowRunListOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	forge, err := c.forge(args.CommonArgs)
+	if err != nil {
+		return nil, WorkflowRunListOutput{}, err
+	}
+	runs, ok := forge.(runForge)
+	if !ok {
+		return nil, WorkflowRunListOutput{}, fmt.Errorf("the provider has no CI runs to list")
+	}
+	out, err := runs.ListRuns(ctx, args)
+	if err != nil {
+		return nil, WorkflowRunListOutput{}, err
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Workflow runs for repository %s/%s (%d total):\n", args.Owner, args.Repo, out.TotalCount)
+	for _, run := range out.WorkflowRuns {
+		fmt.Fprintf(&result, "%d %s #%d [%s] on %s (%s) by %s at %s %s\n",
+			run.ID, run.Name, run.RunNumber, runState(run), run.Branch, run.Event, run.Actor, run.CreatedAt.Format(time.RFC3339), run.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, *out, nil
+}
+
+// workflowRuns fetches the most recent workflow runs matching args.
+func (c *GithubClient) workflowRuns(ctx context.Context, baseURL string, args ListWorkflowRunsArgs) (*WorkflowRunListOutput, error) {
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(limit))
+	if args.Event != "" {
+		query.Set("event", args.Event)
+	}
+	if args.Branch != "" {
+		query.Set("branch", args.Branch)
+	}
+	if args.Status != "" {
+		query.Set("status", args.Status)
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", baseURL, url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	apiURL := repoURL + "/actions/runs?" + query.Encode()
+	// Workflow files can be queried directly, display names are matched
+	// against the runs below.
+	byName := args.Workflow != "" && !isWorkflowFile(args.Workflow)
+	if args.Workflow != "" && !byName {
+		apiURL = fmt.Sprintf("%s/actions/workflows/%s/runs?%s", repoURL, url.PathEscape(args.Workflow), query.Encode())
+	}
+
+	var raw struct {
+		TotalCount   int           `json:"total_count"`
+		WorkflowRuns []workflowRun `json:"workflow_runs"`
+	}
+	if err := c.getJSON(ctx, apiURL, &raw); err != nil {
+		return nil, err
+	}
+	out := &WorkflowRunListOutput{
+		Repository:   args.Owner + "/" + args.Repo,
+		TotalCount:   raw.TotalCount,
+		WorkflowRuns: []WorkflowRun{},
+	}
+	for _, r := range raw.WorkflowRuns {
+		if byName && !strings.EqualFold(r.Name, args.Workflow) {
+			continue
+		}
+		run := r.WorkflowRun
+		run.Actor = r.Actor.Login
+		out.WorkflowRuns = append(out.WorkflowRuns, run)
+	}
+	if byName {
+		out.TotalCount = len(out.WorkflowRuns)
+	}
+	return out, nil
+}
+
+// isWorkflowFile reports whether workflow names a workflow by file name or
+// numeric ID rather than by display name.
+func isWorkflowFile(workflow string) bool {
+	if _, err := strconv.ParseInt(workflow, 10, 64); err == nil {
+		return true
+	}
+	return strings.HasSuffix(workflow, ".yml") || strings.HasSuffix(workflow, ".yaml")
+}
+
+// runState describes a run by its conclusion once it completed, its status
+// otherwise.
+func runState(run WorkflowRun) string {
+	if run.Conclusion != "" {
+		return run.Conclusion
+	}
+	return run.Status
+}
+
+// maxLogArchiveBytes bounds how much log data is downloaded for a single run.
+const maxLogArchiveBytes = 64 << 20
+
+var getWorkflowRunLogsTool = &mcp.Tool{
+	Name:        "get-workflow-run-logs",
+	Description: "A tool to read the logs of a GitHub Actions workflow run, or of a single job of it. Long logs are returned in chunks",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"run_id": {
+				Type:        "integer",
+				Description: "ID of the workflow run, as returned by list-workflow-runs",
+			},
+			"job_id": {
+				Type:        "integer",
+				Description: "ID of a single job to read the log of instead of the whole run",
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Byte offset to start reading at, to continue a truncated log",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"max_bytes": {
+				Type:        "integer",
+				Description: "Maximum number of bytes to return (defaults to 102400)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"tail": {
+				Type:        "boolean",
+				Description: "Return the end of the log instead of the beginning, where failures usually are",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type GetWorkflowRunLogsArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	RunID    int64  `json:"run_id,omitempty"`
+	JobID    int64  `json:"job_id,omitempty"`
+	Offset   int    `json:"offset,omitempty"`
+	MaxBytes int    `json:"max_bytes,omitempty"`
+	Tail     bool   `json:"tail,omitempty"`
+}
+
+func (c *GithubClient) GetWorkflowRunLogs(ctx context.Context, req *mcp.CallToolRequest, args GetWorkflowRunLogsArgs) (*mcp.CallToolResult, any, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, nil, fmt.Errorf("owner and repo are required")
+	}
+	if args.JobID == 0 {
+		err := elicitMissing(ctx, req, "Which workflow run should the logs be fetched for?",
+			elicitField{name: "run_id", description: "ID of the workflow run", value: &args.RunID})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if args.RunID == 0 && args.JobID == 0 {
+		return nil, nil, fmt.Errorf("either run_id or job_id is required")
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+
+	var logs []byte
+	var err error
+	if args.JobID != 0 {
+		// Job logs are served as plain text.
+		logs, err = c.download(ctx, fmt.Sprintf("%s/actions/jobs/%d/logs", repoURL, args.JobID), maxLogArchiveBytes)
+	} else {
+		logs, err = c.runLogs(ctx, fmt.Sprintf("%s/actions/runs/%d/logs", repoURL, args.RunID))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	maxBytes := args.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxFileBytes
+	}
+	offset := args.Offset
+	if args.Tail && args.Offset == 0 {
+		offset = max(len(logs)-maxBytes, 0)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: chunk(logs, offset, maxBytes)},
+		},
+	}, nil, nil
+}
+
+// runLogs downloads the log archive of a workflow run and concatenates the
+// logs of its jobs.
+func (c *GithubClient) runLogs(ctx context.Context, apiURL string) ([]byte, error) {
+	data, err := c.download(ctx, apiURL, maxLogArchiveBytes)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log archive: %w", err)
+	}
+	// The archive holds one log per job at the top level, plus the same
+	// content split per step in a directory per job. Prefer the former.
+	files := slices.DeleteFunc(slices.Clone(archive.File), func(f *zip.File) bool {
+		return strings.Contains(f.Name, "/") || f.FileInfo().IsDir()
+	})
+	if len(files) == 0 {
+		files = archive.File
+	}
+	slices.SortFunc(files, func(a, b *zip.File) int { return strings.Compare(a.Name, b.Name) })
+
+	var logs bytes.Buffer
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from log archive: %w", f.Name, err)
+		}
+		fmt.Fprintf(&logs, "===== %s =====\n", f.Name)
+		_, err = io.Copy(&logs, io.LimitReader(rc, maxLogArchiveBytes))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from log archive: %w", f.Name, err)
+		}
+	}
+	return logs.Bytes(), nil
+}
+
+// download fetches url and returns its body, failing if it is larger than
+// limit bytes. The bytes received are reported as progress of the tool call.
+func (c *GithubClient) download(ctx context.Context, url string, limit int64) ([]byte, error) {
+	return c.downloadMedia(ctx, url, "", limit)
+}
+
+// downloadMedia is download asking for the accept media type, like the diff
+// of a pull request, when it isn't empty.
+func (c *GithubClient) downloadMedia(ctx context.Context, url, accept string, limit int64) ([]byte, error) {
+	var data bytes.Buffer
+	if _, err := c.downloadTo(ctx, &data, url, accept, limit); err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+// downloadTo is downloadMedia writing the body to w as it arrives, for
+// downloads too large to hold in memory. It returns the size of the body.
+func (c *GithubClient) downloadTo(ctx context.Context, w io.Writer, url, accept string, limit int64) (int64, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body := newProgressReader(ctx, resp.Body, resp.ContentLength)
+	n, err := io.Copy(w, io.LimitReader(body, limit+1))
+	if err != nil {
+		return 0, err
+	}
+	if n > limit {
+		return 0, fmt.Errorf("download is larger than %d bytes", limit)
+	}
+	return n, nil
+}
+
+// chunk returns up to max bytes of data starting at offset, with markers
+// telling the reader what was left out and how to get the rest. Both ends
+// are moved back to the start of a UTF-8 character so none is split.
+func chunk(data []byte, offset, max int) string {
+	if offset >= len(data) {
+		return fmt.Sprintf("[offset %d is past the end, the content is %d bytes long]", offset, len(data))
+	}
+	offset = runeBoundary(data, offset)
+	end := runeBoundary(data, min(offset+max, len(data)))
+	if end == offset {
+		// max is smaller than the character at offset, return it whole.
+		_, size := utf8.DecodeRune(data[offset:])
+		end = offset + size
+	}
+	var b strings.Builder
+	if offset > 0 {
+		fmt.Fprintf(&b, "[... skipped the first %d bytes ...]\n", offset)
+	}
+	b.Write(data[offset:end])
+	if end < len(data) {
+		fmt.Fprintf(&b, "\n[... truncated, showing bytes %d-%d of %d, call again with offset=%d for more ...]", offset, end, len(data), end)
+	}
+	return b.String()
+}
+
+var dispatchWorkflowTool = &mcp.Tool{
+	Name:        "dispatch-workflow",
+	Description: "A tool to trigger a run of a Github Actions workflow that has a workflow_dispatch trigger",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"workflow": {
+				Type:        "string",
+				Description: "Workflow to run, given by its file name (e.g., release.yml), ID or display name (e.g., Release)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch or tag to run the workflow on (defaults to the default branch)",
+			},
+			"inputs": {
+				Type:                 "object",
+				Description:          "Values of the inputs declared by the workflow's workflow_dispatch trigger",
+				AdditionalProperties: &jsonschema.Schema{Type: "string"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "workflow"},
+	},
+}
+
+type DispatchWorkflowArgs struct {
+	CommonArgs
+	Owner    string            `json:"owner"`
+	Repo     string            `json:"repo"`
+	Workflow string            `json:"workflow"`
+	Ref      string            `json:"ref,omitempty"`
+	Inputs   map[string]string `json:"inputs,omitempty"`
+}
+
+func (c *GithubClient) DispatchWorkflow(ctx context.Context, req *mcp.CallToolRequest, args DispatchWorkflowArgs) (*mcp.CallToolResult, any, error) {
+	if args.Owner == "" || args.Repo == "" || args.Workflow == "" {
+		return nil, nil, fmt.Errorf("owner, repo and workflow are required")
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	workflow := args.Workflow
+	if !isWorkflowFile(workflow) {
+		id, err := c.workflowID(ctx, repoURL, workflow)
+		if err != nil {
+			return nil, nil, err
+		}
+		workflow = strconv.FormatInt(id, 10)
+	}
+	// Unlike most endpoints, the dispatch one doesn't default to the
+	// default branch.
+	ref := args.Ref
+	if ref == "" {
+		var repo repositoryDetails
+		if err := c.getJSON(ctx, repoURL, &repo); err != nil {
+			return nil, nil, err
+		}
+		ref = repo.DefaultBranch
+	}
+
+	payload := map[string]any{"ref": ref}
+	if len(args.Inputs) > 0 {
+		payload["inputs"] = args.Inputs
+	}
+	apiURL := fmt.Sprintf("%s/actions/workflows/%s/dispatches", repoURL, url.PathEscape(workflow))
+	if err := c.sendJSON(ctx, http.MethodPost, apiURL, payload, nil); err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Triggered workflow %s of %s/%s on %s, use list-workflow-runs to follow the run\n", args.Workflow, args.Owner, args.Repo, ref)},
+		},
+	}, nil, nil
+}
+
+// workflowID looks up a workflow of the repository at repoURL by its display
+// name.
+func (c *GithubClient) workflowID(ctx context.Context, repoURL, name string) (int64, error) {
+	var workflows struct {
+		Workflows []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"workflows"`
+	}
+	if err := c.getJSON(ctx, repoURL+"/actions/workflows?per_page=100", &workflows); err != nil {
+		return 0, err
+	}
+	for _, w := range workflows.Workflows {
+		if strings.EqualFold(w.Name, name) {
+			return w.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("no workflow named %q", name)
+}
+
+var rerunWorkflowRunTool = &mcp.Tool{
+	Name:        "rerun-workflow-run",
+	Description: "A tool to re-run the failed jobs, or all jobs, of a Github Actions workflow run",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"run_id": {
+				Type:        "integer",
+				Description: "ID of the workflow run, as returned by list-workflow-runs",
+			},
+			"all_jobs": {
+				Type:        "boolean",
+				Description: "Re-run every job instead of only the failed ones",
+			},
+			"debug": {
+				Type:        "boolean",
+				Description: "Enable debug logging for the re-run",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "run_id"},
+	},
+}
+
+type RerunWorkflowRunArgs struct {
+	CommonArgs
+	Owner   string `json:"owner"`
+	Repo    string `json:"repo"`
+	RunID   int64  `json:"run_id"`
+	AllJobs bool   `json:"all_jobs,omitempty"`
+	Debug   bool   `json:"debug,omitempty"`
+}
+
+func (c *GithubClient) RerunWorkflowRun(ctx context.Context, req *mcp.CallToolRequest, args RerunWorkflowRunArgs) (*mcp.CallToolResult, any, error) {
+	if args.Owner == "" || args.Repo == "" || args.RunID == 0 {
+		return nil, nil, fmt.Errorf("owner, repo and run_id are required")
+	}
+	jobs, endpoint := "failed jobs", "rerun-failed-jobs"
+	if args.AllJobs {
+		jobs, endpoint = "all jobs", "rerun"
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.RunID, endpoint)
+	if err := c.sendJSON(ctx, http.MethodPost, apiURL, map[string]bool{"enable_debug_logging": args.Debug}, nil); err != nil {
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Re-running %s of workflow run %d of %s/%s\n", jobs, args.RunID, args.Owner, args.Repo)},
+		},
+	}, nil, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkKeepsCharactersWhole(t *testing.T) {
+	data := []byte("héllo wörld") // é and ö are 2 bytes long
+	for _, tt := range []struct {
+		offset, max int
+		want        string
+		next        string
+	}{
+		{0, 2, "h", "offset=1 "},
+		{0, 3, "hé", "offset=3 "},
+		{2, 3, "él", "offset=4 "},
+		{1, 1, "é", "offset=3 "},
+		{8, 10, "örld", ""},
+		{9, 10, "örld", ""},
+	} {
+		got := chunk(data, tt.offset, tt.max)
+		text := got
+		if _, after, ok := strings.Cut(text, "...]\n"); ok && tt.offset > 0 {
+			text = after
+		}
+		text, _, _ = strings.Cut(text, "\n[...")
+		if text != tt.want || !utf8.ValidString(got) {
+			t.Errorf("chunk(%q, %d, %d) = %q, want %q", data, tt.offset, tt.max, got, tt.want)
+		}
+		if tt.next != "" && !strings.Contains(got, tt.next) {
+			t.Errorf("chunk(%q, %d, %d) = %q, want it to resume at %s", data, tt.offset, tt.max, got, tt.next)
+		}
+	}
+}
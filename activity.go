@@ -0,0 +1,295 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxReviewedPulls caps how many pull requests updated during the window
+// have their reviews fetched, most recently updated first.
+const maxReviewedPulls = 300
+
+var contributorActivityTool = &mcp.Tool{
+	Name:        "contributor-activity",
+	Description: "A tool to report the commits, pull requests opened and merged, and reviews of each contributor to a Github repository, or to every repository of an organization, over a time window",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository, or the organization or user to report on (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl). Omit it to report on every repository of the owner",
+			},
+			"since": {
+				Type:        "string",
+				Description: "Start of the window (e.g., 2024-01-31 or 2024-01-31T15:04:05Z, defaults to 30 days ago)",
+			},
+			"until": {
+				Type:        "string",
+				Description: "End of the window (e.g., 2024-01-31 or 2024-01-31T15:04:05Z, defaults to now)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type ContributorActivityArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo,omitempty"`
+	Since string `json:"since,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+type ContributorActivity struct {
+	Login              string `json:"login"`
+	Commits            int    `json:"commits"`
+	PullRequestsOpened int    `json:"pull_requests_opened"`
+	PullRequestsMerged int    `json:"pull_requests_merged"`
+	Reviews            int    `json:"reviews"`
+}
+
+func (a ContributorActivity) total() int {
+	return a.Commits + a.PullRequestsOpened + a.PullRequestsMerged + a.Reviews
+}
+
+type ContributorActivityOutput struct {
+	// Scope is the repository (owner/repo) or account reported on.
+	Scope        string                `json:"scope"`
+	Since        time.Time             `json:"since"`
+	Until        time.Time             `json:"until"`
+	Contributors []ContributorActivity `json:"contributors"`
+	// ReviewsPartial is set when only the reviews of the maxReviewedPulls
+	// most recently updated pull requests were counted.
+	ReviewsPartial bool `json:"reviews_partial,omitempty"`
+}
+
+type pullReview struct {
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+func (c *GithubClient) ContributorActivity(ctx context.Context, req *mcp.CallToolRequest, args ContributorActivityArgs) (*mcp.CallToolResult, ContributorActivityOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose activity should be reported?", ownerField(&args.Owner)); err != nil {
+		return nil, ContributorActivityOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, ContributorActivityOutput{}, fmt.Errorf("owner is required")
+	}
+	until := time.Now().UTC().Truncate(time.Minute)
+	since := until.AddDate(0, 0, -30)
+	for _, date := range []struct {
+		name, value string
+		t           *time.Time
+	}{{"since", args.Since, &since}, {"until", args.Until, &until}} {
+		if date.value == "" {
+			continue
+		}
+		t, err := parseDate(date.value)
+		if err != nil {
+			return nil, ContributorActivityOutput{}, fmt.Errorf("invalid %s: %w", date.name, err)
+		}
+		*date.t = t.UTC()
+	}
+	if !since.Before(until) {
+		return nil, ContributorActivityOutput{}, fmt.Errorf("since must be before until")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+
+	scope := args.Owner
+	repos := []string{args.Repo}
+	qualifier := "repo:" + args.Owner + "/" + args.Repo
+	if args.Repo != "" {
+		scope = args.Owner + "/" + args.Repo
+	} else {
+		accountType, err := c.accountType(ctx, baseURL, args.Owner)
+		if err != nil {
+			return nil, ContributorActivityOutput{}, err
+		}
+		qualifier = "org:" + args.Owner
+		if accountType == "user" {
+			qualifier = "user:" + args.Owner
+		}
+		ownerRepos, err := c.accountRepositories(ctx, baseURL, args.Owner, accountType, nil)
+		if err != nil {
+			return nil, ContributorActivityOutput{}, err
+		}
+		// Repositories not pushed to since the window started have no
+		// commits in it.
+		repos = nil
+		for _, r := range ownerRepos {
+			if !r.PushedAt.Before(since) {
+				repos = append(repos, r.Name)
+			}
+		}
+	}
+	window := since.Format(time.RFC3339) + ".." + until.Format(time.RFC3339)
+
+	var mu sync.Mutex
+	byLogin := map[string]*ContributorActivity{}
+	count := func(login string, add func(*ContributorActivity)) {
+		mu.Lock()
+		defer mu.Unlock()
+		if byLogin[login] == nil {
+			byLogin[login] = &ContributorActivity{Login: login}
+		}
+		add(byLogin[login])
+	}
+
+	// Commits are listed per repository, batchWorkers at a time, while the
+	// search API finds the pull requests.
+	var opened, merged, updated []issueSearchItem
+	var updatedTotal int
+	errs := make([]error, len(repos)+3)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, repo := range repos {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			query := url.Values{}
+			query.Set("per_page", strconv.Itoa(c.perPage))
+			query.Set("since", since.Format(time.RFC3339))
+			query.Set("until", until.Format(time.RFC3339))
+			commits, err := getAllPages[commit](ctx, c, fmt.Sprintf("%s/repos/%s/%s/commits?%s", baseURL, url.PathEscape(args.Owner), url.PathEscape(repo), query.Encode()))
+			if err != nil {
+				// Empty repositories have no commits to list.
+				var apiErr *apiError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+					errs[i] = fmt.Errorf("listing commits of %s/%s: %w", args.Owner, repo, err)
+				}
+				return
+			}
+			for _, cm := range commits {
+				login := cm.Commit.Author.Name
+				if cm.Author != nil && cm.Author.Login != "" {
+					login = cm.Author.Login
+				}
+				count(cmp.Or(login, "unknown"), func(a *ContributorActivity) { a.Commits++ })
+			}
+		})
+	}
+	wg.Go(func() {
+		opened, _, errs[len(repos)] = c.searchAllIssues(ctx, baseURL, qualifier+" is:pr created:"+window)
+	})
+	wg.Go(func() {
+		merged, _, errs[len(repos)+1] = c.searchAllIssues(ctx, baseURL, qualifier+" is:pr merged:"+window)
+	})
+	wg.Go(func() {
+		updated, updatedTotal, errs[len(repos)+2] = c.searchAllIssues(ctx, baseURL, qualifier+" is:pr updated:"+window+" sort:updated-desc")
+	})
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, ContributorActivityOutput{}, err
+	}
+	for _, pr := range opened {
+		count(pr.User.Login, func(a *ContributorActivity) { a.PullRequestsOpened++ })
+	}
+	for _, pr := range merged {
+		count(pr.User.Login, func(a *ContributorActivity) { a.PullRequestsMerged++ })
+	}
+
+	// Reviews can only be listed per pull request. Authors answering
+	// reviews on their own pull request don't count as reviewing.
+	partial := updatedTotal > len(updated) || len(updated) > maxReviewedPulls
+	updated = updated[:min(len(updated), maxReviewedPulls)]
+	errs = make([]error, len(updated))
+	for i, pr := range updated {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			repo := repoFromAPIURL(pr.RepositoryURL)
+			reviews, err := getAllPages[pullReview](ctx, c, fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=%d", baseURL, repo, pr.Number, c.perPage))
+			if err != nil {
+				errs[i] = fmt.Errorf("listing reviews of %s#%d: %w", repo, pr.Number, err)
+				return
+			}
+			for _, r := range reviews {
+				if r.User == nil || r.User.Login == pr.User.Login || r.SubmittedAt.Before(since) || r.SubmittedAt.After(until) {
+					continue
+				}
+				count(r.User.Login, func(a *ContributorActivity) { a.Reviews++ })
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, ContributorActivityOutput{}, err
+	}
+
+	out := ContributorActivityOutput{
+		Scope:          scope,
+		Since:          since,
+		Until:          until,
+		Contributors:   []ContributorActivity{},
+		ReviewsPartial: partial,
+	}
+	for _, a := range byLogin {
+		out.Contributors = append(out.Contributors, *a)
+	}
+	slices.SortFunc(out.Contributors, func(a, b ContributorActivity) int {
+		return cmp.Or(cmp.Compare(b.total(), a.total()), strings.Compare(a.Login, b.Login))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Activity in %s from %s to %s:\n", scope, since.Format(time.RFC3339), until.Format(time.RFC3339))
+	for _, a := range out.Contributors {
+		fmt.Fprintf(&result, "%s: %d commits, %d pull requests opened, %d merged, %d reviews\n",
+			a.Login, a.Commits, a.PullRequestsOpened, a.PullRequestsMerged, a.Reviews)
+	}
+	if partial {
+		fmt.Fprintf(&result, "Only the reviews of the %d most recently updated pull requests were counted\n", len(updated))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// searchAllIssues pages through the issues and pull requests matching query,
+// up to the 1000 results the search API returns, and also returns how many
+// matched in total.
+func (c *GithubClient) searchAllIssues(ctx context.Context, baseURL, query string) ([]issueSearchItem, int, error) {
+	perPage := min(c.perPage, 100)
+	all := []issueSearchItem{}
+	total := 0
+	for page := 1; page <= c.maxPages && page*perPage <= 1000; page++ {
+		var found struct {
+			TotalCount int               `json:"total_count"`
+			Items      []issueSearchItem `json:"items"`
+		}
+		q := url.Values{"q": {query}, "per_page": {strconv.Itoa(perPage)}, "page": {strconv.Itoa(page)}}
+		if err := c.search(ctx, baseURL, "issues", q, "", &found); err != nil {
+			return nil, 0, err
+		}
+		all = append(all, found.Items...)
+		total = found.TotalCount
+		if len(found.Items) < perPage || len(all) >= total {
+			break
+		}
+	}
+	return all, total, nil
+}
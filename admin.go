@@ -0,0 +1,80 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// serveAdmin serves the metrics and the probes on addr, apart from the MCP
+// clients, and with debug the pprof profiles and the expvar variables of the
+// process. The command line is left out of both, as it can hold the token.
+func serveAdmin(addr string, metrics http.Handler, debug bool, gh *GithubClient) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics)
+	addProbes(mux, gh)
+	if debug {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		publishVars(gh)
+		mux.HandleFunc("GET /debug/vars", serveVars)
+	}
+	slog.Info("Admin endpoint listening", "url", "http://"+addr, "debug", debug)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		slog.Error("Admin endpoint failed", "err", err)
+	}
+}
+
+// publishVars adds the state of the server to the variables expvar
+// serves next to memstats.
+func publishVars(gh *GithubClient) {
+	started := time.Now()
+	expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+	expvar.Publish("uptime_seconds", expvar.Func(func() any { return int(time.Since(started).Seconds()) }))
+	expvar.Publish("response_cache", expvar.Func(func() any {
+		if gh.cache == nil {
+			return CacheStats{}
+		}
+		return gh.cache.Stats()
+	}))
+}
+
+// serveVars is expvar.Handler without the cmdline variable, which holds
+// the --github-token flag when the token is passed that way.
+func serveVars(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fmt.Fprint(w, "{")
+	first := true
+	expvar.Do(func(kv expvar.KeyValue) {
+		if kv.Key == "cmdline" {
+			return
+		}
+		if !first {
+			fmt.Fprint(w, ",")
+		}
+		first = false
+		fmt.Fprintf(w, "\n%q: %s", kv.Key, kv.Value)
+	})
+	fmt.Fprint(w, "\n}\n")
+}
+
+// isLoopback reports whether addr, a host:port, only listens on the
+// loopback interface. An empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeVarsLeavesOutCmdline(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveVars(rec, httptest.NewRequest("GET", "/debug/vars", nil))
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	if _, ok := vars["cmdline"]; ok {
+		t.Error("cmdline is served")
+	}
+	if _, ok := vars["memstats"]; !ok {
+		t.Error("memstats is not served")
+	}
+}
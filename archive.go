@@ -0,0 +1,362 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxArchiveBytes bounds the download of a repository archive,
+	// maxExtractedBytes the files extracted from it.
+	maxArchiveBytes   = 512 << 20
+	maxExtractedBytes = 2 << 30
+	// defaultArchiveFiles is the number of extracted files listed when the
+	// limit argument is omitted.
+	defaultArchiveFiles = 1000
+)
+
+var downloadArchiveTool = &mcp.Tool{
+	Name:        "download-archive",
+	Description: "A tool to download a Github repository at a ref as a tarball or zipball and extract it to a local scratch directory, returning the local path and the extracted files so they can be analyzed offline",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to download (defaults to the default branch)",
+			},
+			"format": {
+				Type:        "string",
+				Description: "Archive format to download (defaults to tarball)",
+				Enum:        []any{"tarball", "zipball"},
+			},
+			"limit": {
+				Type:        "integer",
+				Description: fmt.Sprintf("Maximum number of extracted files to list (defaults to %d), all of them are extracted regardless", defaultArchiveFiles),
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(10000.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type DownloadArchiveArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Ref    string `json:"ref,omitempty"`
+	Format string `json:"format,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+type ArchiveFile struct {
+	// Path is relative to the directory the archive was extracted to.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+type DownloadArchiveOutput struct {
+	// Path is the local directory holding the repository files.
+	Path string `json:"path"`
+	// Commit is the abbreviated SHA of the commit the archive was made of.
+	Commit     string        `json:"commit"`
+	Files      []ArchiveFile `json:"files"`
+	TotalFiles int           `json:"total_files"`
+	TotalBytes int64         `json:"total_bytes"`
+	// Truncated is set when more files were extracted than listed.
+	Truncated bool `json:"truncated"`
+	// Evicted are the owner/repo/commit directories of the archives
+	// removed to make room.
+	Evicted []string `json:"evicted"`
+}
+
+func (c *GithubClient) DownloadArchive(ctx context.Context, req *mcp.CallToolRequest, args DownloadArchiveArgs) (*mcp.CallToolResult, DownloadArchiveOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be downloaded?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, DownloadArchiveOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	// Both name directories of the scratch directory.
+	if !isPathSegment(args.Owner) || !isPathSegment(args.Repo) {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("invalid repository %s/%s", args.Owner, args.Repo)
+	}
+	format := cmp.Or(args.Format, "tarball")
+	if format != "tarball" && format != "zipball" {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("format must be tarball or zipball, got %q", format)
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultArchiveFiles
+	}
+
+	archiveURL := fmt.Sprintf("%s/repos/%s/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), format)
+	if args.Ref != "" {
+		archiveURL += "/" + escapePath(args.Ref)
+	}
+
+	// Extract next to the final directory and move it in place once
+	// complete, so a failed download never leaves a partial tree behind.
+	repoDir := filepath.Join(c.scratchDir, args.Owner, args.Repo)
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	// Archives can be far larger than what the server should hold in
+	// memory, so they are streamed to a file.
+	archive, err := os.CreateTemp(repoDir, ".archive-*")
+	if err != nil {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+	size, err := c.downloadTo(ctx, archive, archiveURL, "", maxArchiveBytes)
+	if err != nil {
+		return nil, DownloadArchiveOutput{}, err
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return nil, DownloadArchiveOutput{}, err
+	}
+	tmp, err := os.MkdirTemp(repoDir, ".download-*")
+	if err != nil {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	x := &extractor{ctx: ctx, dir: tmp}
+	if format == "tarball" {
+		err = x.tarball(archive)
+	} else {
+		err = x.zipball(archive, size)
+	}
+	if err != nil {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("failed to extract %s: %w", format, err)
+	}
+
+	if x.top == "" {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("the %s of %s/%s is empty", format, args.Owner, args.Repo)
+	}
+	// GitHub names the top level directory owner-repo-sha.
+	commit := x.top[strings.LastIndex(x.top, "-")+1:]
+	if !isPathSegment(commit) {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("unexpected top level directory %q in the %s", x.top, format)
+	}
+	dest := filepath.Join(repoDir, commit)
+	if !isWithin(c.scratchDir, dest) {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("%s is outside the scratch directory", dest)
+	}
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return nil, DownloadArchiveOutput{}, err
+	}
+	c.scratchMu.Lock()
+	err = os.RemoveAll(dest)
+	if err == nil {
+		err = os.Rename(tmp, dest)
+	}
+	c.scratchMu.Unlock()
+	if err != nil {
+		return nil, DownloadArchiveOutput{}, fmt.Errorf("failed to move archive to %s: %w", dest, err)
+	}
+	if err := touchClone(dest); err != nil {
+		return nil, DownloadArchiveOutput{}, err
+	}
+	evicted, err := c.evictArchives(dest)
+	if err != nil {
+		return nil, DownloadArchiveOutput{}, err
+	}
+
+	slices.SortFunc(x.files, func(a, b ArchiveFile) int { return strings.Compare(a.Path, b.Path) })
+	out := DownloadArchiveOutput{Path: dest, Commit: commit, Files: x.files[:min(limit, len(x.files))], TotalFiles: len(x.files), TotalBytes: x.total, Evicted: evicted}
+	out.Truncated = len(out.Files) < out.TotalFiles
+	var result strings.Builder
+	fmt.Fprintf(&result, "Extracted %d files (%d bytes) of %s/%s@%s (%s) to %s:\n", out.TotalFiles, out.TotalBytes, args.Owner, args.Repo, cmp.Or(args.Ref, "default branch"), commit, dest)
+	for _, f := range out.Files {
+		fmt.Fprintf(&result, "%s (%d bytes)\n", f.Path, f.Size)
+	}
+	if out.Truncated {
+		fmt.Fprintf(&result, "... and %d more files\n", out.TotalFiles-len(out.Files))
+	}
+	if len(out.Evicted) > 0 {
+		fmt.Fprintf(&result, "Removed %d least recently downloaded archives to stay within the scratch directory size: %s\n", len(out.Evicted), strings.Join(out.Evicted, ", "))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// evictArchives removes the least recently downloaded archives other than
+// keep until they fit in scratchMaxBytes, and returns their directories
+// relative to the scratch directory. keep itself is removed when it alone is
+// too large.
+func (c *GithubClient) evictArchives(keep string) ([]string, error) {
+	c.scratchMu.Lock()
+	defer c.scratchMu.Unlock()
+	dirs, fits, err := evictLRU(filepath.Join(c.scratchDir, "*", "*", "*"), c.scratchMaxBytes, keep)
+	evicted := []string{}
+	for _, dir := range dirs {
+		rel, relErr := filepath.Rel(c.scratchDir, dir)
+		if relErr != nil {
+			rel = dir
+		}
+		evicted = append(evicted, filepath.ToSlash(rel))
+	}
+	if err != nil {
+		return evicted, fmt.Errorf("failed to evict archive: %w", err)
+	}
+	if !fits {
+		return evicted, fmt.Errorf("the archive is larger than the %d bytes of the scratch directory", c.scratchMaxBytes)
+	}
+	return evicted, nil
+}
+
+// isPathSegment reports whether name can be used as a single directory
+// name: it isn't empty, . or .., and has no separator.
+func isPathSegment(name string) bool {
+	return name != "." && filepath.IsLocal(name) && !strings.ContainsAny(name, `/\`)
+}
+
+// isWithin reports whether path is below dir.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && filepath.IsLocal(rel)
+}
+
+// extractor writes the entries of a repository archive below dir, without
+// the top level directory every entry of a GitHub archive is in.
+type extractor struct {
+	ctx   context.Context
+	dir   string
+	top   string
+	files []ArchiveFile
+	total int64
+}
+
+func (x *extractor) tarball(r io.Reader) error {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Links, and the header holding the commit SHA, are skipped.
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = x.add(hdr.Name, true, 0, nil)
+		case tar.TypeReg:
+			err = x.add(hdr.Name, false, hdr.FileInfo().Mode(), tr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (x *extractor) zipball(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.add(f.Name, true, 0, nil)
+		case mode.IsRegular():
+			var rc io.ReadCloser
+			if rc, err = f.Open(); err != nil {
+				return err
+			}
+			err = x.add(f.Name, false, mode, rc)
+			rc.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add extracts one archive entry, refusing names that would end up outside
+// of dir.
+func (x *extractor) add(name string, dir bool, mode fs.FileMode, r io.Reader) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	top, rest, _ := strings.Cut(name, "/")
+	if x.top == "" {
+		x.top = top
+	} else if top != x.top {
+		return fmt.Errorf("entry %s is outside of %s", name, x.top)
+	}
+	rest = strings.TrimSuffix(rest, "/")
+	if rest == "" {
+		return nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(rest)) {
+		return fmt.Errorf("entry %s has an unsafe path", name)
+	}
+	path := filepath.Join(x.dir, filepath.FromSlash(rest))
+	if dir {
+		return os.MkdirAll(path, 0o755)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	perm := fs.FileMode(0o644)
+	if mode&0o111 != 0 {
+		perm = 0o755
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, maxExtractedBytes-x.total+1))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	x.total += n
+	if x.total > maxExtractedBytes {
+		return fmt.Errorf("archive extracts to more than %d bytes", maxExtractedBytes)
+	}
+	x.files = append(x.files, ArchiveFile{Path: rest, Size: n})
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestIsPathSegment(t *testing.T) {
+	for name, want := range map[string]bool{
+		"kubectl":  true,
+		"a1b2c3d":  true,
+		".github":  true,
+		"":         false,
+		".":        false,
+		"..":       false,
+		"a/b":      false,
+		`a\b`:      false,
+		"/etc":     false,
+		"../other": false,
+	} {
+		if got := isPathSegment(name); got != want {
+			t.Errorf("isPathSegment(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	dir := filepath.FromSlash("/scratch")
+	for path, want := range map[string]bool{
+		"/scratch/o/r/abc": true,
+		"/scratch":         false,
+		"/scratch/../etc":  false,
+		"/scratchy/o":      false,
+		"/etc":             false,
+	} {
+		if got := isWithin(dir, filepath.FromSlash(path)); got != want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", dir, path, got, want)
+		}
+	}
+}
+
+// tarball returns a gzipped tar of a GitHub archive of commit holding a
+// single file of size bytes.
+func tarball(t *testing.T, commit string, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	top := "o-r-" + commit + "/"
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: top, Mode: 0o755})
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: top + "data", Mode: 0o644, Size: int64(size)})
+	tw.Write(bytes.Repeat([]byte("x"), size))
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloadArchiveEvictsLeastRecent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/o/r/tarball/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tarball(t, r.PathValue("ref"), 600))
+	})
+	dir := t.TempDir()
+	gh := newTestClient(t, mux, GithubClientOptions{ScratchDir: dir, ScratchMaxBytes: 1000})
+	download := func(ref string) *mcp.CallToolResult {
+		return callTool(t, func(s *mcp.Server) { addTool(s, downloadArchiveTool, gh.DownloadArchive) }, "download-archive", map[string]any{
+			"owner": "o", "repo": "r", "ref": ref,
+		})
+	}
+
+	if res := download("aaa111"); res.IsError {
+		t.Fatal(resultText(res))
+	}
+	// Modification times may have a coarse resolution.
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, "o", "r", "aaa111"), old, old)
+	res := download("bbb222")
+	if res.IsError {
+		t.Fatal(resultText(res))
+	}
+	if text := resultText(res); !strings.Contains(text, "o/r/aaa111") {
+		t.Errorf("second download = %q, want o/r/aaa111 evicted", text)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "o", "r", "aaa111")); !os.IsNotExist(err) {
+		t.Errorf("evicted archive still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "o", "r", "bbb222", "data")); err != nil {
+		t.Errorf("downloaded archive is missing: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "o", "r"))
+	if len(entries) != 1 {
+		t.Errorf("scratch directory holds %v, want only the last archive", entries)
+	}
+
+	mux.HandleFunc("GET /repos/o/r/tarball", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tarball(t, "ccc333", 2000))
+	})
+	if res := download(""); !res.IsError || !strings.Contains(resultText(res), "larger than") {
+		t.Errorf("download of an oversized archive = %q, want an error", resultText(res))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "o", "r", "ccc333")); !os.IsNotExist(err) {
+		t.Errorf("oversized archive was kept: %v", err)
+	}
+}
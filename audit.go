@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxAuditValueBytes cuts the long strings of the audited arguments, like
+// file contents and issue bodies, down to their beginning.
+const maxAuditValueBytes = 256
+
+// secretArgument matches the names of the arguments whose values are never
+// written to the audit log.
+var secretArgument = regexp.MustCompile(`(?i)token|secret|password|passphrase|private_key|credential`)
+
+// auditLog appends a JSON line for every tool call to a file. A nil
+// auditLog records nothing.
+type auditLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+type auditEntry struct {
+	Time    time.Time    `json:"time"`
+	Session string       `json:"session,omitempty"`
+	Client  *auditClient `json:"client,omitempty"`
+	Tool    string       `json:"tool"`
+	// Arguments are the arguments of the call, sanitized: secrets are
+	// redacted and long strings cut.
+	Arguments any `json:"arguments"`
+	// Status is ok or error, with the error and its class.
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	ErrorClass string `json:"error_class,omitempty"`
+	// DryRun is set when nothing was changed, the call only reporting the
+	// requests it would send.
+	DryRun     bool    `json:"dry_run,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+type auditClient struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// openAuditLog opens the audit log at path for appending, creating it if
+// needed.
+func openAuditLog(path string) (*auditLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{f: f}, nil
+}
+
+func (a *auditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+	return a.f.Close()
+}
+
+// middleware audits the tool calls.
+func (a *auditLog) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if a == nil || !ok {
+			return next(ctx, method, req)
+		}
+		ctx, tc := withToolCall(ctx)
+		start := time.Now()
+		res, err := next(ctx, method, req)
+		entry := auditEntry{
+			Time:       start.UTC(),
+			Tool:       call.Params.Name,
+			Arguments:  sanitizeArguments(call.Params.Arguments),
+			Status:     "ok",
+			DryRun:     tc.dryRun,
+			DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if call.Session != nil {
+			entry.Session = call.Session.ID()
+			if p := call.Session.InitializeParams(); p != nil && p.ClientInfo != nil {
+				entry.Client = &auditClient{Name: p.ClientInfo.Name, Version: p.ClientInfo.Version}
+			}
+		}
+		callErr := err
+		if r, ok := res.(*mcp.CallToolResult); ok && r.IsError && err == nil {
+			callErr = tc.err
+			entry.Status = "error"
+			if callErr == nil {
+				entry.ErrorClass = "tool"
+				for _, c := range r.Content {
+					if text, ok := c.(*mcp.TextContent); ok {
+						entry.Error = text.Text
+						break
+					}
+				}
+			}
+		}
+		if callErr != nil {
+			entry.Status, entry.Error, entry.ErrorClass = "error", callErr.Error(), errorClass(callErr)
+		}
+		entry.Error = clipValue(redact(entry.Error))
+		a.write(entry)
+		return res, err
+	}
+}
+
+func (a *auditLog) write(entry auditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		slog.Error("Encoding audit log entry failed", "tool", entry.Tool, "err", err)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// One write per entry, so that O_APPEND keeps lines whole.
+	if _, err := a.f.Write(append(line, '\n')); err != nil {
+		slog.Error("Writing audit log failed", "tool", entry.Tool, "err", err)
+	}
+}
+
+// sanitizeArguments returns the arguments of a call with the values of
+// secret arguments, and the credentials found in the others, redacted and
+// long strings cut.
+func sanitizeArguments(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return map[string]any{}
+	}
+	var args any
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return fmt.Sprintf("[invalid JSON, %d bytes]", len(raw))
+	}
+	return sanitizeValue("", args)
+}
+
+func sanitizeValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = sanitizeValue(k, item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = sanitizeValue(key, item)
+		}
+		return out
+	case string:
+		if secretArgument.MatchString(key) {
+			return "[REDACTED]"
+		}
+		return clipValue(redact(v))
+	}
+	return v
+}
+
+func clipValue(s string) string {
+	if len(s) <= maxAuditValueBytes {
+		return s
+	}
+	return fmt.Sprintf("%s[... %d bytes]", strings.ToValidUTF8(s[:maxAuditValueBytes], ""), len(s))
+}
+
+// redact replaces the credentials in s, as in the wire log.
+func redact(s string) string {
+	for _, red := range redactions {
+		s = red.re.ReplaceAllString(s, red.repl)
+	}
+	return s
+}
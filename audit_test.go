@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSanitizeArguments(t *testing.T) {
+	token := "ghp_" + strings.Repeat("x", 36)
+	long := strings.Repeat("é", maxAuditValueBytes)
+	raw, _ := json.Marshal(map[string]any{
+		"owner":          "octo",
+		"github_token":   "hunter2",
+		"client_secret":  []any{"a", "b"},
+		"body":           "use " + token + " to log in",
+		"content":        long,
+		"per_page":       30,
+		"labels":         []any{"bug", token},
+		"nested":         map[string]any{"Password": "p", "draft": true},
+		"private_key_id": 7,
+	})
+	want := map[string]any{
+		"owner":          "octo",
+		"github_token":   "[REDACTED]",
+		"client_secret":  []any{"[REDACTED]", "[REDACTED]"},
+		"body":           "use [REDACTED] to log in",
+		"content":        strings.Repeat("é", maxAuditValueBytes/2) + "[... 512 bytes]",
+		"per_page":       30.0,
+		"labels":         []any{"bug", "[REDACTED]"},
+		"nested":         map[string]any{"Password": "[REDACTED]", "draft": true},
+		"private_key_id": 7.0,
+	}
+	if got := sanitizeArguments(raw); !reflect.DeepEqual(got, want) {
+		t.Errorf("sanitizeArguments = %#v, want %#v", got, want)
+	}
+
+	if got := sanitizeArguments(nil); !reflect.DeepEqual(got, map[string]any{}) {
+		t.Errorf("sanitizeArguments(nil) = %#v, want an empty object", got)
+	}
+	if got := sanitizeArguments(json.RawMessage(`{"owner":`)); got != "[invalid JSON, 9 bytes]" {
+		t.Errorf("sanitizeArguments of invalid JSON = %#v", got)
+	}
+}
+
+func TestAuditLogRecordsCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audit.Close()
+	gh := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	}), GithubClientOptions{})
+
+	res := callTool(t, func(s *mcp.Server) {
+		s.AddReceivingMiddleware(audit.middleware)
+		addTool(s, getFileContentsTool, gh.GetFileContents)
+	}, "get-file-contents", map[string]any{"owner": "o", "repo": "r", "path": "missing.md", "github_token": "hunter2"})
+	if !res.IsError {
+		t.Fatalf("get-file-contents = %q, want an error", resultText(res))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("audit log holds a secret: %s", data)
+	}
+	var entry auditEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("audit log %q: %v", data, err)
+	}
+	if entry.Tool != "get-file-contents" || entry.Status != "error" || entry.ErrorClass != "not_found" || entry.Client == nil {
+		t.Errorf("audit entry = %+v", entry)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenSource supplies the token sent to GitHub with each request.
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// staticToken is a personal access token, or any other token that doesn't
+// need to be refreshed.
+type staticToken string
+
+func (t staticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// appTokenSource authenticates as a GitHub App installation. It signs a JWT
+// with the app's private key, exchanges it for an installation access token
+// and mints a new one shortly before the current one expires.
+type appTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	baseURL        string
+	httpClient     *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newAppTokenSource returns a token source for the installation of app appID.
+// When installationID is zero the app must have exactly one installation,
+// which is looked up on first use.
+func newAppTokenSource(appID, installationID int64, privateKeyPEM []byte, baseURL string) (*appTokenSource, error) {
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &appTokenSource{
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		httpClient:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *appTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Installation tokens are valid for an hour, refresh them with some
+	// margin so a token doesn't expire halfway through a tool call.
+	if s.token != "" && time.Until(s.expires) > 5*time.Minute {
+		return s.token, nil
+	}
+	if s.installationID == 0 {
+		id, err := s.findInstallation(ctx)
+		if err != nil {
+			return "", err
+		}
+		s.installationID = id
+	}
+
+	var out struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	apiURL := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.baseURL, s.installationID)
+	if err := s.appRequest(ctx, http.MethodPost, apiURL, &out); err != nil {
+		return "", fmt.Errorf("creating installation token: %w", err)
+	}
+	s.token, s.expires = out.Token, out.ExpiresAt
+	return s.token, nil
+}
+
+// findInstallation returns the ID of the app's only installation.
+func (s *appTokenSource) findInstallation(ctx context.Context) (int64, error) {
+	var installations []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := s.appRequest(ctx, http.MethodGet, s.baseURL+"/app/installations", &installations); err != nil {
+		return 0, fmt.Errorf("listing app installations: %w", err)
+	}
+	switch len(installations) {
+	case 0:
+		return 0, fmt.Errorf("GitHub App %d is not installed anywhere", s.appID)
+	case 1:
+		return installations[0].ID, nil
+	}
+	var accounts []string
+	for _, i := range installations {
+		accounts = append(accounts, fmt.Sprintf("%s (%d)", i.Account.Login, i.ID))
+	}
+	return 0, fmt.Errorf("GitHub App %d has several installations, pick one with github_app_installation_id: %s", s.appID, strings.Join(accounts, ", "))
+}
+
+// appRequest calls an endpoint authenticated as the app itself and decodes
+// the response into v.
+func (s *appTokenSource) appRequest(ctx context.Context, method, url string, v any) error {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwt returns the RS256 signed JSON Web Token identifying the app. It is
+// backdated a minute to allow for clock drift, and GitHub accepts at most
+// ten minutes of validity.
+func (s *appTokenSource) jwt(now time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": fmt.Sprint(s.appID),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing app JWT: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// parseRSAPrivateKey decodes a PEM encoded RSA key, either PKCS #1 as
+// downloaded from GitHub or PKCS #8.
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
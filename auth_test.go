@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func testAppKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// verifyAppJWT checks the signature of jwt with key and returns its claims.
+func verifyAppJWT(t *testing.T, key *rsa.PrivateKey, jwt string) map[string]any {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT %q doesn't have three parts", jwt)
+	}
+	enc := base64.RawURLEncoding
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("JWT signature: %v", err)
+	}
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestAppJWT(t *testing.T) {
+	key := testAppKey(t)
+	s := &appTokenSource{appID: 42, key: key}
+	now := time.Unix(1_700_000_000, 0)
+	jwt, err := s.jwt(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := verifyAppJWT(t, key, jwt)
+	if claims["iss"] != "42" {
+		t.Errorf("iss = %v, want 42", claims["iss"])
+	}
+	iat, exp := int64(claims["iat"].(float64)), int64(claims["exp"].(float64))
+	if iat != now.Unix()-60 || exp != now.Unix()+540 {
+		t.Errorf("iat %d and exp %d, want a minute before and nine after %d", iat, exp, now.Unix())
+	}
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key := testAppKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, block := range map[string]*pem.Block{
+		"PKCS #1": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		"PKCS #8": {Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		parsed, err := parseRSAPrivateKey(pem.EncodeToMemory(block))
+		if err != nil || !parsed.Equal(key) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := parseRSAPrivateKey([]byte("not a key")); err == nil {
+		t.Error("parsed a key that isn't PEM encoded")
+	}
+}
+
+func TestAppTokenSourceRefreshes(t *testing.T) {
+	key := testAppKey(t)
+	var lookups, minted int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/installations", func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		verifyAppJWT(t, key, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		jsonHandler(`[{"id":7,"account":{"login":"o"}}]`)(w, r)
+	})
+	mux.HandleFunc("POST /app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		minted++
+		verifyAppJWT(t, key, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		// The first token is about to expire, the second one isn't.
+		expires := time.Now().Add(time.Minute)
+		if minted > 1 {
+			expires = time.Now().Add(time.Hour)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":%q}`, minted, expires.Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /orgs/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghs_2" {
+			t.Errorf("listing sent with %q, want the installation token", got)
+		}
+		jsonHandler(`[]`)(w, r)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	s, err := newAppTokenSource(42, 0, keyPEM, srv.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []string{"ghs_1", "ghs_2", "ghs_2"} {
+		token, err := s.Token(context.Background())
+		if err != nil || token != want {
+			t.Errorf("Token() #%d = %q, %v, want %q", i+1, token, err, want)
+		}
+	}
+	if lookups != 1 || minted != 2 {
+		t.Errorf("%d installation lookups and %d tokens minted, want 1 and 2", lookups, minted)
+	}
+
+	gh := NewGithubClient(&GithubClientOptions{BaseURL: srv.URL, TokenSource: s})
+	res := callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+		"name": "o", "account_type": "org",
+	})
+	if res.IsError {
+		t.Error(resultText(res))
+	}
+}
+
+func TestAppTokenSourceNeedsOneInstallation(t *testing.T) {
+	key := testAppKey(t)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	for body, want := range map[string]string{
+		`[]`: "not installed anywhere",
+		`[{"id":1,"account":{"login":"a"}},{"id":2,"account":{"login":"b"}}]`: "a (1), b (2)",
+	} {
+		srv := httptest.NewServer(jsonHandler(body))
+		s, err := newAppTokenSource(42, 0, keyPEM, srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Token(context.Background()); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("installations %s: %v, want an error mentioning %q", body, err, want)
+		}
+		srv.Close()
+	}
+}
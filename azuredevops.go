@@ -0,0 +1,366 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// azureDevOpsURL is the root of Azure DevOps Services. Azure DevOps Server
+// collections live at https://<host>/<collection>.
+const azureDevOpsURL = "https://dev.azure.com"
+
+// azureAPIVersion is the REST API version requested by the azure provider.
+const azureAPIVersion = "7.1"
+
+// addAzureDevOps makes Azure DevOps available as the "azure" provider,
+// authenticated with a personal access token when it is set.
+func (c *GithubClient) addAzureDevOps(baseURL, token string) {
+	baseURL = strings.TrimSuffix(cmp.Or(baseURL, azureDevOpsURL), "/")
+	var hosts []string
+	var sitePath string
+	if u, err := url.Parse(baseURL); err == nil {
+		hosts = append(hosts, u.Hostname())
+		sitePath = u.Path
+	}
+	var authorize func(*http.Request)
+	if token != "" {
+		// A PAT is sent as the password of a user without name.
+		basic := "Basic " + base64.StdEncoding.EncodeToString([]byte(":"+token))
+		authorize = func(req *http.Request) {
+			req.Header.Set("Authorization", basic)
+		}
+	}
+	c.addProvider("azure", forgeProvider{
+		hosts: hosts,
+		forge: func(override string) Forge {
+			return &azureForge{c: c, baseURL: cmp.Or(override, baseURL)}
+		},
+		authorize:    authorize,
+		nestedOwners: true,
+		sitePath:     sitePath,
+	})
+}
+
+// azureForge is the Forge of Azure DevOps. Repositories belong to a project
+// of an organization, so owners are given as organization/project, or only
+// as the organization when listing every repository of it. It has no
+// issues, work items living in Azure Boards, but lists pipeline runs.
+type azureForge struct {
+	c       *GithubClient
+	baseURL string
+}
+
+// projectURL returns the API root of the organization/project owner.
+func (f *azureForge) projectURL(owner string) (string, error) {
+	org, project, ok := strings.Cut(owner, "/")
+	if !ok || org == "" || project == "" {
+		return "", fmt.Errorf("owner must be an Azure DevOps organization/project, got %q", owner)
+	}
+	return fmt.Sprintf("%s/%s/%s/_apis", f.baseURL, url.PathEscape(org), url.PathEscape(project)), nil
+}
+
+func (f *azureForge) repoURL(owner, repo string) (string, error) {
+	projectURL, err := f.projectURL(owner)
+	if err != nil {
+		return "", err
+	}
+	return projectURL + "/git/repositories/" + url.PathEscape(repo), nil
+}
+
+// azurePages fetches every page of a listing paged with $top and $skip.
+func azurePages[T any](ctx context.Context, c *GithubClient, apiURL string) ([]T, error) {
+	all := []T{}
+	for page := 0; page < c.maxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return all, interrupted(ctx, &partialError{Pages: page, Items: len(all), Err: err})
+		}
+		var resp struct {
+			Value []T `json:"value"`
+		}
+		pageURL := fmt.Sprintf("%s&$top=%d&$skip=%d&api-version=%s", apiURL, c.perPage, page*c.perPage, azureAPIVersion)
+		if err := c.getJSON(ctx, pageURL, &resp); err != nil {
+			if ctx.Err() != nil && page > 0 {
+				return all, interrupted(ctx, &partialError{Pages: page, Items: len(all), Err: ctx.Err()})
+			}
+			return nil, err
+		}
+		all = append(all, resp.Value...)
+		progressFrom(ctx).update(ctx, float64(page+1), 0, fmt.Sprintf("Fetched page %d", page+1))
+		if len(resp.Value) < c.perPage {
+			break
+		}
+	}
+	return all, nil
+}
+
+type azureRepository struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	WebURL        string `json:"webUrl"`
+	DefaultBranch string `json:"defaultBranch"`
+	IsDisabled    bool   `json:"isDisabled"`
+	IsFork        bool   `json:"isFork"`
+	Project       struct {
+		Name           string    `json:"name"`
+		Visibility     string    `json:"visibility"`
+		Description    string    `json:"description"`
+		LastUpdateTime time.Time `json:"lastUpdateTime"`
+	} `json:"project"`
+}
+
+func (f *azureForge) ListRepos(ctx context.Context, owner string, opts RepoListOptions) ([]Repository, error) {
+	org, _, _ := strings.Cut(owner, "/")
+	apiURL := fmt.Sprintf("%s/%s/_apis/git/repositories", f.baseURL, url.PathEscape(org))
+	if strings.Contains(owner, "/") {
+		projectURL, err := f.projectURL(owner)
+		if err != nil {
+			return nil, err
+		}
+		apiURL = projectURL + "/git/repositories"
+	}
+	// Repositories come in a single page.
+	var resp struct {
+		Value []azureRepository `json:"value"`
+	}
+	if err := f.c.getJSON(ctx, apiURL+"?api-version="+azureAPIVersion, &resp); err != nil {
+		return nil, err
+	}
+	repos := []Repository{}
+	for _, r := range resp.Value {
+		// The owner of a repository is its organization/project, whether
+		// the whole organization or one project is listed.
+		project := org + "/" + r.Project.Name
+		if !f.c.scope.allowsRepo(project, r.Name) {
+			continue
+		}
+		private := r.Project.Visibility != "public"
+		switch {
+		case opts.Type == "forks" && !r.IsFork, opts.Type == "sources" && r.IsFork,
+			opts.Type == "public" && private, opts.Type == "private" && !private:
+			continue
+		}
+		repos = append(repos, Repository{
+			Name:     r.Name,
+			FullName: project + "/" + r.Name,
+			HTMLURL:  r.WebURL,
+			Private:  private,
+			// Only projects have a description.
+			Description: r.Project.Description,
+			Fork:        r.IsFork,
+			Archived:    r.IsDisabled,
+			PushedAt:    r.Project.LastUpdateTime,
+		})
+	}
+	return repos, nil
+}
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func (f *azureForge) GetFile(ctx context.Context, owner, repo, path, ref string) (*File, error) {
+	if err := f.c.scope.checkRepo(owner, repo); err != nil {
+		return nil, err
+	}
+	repoURL, err := f.repoURL(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("path", "/"+strings.Trim(path, "/"))
+	query.Set("$format", "octetStream")
+	query.Set("api-version", azureAPIVersion)
+	if ref != "" {
+		versionType := "branch"
+		if commitSHA.MatchString(ref) {
+			versionType = "commit"
+		}
+		query.Set("versionDescriptor.version", ref)
+		query.Set("versionDescriptor.versionType", versionType)
+	}
+	resp, err := f.c.get(ctx, repoURL+"/items?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: path, Size: len(data), Content: data}, nil
+}
+
+func (f *azureForge) ListIssues(ctx context.Context, owner, repo string, opts IssueListOptions) ([]Issue, error) {
+	return nil, fmt.Errorf("azure devops repositories have no issues, their work items live in Azure Boards")
+}
+
+type azurePullRequest struct {
+	PullRequestID int    `json:"pullRequestId"`
+	Title         string `json:"title"`
+	Status        string `json:"status"`
+	IsDraft       bool   `json:"isDraft"`
+	SourceRefName string `json:"sourceRefName"`
+	TargetRefName string `json:"targetRefName"`
+	MergeStatus   string `json:"mergeStatus"`
+	CreatedBy     struct {
+		UniqueName string `json:"uniqueName"`
+	} `json:"createdBy"`
+	Repository struct {
+		WebURL string `json:"webUrl"`
+	} `json:"repository"`
+}
+
+func (f *azureForge) ListPullRequests(ctx context.Context, owner, repo string, opts PullRequestListOptions) ([]PullRequest, error) {
+	if err := f.c.scope.checkRepo(owner, repo); err != nil {
+		return nil, err
+	}
+	repoURL, err := f.repoURL(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	status := map[string]string{"": "active", "open": "active", "closed": "all", "all": "all"}[opts.State]
+	query.Set("searchCriteria.status", cmp.Or(status, opts.State))
+	if opts.Base != "" {
+		query.Set("searchCriteria.targetRefName", "refs/heads/"+opts.Base)
+	}
+
+	raw, err := azurePages[azurePullRequest](ctx, f.c, repoURL+"/pullrequests?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	pulls := []PullRequest{}
+	for _, pr := range raw {
+		// Completed and abandoned pull requests are both closed.
+		if opts.State == "closed" && pr.Status == "active" {
+			continue
+		}
+		mergeable := "unknown"
+		if pr.Status == "active" && pr.MergeStatus != "" {
+			mergeable = pr.MergeStatus
+		}
+		pulls = append(pulls, PullRequest{
+			Number:    pr.PullRequestID,
+			Title:     pr.Title,
+			State:     cmp.Or(azurePullRequestStates[pr.Status], pr.Status),
+			Draft:     pr.IsDraft,
+			Author:    pr.CreatedBy.UniqueName,
+			Head:      strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+			Base:      strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+			URL:       fmt.Sprintf("%s/pullrequest/%d", pr.Repository.WebURL, pr.PullRequestID),
+			Mergeable: mergeable,
+		})
+	}
+	return pulls, nil
+}
+
+// azurePullRequestStates translates pull request statuses to the states used
+// for the other providers.
+var azurePullRequestStates = map[string]string{
+	"active":    "open",
+	"completed": "merged",
+	"abandoned": "closed",
+}
+
+type azureBuild struct {
+	ID            int64     `json:"id"`
+	Status        string    `json:"status"`
+	Result        string    `json:"result"`
+	Reason        string    `json:"reason"`
+	SourceBranch  string    `json:"sourceBranch"`
+	SourceVersion string    `json:"sourceVersion"`
+	QueueTime     time.Time `json:"queueTime"`
+	Definition    struct {
+		Name string `json:"name"`
+	} `json:"definition"`
+	RequestedFor struct {
+		UniqueName string `json:"uniqueName"`
+	} `json:"requestedFor"`
+	Links struct {
+		Web struct {
+			Href string `json:"href"`
+		} `json:"web"`
+	} `json:"_links"`
+}
+
+// azureRunStates translates build statuses and results to the workflow run
+// vocabulary of GitHub.
+var azureRunStates = map[string]string{
+	"inProgress":         "in_progress",
+	"notStarted":         "queued",
+	"cancelling":         "in_progress",
+	"postponed":          "queued",
+	"succeeded":          "success",
+	"partiallySucceeded": "partially_succeeded",
+	"failed":             "failure",
+	"canceled":           "cancelled",
+}
+
+// ListRuns lists the pipeline runs, builds in the API, of a repository.
+func (f *azureForge) ListRuns(ctx context.Context, args ListWorkflowRunsArgs) (*WorkflowRunListOutput, error) {
+	if err := f.c.scope.checkRepo(args.Owner, args.Repo); err != nil {
+		return nil, err
+	}
+	repoURL, err := f.repoURL(args.Owner, args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	// Builds are filtered by repository ID, not name.
+	var repo azureRepository
+	if err := f.c.getJSON(ctx, repoURL+"?api-version="+azureAPIVersion, &repo); err != nil {
+		return nil, err
+	}
+	projectURL, _ := f.projectURL(args.Owner)
+	query := url.Values{}
+	query.Set("repositoryId", repo.ID)
+	query.Set("repositoryType", "TfsGit")
+	query.Set("$top", strconv.Itoa(cmp.Or(args.Limit, defaultSearchLimit)))
+	query.Set("api-version", azureAPIVersion)
+	if args.Branch != "" {
+		query.Set("branchName", "refs/heads/"+args.Branch)
+	}
+	if args.Event != "" {
+		query.Set("reasonFilter", args.Event)
+	}
+
+	var resp struct {
+		Value []azureBuild `json:"value"`
+	}
+	if err := f.c.getJSON(ctx, projectURL+"/build/builds?"+query.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	out := &WorkflowRunListOutput{
+		Repository:   args.Owner + "/" + args.Repo,
+		WorkflowRuns: []WorkflowRun{},
+	}
+	for _, b := range resp.Value {
+		run := WorkflowRun{
+			ID:         b.ID,
+			Name:       b.Definition.Name,
+			Event:      b.Reason,
+			Status:     cmp.Or(azureRunStates[b.Status], b.Status),
+			Conclusion: cmp.Or(azureRunStates[b.Result], b.Result),
+			Branch:     strings.TrimPrefix(b.SourceBranch, "refs/heads/"),
+			HeadSHA:    b.SourceVersion,
+			Actor:      b.RequestedFor.UniqueName,
+			CreatedAt:  b.QueueTime,
+			HTMLURL:    b.Links.Web.Href,
+		}
+		if args.Workflow != "" && !strings.EqualFold(run.Name, args.Workflow) {
+			continue
+		}
+		if args.Status != "" && args.Status != run.Status && args.Status != run.Conclusion {
+			continue
+		}
+		out.WorkflowRuns = append(out.WorkflowRuns, run)
+	}
+	out.TotalCount = len(out.WorkflowRuns)
+	return out, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newAzureClient returns a client whose azure provider is served by mux,
+// authenticated with the personal access token pat.
+func newAzureClient(t *testing.T, mux *http.ServeMux, opts GithubClientOptions) *GithubClient {
+	t.Helper()
+	gh, url := newForgeTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("api-version"); got != azureAPIVersion {
+			t.Errorf("%s asks for API version %q", r.URL, got)
+		}
+		mux.ServeHTTP(w, r)
+	}), "Basic OnBhdA==", opts)
+	gh.addAzureDevOps(url, "pat")
+	return gh
+}
+
+func TestAzureListsOrganizationRepositories(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/_apis/git/repositories", jsonHandler(`{"value":[
+		{"name":"api","webUrl":"https://dev.azure.com/org/web/_git/api","project":{"name":"web","visibility":"private","description":"Web"}},
+		{"name":"site","project":{"name":"docs","visibility":"public"}}
+	]}`))
+	gh := newAzureClient(t, mux, GithubClientOptions{})
+
+	res := callForgeTool(t, gh, "list-repositories", map[string]any{"name": "org", "provider": "azure", "type": "private"})
+	text := resultText(res)
+	if res.IsError || !strings.Contains(text, "Name: api, URL: https://dev.azure.com/org/web/_git/api") || strings.Contains(text, "site") {
+		t.Errorf("list-repositories = %q, want only the private repository", text)
+	}
+}
+
+func TestAzureGetFile(t *testing.T) {
+	sha := strings.Repeat("a", 40)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/proj/_apis/git/repositories/repo/items", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("path") != "/docs/a.md" || q.Get("versionDescriptor.version") != sha || q.Get("versionDescriptor.versionType") != "commit" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte("# A"))
+	})
+	gh := newAzureClient(t, mux, GithubClientOptions{})
+
+	res := callForgeTool(t, gh, "get-file-contents", map[string]any{"owner": "org/proj", "repo": "repo", "path": "docs/a.md", "ref": sha, "provider": "azure"})
+	if text := resultText(res); res.IsError || text != "# A" {
+		t.Errorf("get-file-contents = %q", text)
+	}
+	// Repositories live in projects, which owner has to name.
+	res = callForgeTool(t, gh, "get-file-contents", map[string]any{"owner": "org", "repo": "repo", "path": "a.md", "provider": "azure"})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "organization/project") {
+		t.Errorf("get-file-contents without a project = %q, want an error", text)
+	}
+	res = callForgeTool(t, gh, "list-issues", map[string]any{"owner": "org/proj", "repo": "repo", "provider": "azure"})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "Azure Boards") {
+		t.Errorf("list-issues = %q, want an error", text)
+	}
+}
+
+func TestAzurePullRequestPages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/proj/_apis/git/repositories/repo/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("searchCriteria.status") != "all" || q.Get("searchCriteria.targetRefName") != "refs/heads/main" || q.Get("$top") != "2" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		// Two full pages and a short one.
+		skip, _ := strconv.Atoi(q.Get("$skip"))
+		var values []string
+		for id := skip + 1; id <= min(skip+2, 5); id++ {
+			status := "completed"
+			if id == 5 {
+				status = "active"
+			}
+			values = append(values, fmt.Sprintf(`{"pullRequestId":%d,"title":"PR %d","status":%q,"sourceRefName":"refs/heads/fix","targetRefName":"refs/heads/main","createdBy":{"uniqueName":"bob"}}`, id, id, status))
+		}
+		jsonHandler(`{"value":[`+strings.Join(values, ",")+`]}`)(w, r)
+	})
+	gh := newAzureClient(t, mux, GithubClientOptions{PerPage: 2})
+
+	res := callForgeTool(t, gh, "list-pull-requests", map[string]any{"owner": "org/proj", "repo": "repo", "provider": "azure", "state": "closed", "base": "main"})
+	text := resultText(res)
+	if res.IsError || !strings.Contains(text, "#1 [merged] PR 1 by bob, fix -> main") || !strings.Contains(text, "#4 [merged]") || strings.Contains(text, "PR 5") {
+		t.Errorf("list-pull-requests = %q, want the four completed ones", text)
+	}
+}
+
+func TestAzureListsPipelineRuns(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org/proj/_apis/git/repositories/repo", jsonHandler(`{"id":"1234"}`))
+	mux.HandleFunc("GET /org/proj/_apis/build/builds", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("repositoryId") != "1234" || q.Get("branchName") != "refs/heads/main" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		jsonHandler(`{"value":[
+			{"id":7,"status":"completed","result":"succeeded","reason":"manual","sourceBranch":"refs/heads/main","definition":{"name":"CI"},"requestedFor":{"uniqueName":"bob"}},
+			{"id":8,"status":"inProgress","definition":{"name":"Release"}}
+		]}`)(w, r)
+	})
+	gh := newAzureClient(t, mux, GithubClientOptions{})
+
+	res := callTool(t, func(s *mcp.Server) {
+		addTool(s, listWorkflowRunsTool, gh.ListWorkflowRuns)
+	}, "list-workflow-runs", map[string]any{"owner": "org/proj", "repo": "repo", "provider": "azure", "branch": "main", "workflow": "ci"})
+	text := resultText(res)
+	if res.IsError || !strings.Contains(text, "7 CI #0 [success] on main (manual) by bob") || strings.Contains(text, "Release") {
+		t.Errorf("list-workflow-runs = %q", text)
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bitbucketAPIURL is the API root of Bitbucket Cloud.
+const bitbucketAPIURL = "https://api.bitbucket.org/2.0"
+
+// addBitbucket makes Bitbucket Cloud available as the "bitbucket" provider.
+// token is an access token, or an API token or app password when username
+// is set.
+func (c *GithubClient) addBitbucket(username, token string) {
+	authorize := bearerAuth(token)
+	if username != "" && token != "" {
+		basic := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+token))
+		authorize = func(req *http.Request) {
+			req.Header.Set("Authorization", basic)
+		}
+	}
+	c.addProvider("bitbucket", forgeProvider{
+		hosts: []string{"api.bitbucket.org", "bitbucket.org"},
+		forge: func(baseURL string) Forge {
+			// A base_url on the website stands for the API.
+			if u, err := url.Parse(baseURL); err != nil || baseURL == "" || strings.EqualFold(u.Hostname(), "bitbucket.org") {
+				baseURL = bitbucketAPIURL
+			}
+			return &bitbucketForge{c: c, baseURL: baseURL}
+		},
+		authorize: authorize,
+	})
+}
+
+// bitbucketForge is the Forge of Bitbucket Cloud, whose workspaces are the
+// owners.
+type bitbucketForge struct {
+	c       *GithubClient
+	baseURL string
+}
+
+// bitbucketPages fetches every page of a Bitbucket listing, which links to
+// the next page in its body rather than in a Link header.
+func bitbucketPages[T any](ctx context.Context, c *GithubClient, url string) ([]T, error) {
+	progress := progressFrom(ctx)
+	base := progress.value()
+	all := []T{}
+	for page := 0; url != "" && page < c.maxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return all, interrupted(ctx, &partialError{Pages: page, Items: len(all), Err: err})
+		}
+		var resp struct {
+			Values []T    `json:"values"`
+			Next   string `json:"next"`
+			Size   int    `json:"size"`
+			Len    int    `json:"pagelen"`
+		}
+		if err := c.getJSON(ctx, url, &resp); err != nil {
+			if ctx.Err() != nil && page > 0 {
+				return all, interrupted(ctx, &partialError{Pages: page, Items: len(all), Err: ctx.Err()})
+			}
+			return nil, err
+		}
+		all = append(all, resp.Values...)
+		url = resp.Next
+		total := 0.0
+		if resp.Size > 0 && resp.Len > 0 {
+			total = base + float64(min((resp.Size+resp.Len-1)/resp.Len, c.maxPages))
+		}
+		progress.update(ctx, base+float64(page+1), total, fmt.Sprintf("Fetched page %d", page+1))
+	}
+	if url != "" {
+		noteTruncated(ctx, c.maxPages)
+	}
+	return all, nil
+}
+
+func (f *bitbucketForge) repoURL(workspace, repo string) string {
+	return fmt.Sprintf("%s/repositories/%s/%s", f.baseURL, url.PathEscape(workspace), url.PathEscape(repo))
+}
+
+type bitbucketRepository struct {
+	Slug        string    `json:"slug"`
+	FullName    string    `json:"full_name"`
+	IsPrivate   bool      `json:"is_private"`
+	Description string    `json:"description"`
+	Language    string    `json:"language"`
+	UpdatedOn   time.Time `json:"updated_on"`
+	Parent      *struct{} `json:"parent"`
+	MainBranch  struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (f *bitbucketForge) ListRepos(ctx context.Context, workspace string, opts RepoListOptions) ([]Repository, error) {
+	if err := f.c.scope.checkOwner(workspace); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("pagelen", strconv.Itoa(min(f.c.perPage, 100)))
+	sort := map[string]string{"created": "created_on", "updated": "updated_on", "pushed": "updated_on", "full_name": "full_name"}[opts.Sort]
+	if sort != "" {
+		if opts.Direction == "desc" || (opts.Direction == "" && opts.Sort != "full_name") {
+			sort = "-" + sort
+		}
+		query.Set("sort", sort)
+	}
+	switch opts.Type {
+	case "public":
+		query.Set("q", "is_private = false")
+	case "private":
+		query.Set("q", "is_private = true")
+	}
+
+	raw, err := bitbucketPages[bitbucketRepository](ctx, f.c, fmt.Sprintf("%s/repositories/%s?%s", f.baseURL, url.PathEscape(workspace), query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	repos := []Repository{}
+	for _, r := range raw {
+		if !f.c.scope.allowsFullName(r.FullName) {
+			continue
+		}
+		fork := r.Parent != nil
+		if (opts.Type == "forks" && !fork) || (opts.Type == "sources" && fork) {
+			continue
+		}
+		repos = append(repos, Repository{
+			Name:        r.Slug,
+			FullName:    r.FullName,
+			HTMLURL:     r.Links.HTML.Href,
+			Private:     r.IsPrivate,
+			Description: r.Description,
+			Language:    r.Language,
+			Fork:        fork,
+			PushedAt:    r.UpdatedOn,
+		})
+	}
+	return repos, nil
+}
+
+func (f *bitbucketForge) GetFile(ctx context.Context, workspace, repo, path, ref string) (*File, error) {
+	if err := f.c.scope.checkRepo(workspace, repo); err != nil {
+		return nil, err
+	}
+	repoURL := f.repoURL(workspace, repo)
+	// The src endpoint needs a commit or branch.
+	if ref == "" {
+		var r bitbucketRepository
+		if err := f.c.getJSON(ctx, repoURL, &r); err != nil {
+			return nil, err
+		}
+		ref = r.MainBranch.Name
+	}
+	resp, err := f.c.get(ctx, fmt.Sprintf("%s/src/%s/%s", repoURL, url.PathEscape(ref), escapePath(path)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Directories are listed as a JSON page, files come raw, and can be
+	// JSON too.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var listing struct {
+			Values  []json.RawMessage `json:"values"`
+			PageLen int               `json:"pagelen"`
+		}
+		if json.Unmarshal(data, &listing) == nil && listing.Values != nil && listing.PageLen > 0 {
+			return nil, fmt.Errorf("%s is a directory, not a file", path)
+		}
+	}
+	return &File{Path: path, Size: len(data), Content: data}, nil
+}
+
+type bitbucketIssue struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	State string `json:"state"`
+	Kind  string `json:"kind"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+// bitbucketOpenIssueStates are the issue states counted as open, the other
+// ones being resolved, invalid, duplicate, wontfix and closed.
+var bitbucketOpenIssueStates = []string{"new", "open", "on hold"}
+
+func (f *bitbucketForge) ListIssues(ctx context.Context, workspace, repo string, opts IssueListOptions) ([]Issue, error) {
+	if err := f.c.scope.checkRepo(workspace, repo); err != nil {
+		return nil, err
+	}
+	if len(opts.Labels) > 0 {
+		return nil, fmt.Errorf("bitbucket issues have no labels to filter on")
+	}
+	// Filters use Bitbucket's query language.
+	var filters []string
+	var open, notOpen []string
+	for _, s := range bitbucketOpenIssueStates {
+		open = append(open, fmt.Sprintf("state = %q", s))
+		notOpen = append(notOpen, fmt.Sprintf("state != %q", s))
+	}
+	switch opts.State {
+	case "", "open":
+		filters = append(filters, "("+strings.Join(open, " OR ")+")")
+	case "closed":
+		filters = append(filters, strings.Join(notOpen, " AND "))
+	}
+	switch opts.Assignee {
+	case "":
+	case "none":
+		filters = append(filters, "assignee = null")
+	case "*":
+		filters = append(filters, "assignee != null")
+	default:
+		filters = append(filters, fmt.Sprintf("assignee.nickname = %q", opts.Assignee))
+	}
+	query := url.Values{}
+	query.Set("pagelen", strconv.Itoa(min(f.c.perPage, 50)))
+	if len(filters) > 0 {
+		query.Set("q", strings.Join(filters, " AND "))
+	}
+
+	raw, err := bitbucketPages[bitbucketIssue](ctx, f.c, f.repoURL(workspace, repo)+"/issues?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	issues := []Issue{}
+	for _, is := range raw {
+		// The kind, like bug or enhancement, is the closest thing to a
+		// label.
+		labels := []string{}
+		if is.Kind != "" {
+			labels = append(labels, is.Kind)
+		}
+		issues = append(issues, Issue{
+			Number: is.ID,
+			Title:  is.Title,
+			State:  is.State,
+			URL:    is.Links.HTML.Href,
+			Labels: labels,
+		})
+	}
+	return issues, nil
+}
+
+type bitbucketPullRequest struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Draft  bool   `json:"draft"`
+	Author struct {
+		Nickname string `json:"nickname"`
+	} `json:"author"`
+	Source struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"source"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"destination"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+func (f *bitbucketForge) ListPullRequests(ctx context.Context, workspace, repo string, opts PullRequestListOptions) ([]PullRequest, error) {
+	if err := f.c.scope.checkRepo(workspace, repo); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("pagelen", strconv.Itoa(min(f.c.perPage, 50)))
+	switch opts.State {
+	case "", "open":
+		query.Add("state", "OPEN")
+	case "closed":
+		query.Add("state", "MERGED")
+		query.Add("state", "DECLINED")
+		query.Add("state", "SUPERSEDED")
+	case "all":
+		for _, s := range []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"} {
+			query.Add("state", s)
+		}
+	}
+	if opts.Base != "" {
+		query.Set("q", fmt.Sprintf("destination.branch.name = %q", opts.Base))
+	}
+
+	raw, err := bitbucketPages[bitbucketPullRequest](ctx, f.c, f.repoURL(workspace, repo)+"/pullrequests?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	pulls := []PullRequest{}
+	for _, pr := range raw {
+		pulls = append(pulls, PullRequest{
+			Number: pr.ID,
+			Title:  pr.Title,
+			State:  strings.ToLower(pr.State),
+			Draft:  pr.Draft,
+			Author: pr.Author.Nickname,
+			Head:   pr.Source.Branch.Name,
+			Base:   pr.Destination.Branch.Name,
+			URL:    pr.Links.HTML.Href,
+			// Bitbucket only tells whether a pull request can be merged
+			// when merging it.
+			Mergeable: "unknown",
+		})
+	}
+	return pulls, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newBitbucketClient returns a client whose bitbucket provider is served by
+// mux, passed to the tools as their base_url.
+func newBitbucketClient(t *testing.T, mux *http.ServeMux, username, token, wantAuth string) (*GithubClient, string) {
+	t.Helper()
+	gh, url := newForgeTestClient(t, mux, wantAuth, GithubClientOptions{})
+	gh.addBitbucket(username, token)
+	// Credentials are only sent to Bitbucket's own hosts, so the test
+	// server has to be one of them.
+	p := gh.providers["bitbucket"]
+	p.hosts = []string{"127.0.0.1"}
+	gh.providers["bitbucket"] = p
+	return gh, url
+}
+
+func TestBitbucketFollowsNextPages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repositories/ws", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			jsonHandler(`{"values":[{"slug":"two","full_name":"ws/two","is_private":true,"parent":{}}]}`)(w, r)
+			return
+		}
+		if q := r.URL.Query(); q.Get("sort") != "-updated_on" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"values":[{"slug":"one","full_name":"ws/one","language":"go","links":{"html":{"href":"https://bitbucket.org/ws/one"}}}],"next":"http://%s/repositories/ws?page=2","size":2,"pagelen":1}`, r.Host)
+	})
+	gh, url := newBitbucketClient(t, mux, "", "token", "Bearer token")
+
+	res := callForgeTool(t, gh, "list-repositories", map[string]any{"name": "ws", "provider": "bitbucket", "base_url": url, "sort": "updated"})
+	text := resultText(res)
+	if res.IsError || !strings.Contains(text, "Name: one, URL: https://bitbucket.org/ws/one, Language: go") || !strings.Contains(text, "Name: two") || !strings.Contains(text, "(private, fork)") {
+		t.Errorf("list-repositories = %q, want both pages", text)
+	}
+}
+
+func TestBitbucketIssuesAndPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repositories/ws/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		want := `(state = "new" OR state = "open" OR state = "on hold") AND assignee.nickname = "bob"`
+		if got := r.URL.Query().Get("q"); got != want {
+			t.Errorf("issue query %q, want %q", got, want)
+		}
+		jsonHandler(`{"values":[{"id":4,"title":"Crash","state":"new","kind":"bug"}]}`)(w, r)
+	})
+	mux.HandleFunc("GET /repositories/ws/repo/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query()["state"]; strings.Join(got, ",") != "MERGED,DECLINED,SUPERSEDED" {
+			t.Errorf("pull request states %v", got)
+		}
+		jsonHandler(`{"values":[{"id":9,"title":"Fix","state":"MERGED","author":{"nickname":"bob"},"source":{"branch":{"name":"fix"}},"destination":{"branch":{"name":"main"}}}]}`)(w, r)
+	})
+	gh, url := newBitbucketClient(t, mux, "alice", "app-password", "Basic YWxpY2U6YXBwLXBhc3N3b3Jk")
+
+	res := callForgeTool(t, gh, "list-issues", map[string]any{"owner": "ws", "repo": "repo", "provider": "bitbucket", "base_url": url, "assignee": "bob"})
+	if text := resultText(res); res.IsError || !strings.Contains(text, "#4 [new] Crash (labels: bug)") {
+		t.Errorf("list-issues = %q", text)
+	}
+	res = callForgeTool(t, gh, "list-issues", map[string]any{"owner": "ws", "repo": "repo", "provider": "bitbucket", "base_url": url, "labels": []string{"bug"}})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "no labels") {
+		t.Errorf("list-issues by label = %q, want an error", text)
+	}
+	res = callForgeTool(t, gh, "list-pull-requests", map[string]any{"owner": "ws", "repo": "repo", "provider": "bitbucket", "base_url": url, "state": "closed"})
+	if text := resultText(res); res.IsError || !strings.Contains(text, "#9 [merged] Fix by bob, fix -> main, mergeable: unknown") {
+		t.Errorf("list-pull-requests = %q", text)
+	}
+}
+
+func TestBitbucketGetFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repositories/ws/repo", jsonHandler(`{"mainbranch":{"name":"trunk"}}`))
+	mux.HandleFunc("GET /repositories/ws/repo/src/trunk/config.json", jsonHandler(`{"debug":true}`))
+	mux.HandleFunc("GET /repositories/ws/repo/src/trunk/docs", jsonHandler(`{"values":[{"path":"docs/a.md"}],"pagelen":10}`))
+	gh, url := newBitbucketClient(t, mux, "", "", "")
+
+	// A JSON file isn't mistaken for a directory listing.
+	res := callForgeTool(t, gh, "get-file-contents", map[string]any{"owner": "ws", "repo": "repo", "path": "config.json", "provider": "bitbucket", "base_url": url})
+	if text := resultText(res); res.IsError || text != `{"debug":true}` {
+		t.Errorf("get-file-contents of a JSON file = %q", text)
+	}
+	res = callForgeTool(t, gh, "get-file-contents", map[string]any{"owner": "ws", "repo": "repo", "path": "docs", "provider": "bitbucket", "base_url": url})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "is a directory") {
+		t.Errorf("get-file-contents of a directory = %q, want an error", text)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listBranchesTool = &mcp.Tool{
+	Name:        "list-branches",
+	Description: "A tool to list the branches of a Github repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"protected_only": {
+				Type:        "boolean",
+				Description: "Only return protected branches",
+			},
+			"page": {
+				Type:        "integer",
+				Description: "Only return this page of results, starting at 1 (defaults to all pages)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListBranchesArgs struct {
+	CommonArgs
+	Owner         string `json:"owner"`
+	Repo          string `json:"repo"`
+	ProtectedOnly bool   `json:"protected_only,omitempty"`
+	Page          int    `json:"page,omitempty"`
+}
+
+type Branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+	Protected bool `json:"protected"`
+}
+
+type BranchListOutput struct {
+	Repository string   `json:"repository"`
+	Branches   []Branch `json:"branches"`
+}
+
+func (c *GithubClient) ListBranches(ctx context.Context, req *mcp.CallToolRequest, args ListBranchesArgs) (*mcp.CallToolResult, BranchListOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, BranchListOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, BranchListOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(c.perPage))
+	if args.ProtectedOnly {
+		query.Set("protected", "true")
+	}
+	if args.Page > 0 {
+		query.Set("page", strconv.Itoa(args.Page))
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/branches?%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), query.Encode())
+
+	var branches []Branch
+	var err error
+	if args.Page > 0 {
+		branches = []Branch{}
+		err = c.getJSON(ctx, apiURL, &branches)
+	} else {
+		branches, err = getAllPages[Branch](ctx, c, apiURL)
+	}
+	if err != nil {
+		return nil, BranchListOutput{}, err
+	}
+	var result strings.Builder
+	fmt.Fprintf(&result, "Branches for repository %s/%s:\n", args.Owner, args.Repo)
+	for _, b := range branches {
+		protected := ""
+		if b.Protected {
+			protected = " (protected)"
+		}
+		fmt.Fprintf(&result, "%s %s%s\n", b.Name, b.Commit.SHA, protected)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, BranchListOutput{
+		Repository: args.Owner + "/" + args.Repo,
+		Branches:   branches,
+	}, nil
+}
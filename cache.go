@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxETagEntries bounds how many responses memoryETagStore remembers.
+	maxETagEntries = 1000
+	// maxCachedBodyBytes is the largest response body the caches keep;
+	// bigger ones, such as log archives, are passed through uncached.
+	maxCachedBodyBytes = 1 << 20
+)
+
+// etagTransport is an http.RoundTripper that makes GET requests conditional.
+//
+// It remembers the ETag and Last-Modified validators of successful responses
+// and sends them back as If-None-Match and If-Modified-Since. When GitHub
+// answers 304 Not Modified the remembered body is served instead, and the
+// request doesn't count against the rate limit.
+type etagTransport struct {
+	base  http.RoundTripper
+	store etagStore
+}
+
+// etagStore holds the responses remembered by etagTransport.
+type etagStore interface {
+	// Get returns the response stored under key, or nil.
+	Get(key string) *cachedResponse
+	Put(key string, r *cachedResponse)
+}
+
+// cachedResponse is a response remembered by etagTransport.
+type cachedResponse struct {
+	Header http.Header
+	Body   []byte
+}
+
+// newETagTransport returns an etagTransport keeping its responses in store,
+// or in memory when store is nil.
+func newETagTransport(base http.RoundTripper, store etagStore) *etagTransport {
+	if store == nil {
+		store = &memoryETagStore{entries: map[string]*cachedResponse{}}
+	}
+	return &etagTransport{base: base, store: store}
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.base.RoundTrip(req)
+	}
+	key := cacheKey(req)
+	cached := t.store.Get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return cached.response(req, resp.Header), nil
+	}
+	if resp.StatusCode != http.StatusOK || (resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+
+	body, ok, err := bufferBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		t.store.Put(key, &cachedResponse{Header: resp.Header.Clone(), Body: body})
+	}
+	return resp, nil
+}
+
+// bufferBody reads the body of resp into memory so it can be cached, and
+// replaces it with a reader over the buffered bytes. Bodies larger than
+// maxCachedBodyBytes are left to stream and ok is false.
+func bufferBody(resp *http.Response) (body []byte, ok bool, err error) {
+	body, err = io.ReadAll(io.LimitReader(resp.Body, maxCachedBodyBytes+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, false, err
+	}
+	if len(body) > maxCachedBodyBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return nil, false, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true, nil
+}
+
+// memoryETagStore is the default etagStore, it is lost when the server
+// stops.
+type memoryETagStore struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+func (s *memoryETagStore) Get(key string) *cachedResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[key]
+}
+
+func (s *memoryETagStore) Put(key string, entry *cachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= maxETagEntries {
+		// Evict an arbitrary entry; it only costs a full request later.
+		for k := range s.entries {
+			delete(s.entries, k)
+			break
+		}
+	}
+	s.entries[key] = entry
+}
+
+// response rebuilds a 200 response from the cached entry. The fresh headers,
+// such as the current rate limit sent along with a 304, take precedence.
+func (e *cachedResponse) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.Header.Clone()
+	for k, v := range fresh {
+		header[k] = v
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies the response to req. The URL is normalized so that
+// the order of query parameters doesn't matter. Requests made with different
+// credentials or media types may see different content, so both are part of
+// the key; the credentials only as a hash.
+func cacheKey(req *http.Request) string {
+	u := *req.URL
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.Method + " " + u.String() + " " + req.Header.Get("Accept") + " " + hex.EncodeToString(auth[:8])
+}
+
+// responseCache is an http.RoundTripper serving repeated GET requests from
+// memory for up to ttl, without contacting GitHub at all. It keeps at most
+// size responses, evicting the least recently used. A tool call can skip it
+// with the no_cache argument, in which case the fresh response replaces the
+// cached one.
+type responseCache struct {
+	base http.RoundTripper
+	ttl  time.Duration
+	size int
+
+	mu      sync.Mutex
+	lru     *list.List // of *lruEntry, most recently used first
+	entries map[string]*list.Element
+	stats   CacheStats
+}
+
+type lruEntry struct {
+	key      string
+	response *cachedResponse
+	expires  time.Time
+}
+
+// CacheStats describes the state of the response cache.
+type CacheStats struct {
+	Enabled   bool   `json:"enabled"`
+	TTL       string `json:"ttl"`
+	Size      int    `json:"size"`
+	Entries   int    `json:"entries"`
+	Hits      int64  `json:"hits"`
+	Misses    int64  `json:"misses"`
+	Bypassed  int64  `json:"bypassed"`
+	Evictions int64  `json:"evictions"`
+}
+
+func newResponseCache(base http.RoundTripper, ttl time.Duration, size int) *responseCache {
+	return &responseCache{
+		base:    base,
+		ttl:     ttl,
+		size:    size,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.base.RoundTrip(req)
+	}
+	key := cacheKey(req)
+	if commonArgsFrom(req.Context()).NoCache {
+		c.mu.Lock()
+		c.stats.Bypassed++
+		c.mu.Unlock()
+	} else if cached := c.get(key); cached != nil {
+		return cached.response(req, nil), nil
+	}
+
+	resp, err := c.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, ok, err := bufferBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		c.put(key, &cachedResponse{Header: resp.Header.Clone(), Body: body})
+	}
+	return resp, nil
+}
+
+func (c *responseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil
+	}
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		c.stats.Misses++
+		return nil
+	}
+	c.lru.MoveToFront(el)
+	c.stats.Hits++
+	return entry.response
+}
+
+func (c *responseCache) put(key string, response *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &lruEntry{key: key, response: response, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *responseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Enabled = true
+	stats.TTL = c.ttl.String()
+	stats.Size = c.size
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+var cacheStatsTool = &mcp.Tool{
+	Name:        "cache-stats",
+	Description: "A tool to report how effective the GitHub response cache is",
+	Annotations: localAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{},
+	},
+}
+
+func (c *GithubClient) CacheStats(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, CacheStats, error) {
+	var stats CacheStats
+	if c.cache != nil {
+		stats = c.cache.Stats()
+	}
+
+	var result strings.Builder
+	if !stats.Enabled {
+		fmt.Fprintf(&result, "The response cache is disabled\n")
+	} else {
+		fmt.Fprintf(&result, "Response cache: %d of %d entries, TTL %s\n", stats.Entries, stats.Size, stats.TTL)
+		fmt.Fprintf(&result, "Hits: %d, misses: %d, bypassed: %d, evictions: %d\n", stats.Hits, stats.Misses, stats.Bypassed, stats.Evictions)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, stats, nil
+}
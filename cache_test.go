@@ -0,0 +1,174 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestETagRevalidatesResponses(t *testing.T) {
+	var conditional, notModified int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional++
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		jsonHandler(`[{"name":"r","full_name":"o/r"}]`)(w, r)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{})
+	list := func() *mcp.CallToolResult {
+		return callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+			"name": "o", "account_type": "org",
+		})
+	}
+
+	first := resultText(list())
+	second := list()
+	if second.IsError || resultText(second) != first {
+		t.Errorf("revalidated listing = %q, want %q", resultText(second), first)
+	}
+	if conditional != 1 || notModified != 1 {
+		t.Errorf("%d conditional requests answered %d times with 304, want 1 and 1", conditional, notModified)
+	}
+}
+
+func TestETagSkipsResponsesWithoutValidators(t *testing.T) {
+	conditional := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			conditional++
+		}
+		jsonHandler(`[]`)(w, r)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{})
+	for range 2 {
+		callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+			"name": "o", "account_type": "org",
+		})
+	}
+	if conditional != 0 {
+		t.Errorf("%d conditional requests for a response without ETag or Last-Modified", conditional)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	request := func(url, token string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+	a := cacheKey(request("https://API.github.com/orgs/o/repos?page=2&per_page=100", "t1"))
+	if b := cacheKey(request("https://api.github.com/orgs/o/repos?per_page=100&page=2", "t1")); a != b {
+		t.Errorf("keys differ by query order or host case: %q and %q", a, b)
+	}
+	if b := cacheKey(request("https://api.github.com/orgs/o/repos?per_page=100&page=2", "t2")); a == b {
+		t.Error("requests made with different tokens share a key")
+	}
+	if b := cacheKey(request("https://api.github.com/orgs/o/repos?per_page=100&page=3", "t1")); a == b {
+		t.Error("different pages share a key")
+	}
+}
+
+func TestResponseCacheServesFromMemory(t *testing.T) {
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		jsonHandler(`[{"name":"r","full_name":"o/r"}]`)(w, r)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{CacheTTL: time.Hour})
+	list := func(noCache bool) {
+		res := callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+			"name": "o", "account_type": "org", "no_cache": noCache,
+		})
+		if res.IsError {
+			t.Fatal(resultText(res))
+		}
+	}
+
+	list(false)
+	list(false)
+	if requests != 1 {
+		t.Errorf("cached listing sent %d requests, want 1", requests)
+	}
+	list(true)
+	if requests != 2 {
+		t.Errorf("no_cache listing sent %d requests in total, want 2", requests)
+	}
+	list(false)
+	if requests != 2 {
+		t.Errorf("listing after no_cache sent %d requests in total, want the refreshed response reused", requests)
+	}
+	stats := gh.cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Bypassed != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 1 miss, 1 bypassed and 1 entry", stats)
+	}
+}
+
+func TestResponseCacheExpiresAndEvicts(t *testing.T) {
+	requests := map[string]int{}
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests[req.URL.Path]++
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	})
+	get := func(c *responseCache, path string) {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.github.com"+path, nil)
+		resp, err := c.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	expiring := newResponseCache(base, time.Millisecond, 10)
+	get(expiring, "/a")
+	time.Sleep(5 * time.Millisecond)
+	get(expiring, "/a")
+	if requests["/a"] != 2 {
+		t.Errorf("expired entry: %d requests, want 2", requests["/a"])
+	}
+
+	small := newResponseCache(base, time.Hour, 2)
+	get(small, "/b")
+	get(small, "/c")
+	get(small, "/b") // /c is now the least recently used
+	get(small, "/d")
+	get(small, "/b")
+	get(small, "/c")
+	if requests["/b"] != 1 || requests["/c"] != 2 {
+		t.Errorf("after eviction: /b sent %d times, /c %d times, want 1 and 2", requests["/b"], requests["/c"])
+	}
+	if stats := small.Stats(); stats.Entries != 2 || stats.Evictions != 2 {
+		t.Errorf("stats = %+v, want 2 entries and 2 evictions", stats)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestCacheStatsTool(t *testing.T) {
+	gh := newTestClient(t, http.NotFoundHandler(), GithubClientOptions{CacheTTL: time.Minute, CacheSize: 10})
+	res := callTool(t, func(s *mcp.Server) { mcp.AddTool(s, cacheStatsTool, gh.CacheStats) }, "cache-stats", nil)
+	if text := resultText(res); !strings.Contains(text, "0 of 10 entries, TTL 1m0s") {
+		t.Errorf("cache-stats = %q", text)
+	}
+	gh = newTestClient(t, http.NotFoundHandler(), GithubClientOptions{})
+	res = callTool(t, func(s *mcp.Server) { mcp.AddTool(s, cacheStatsTool, gh.CacheStats) }, "cache-stats", nil)
+	if text := resultText(res); !strings.Contains(text, "disabled") {
+		t.Errorf("cache-stats without a cache = %q", text)
+	}
+}
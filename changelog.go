@@ -0,0 +1,237 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// unlabeled is the group of the pull requests without labels.
+const unlabeled = "unlabeled"
+
+var changelogTool = &mcp.Tool{
+	Name:        "changelog",
+	Description: "A tool to list the pull requests merged between two tags, or a tag and HEAD, of a Github repository, with their labels and authors, grouped by label to draft release notes from",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"base": {
+				Type:        "string",
+				Description: "Tag, branch or commit SHA of the previous release (defaults to the tag of the latest release)",
+			},
+			"head": {
+				Type:        "string",
+				Description: "Tag, branch or commit SHA of the new release (defaults to HEAD, the tip of the default branch)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ChangelogArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Base  string `json:"base,omitempty"`
+	Head  string `json:"head,omitempty"`
+}
+
+type MergedPullRequest struct {
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	Author   string    `json:"author"`
+	Labels   []string  `json:"labels"`
+	MergedAt time.Time `json:"merged_at"`
+	HTMLURL  string    `json:"html_url"`
+}
+
+// ChangelogGroup lists the pull requests with a label. A pull request with
+// several labels is in several groups.
+type ChangelogGroup struct {
+	Label        string `json:"label"`
+	PullRequests []int  `json:"pull_requests"`
+}
+
+type ChangelogOutput struct {
+	Base    string `json:"base"`
+	Head    string `json:"head"`
+	Commits int    `json:"commits"`
+	// Truncated is set when there were more commits between base and head
+	// than could be fetched.
+	Truncated    bool                `json:"truncated,omitempty"`
+	PullRequests []MergedPullRequest `json:"pull_requests"`
+	Groups       []ChangelogGroup    `json:"groups"`
+}
+
+type commitPullRequest struct {
+	pullRequest
+	MergedAt *time.Time `json:"merged_at"`
+	Labels   []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+func (c *GithubClient) Changelog(ctx context.Context, req *mcp.CallToolRequest, args ChangelogArgs) (*mcp.CallToolResult, ChangelogOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, ChangelogOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, ChangelogOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", baseURL, url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	head := cmp.Or(args.Head, "HEAD")
+	base := args.Base
+	if base == "" {
+		var latest release
+		if err := c.getJSON(ctx, repoURL+"/releases/latest", &latest); err != nil {
+			if isNotFound(err) {
+				return nil, ChangelogOutput{}, fmt.Errorf("%s/%s has no release to start from, give a base", args.Owner, args.Repo)
+			}
+			return nil, ChangelogOutput{}, err
+		}
+		base = latest.TagName
+	}
+
+	// The comparison pages through its commits, the other fields being
+	// repeated on every page.
+	perPage := min(c.perPage, 100)
+	var shas []string
+	total := 0
+	for page := 1; page <= c.maxPages; page++ {
+		var compare struct {
+			TotalCommits int `json:"total_commits"`
+			Commits      []struct {
+				SHA string `json:"sha"`
+			} `json:"commits"`
+		}
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/compare/%s...%s?per_page=%d&page=%d", repoURL, escapePath(base), escapePath(head), perPage, page), &compare); err != nil {
+			return nil, ChangelogOutput{}, err
+		}
+		total = compare.TotalCommits
+		for _, cm := range compare.Commits {
+			shas = append(shas, cm.SHA)
+		}
+		if len(compare.Commits) < perPage || len(shas) >= total {
+			break
+		}
+	}
+
+	// Commits are matched to the pull requests that merged them,
+	// batchWorkers at a time.
+	pulls := make([][]commitPullRequest, len(shas))
+	errs := make([]error, len(shas))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, sha := range shas {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			pulls[i], errs[i] = getAllPages[commitPullRequest](ctx, c, fmt.Sprintf("%s/commits/%s/pulls", repoURL, sha))
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, ChangelogOutput{}, err
+	}
+
+	out := ChangelogOutput{
+		Base:         base,
+		Head:         head,
+		Commits:      total,
+		Truncated:    len(shas) < total,
+		PullRequests: []MergedPullRequest{},
+		Groups:       []ChangelogGroup{},
+	}
+	seen := map[int]bool{}
+	for _, prs := range pulls {
+		for _, pr := range prs {
+			// A commit can also belong to pull requests still open, or
+			// closed without merging.
+			if pr.MergedAt == nil || seen[pr.Number] {
+				continue
+			}
+			seen[pr.Number] = true
+			labels := []string{}
+			for _, l := range pr.Labels {
+				labels = append(labels, l.Name)
+			}
+			out.PullRequests = append(out.PullRequests, MergedPullRequest{
+				Number:   pr.Number,
+				Title:    pr.Title,
+				Author:   pr.User.Login,
+				Labels:   labels,
+				MergedAt: *pr.MergedAt,
+				HTMLURL:  pr.HTMLURL,
+			})
+		}
+	}
+	slices.SortFunc(out.PullRequests, func(a, b MergedPullRequest) int { return a.MergedAt.Compare(b.MergedAt) })
+
+	byLabel := map[string][]int{}
+	for _, pr := range out.PullRequests {
+		if len(pr.Labels) == 0 {
+			byLabel[unlabeled] = append(byLabel[unlabeled], pr.Number)
+		}
+		for _, l := range pr.Labels {
+			byLabel[l] = append(byLabel[l], pr.Number)
+		}
+	}
+	for label, numbers := range byLabel {
+		out.Groups = append(out.Groups, ChangelogGroup{Label: label, PullRequests: numbers})
+	}
+	// The unlabeled pull requests come last.
+	slices.SortFunc(out.Groups, func(a, b ChangelogGroup) int {
+		if (a.Label == unlabeled) != (b.Label == unlabeled) {
+			if a.Label == unlabeled {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Label, b.Label)
+	})
+
+	byNumber := map[int]MergedPullRequest{}
+	for _, pr := range out.PullRequests {
+		byNumber[pr.Number] = pr
+	}
+	var result strings.Builder
+	fmt.Fprintf(&result, "Pull requests merged in %s/%s between %s and %s (%d commits):\n", args.Owner, args.Repo, base, head, total)
+	if out.Truncated {
+		fmt.Fprintf(&result, "Only the first %d commits were looked at\n", len(shas))
+	}
+	for _, g := range out.Groups {
+		fmt.Fprintf(&result, "\n%s:\n", g.Label)
+		for _, n := range g.PullRequests {
+			pr := byNumber[n]
+			fmt.Fprintf(&result, "#%d %s by %s %s\n", pr.Number, pr.Title, pr.Author, pr.HTMLURL)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var getCommitChecksTool = &mcp.Tool{
+	Name:        "get-commit-checks",
+	Description: "A tool to inspect the CI of a commit or pull request on Github: the combined commit status and every check run, with their conclusions and details URLs, to tell exactly which check is failing",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Commit SHA, branch or tag whose checks are inspected",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the pull request whose head commit's checks are inspected, instead of ref",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type GetCommitChecksArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Ref    string `json:"ref,omitempty"`
+	Number int    `json:"number,omitempty"`
+}
+
+type CommitStatus struct {
+	Context     string    `json:"context"`
+	State       string    `json:"state"`
+	Description string    `json:"description"`
+	TargetURL   string    `json:"target_url"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CheckRun struct {
+	Name string `json:"name"`
+	App  string `json:"app"`
+	// Status is queued, in_progress or completed, and Conclusion is only
+	// set once completed.
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DetailsURL  string     `json:"details_url"`
+	HTMLURL     string     `json:"html_url"`
+}
+
+type CommitChecksOutput struct {
+	SHA string `json:"sha"`
+	// State is failure when any check failed, pending while any hasn't
+	// finished, success otherwise, or none when the commit has no checks.
+	State     string         `json:"state"`
+	Failing   []string       `json:"failing"`
+	Pending   []string       `json:"pending"`
+	Statuses  []CommitStatus `json:"statuses"`
+	CheckRuns []CheckRun     `json:"check_runs"`
+}
+
+func (c *GithubClient) GetCommitChecks(ctx context.Context, req *mcp.CallToolRequest, args GetCommitChecksArgs) (*mcp.CallToolResult, CommitChecksOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository's checks should be inspected?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, CommitChecksOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, CommitChecksOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	if (args.Ref == "") == (args.Number == 0) {
+		return nil, CommitChecksOutput{}, fmt.Errorf("give either ref or number")
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	ref := args.Ref
+	target := fmt.Sprintf("%s/%s@%s", args.Owner, args.Repo, args.Ref)
+	if args.Number != 0 {
+		var pr pullRequest
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/pulls/%d", repoURL, args.Number), &pr); err != nil {
+			return nil, CommitChecksOutput{}, err
+		}
+		ref = pr.Head.SHA
+		target = fmt.Sprintf("%s/%s#%d", args.Owner, args.Repo, args.Number)
+	}
+
+	checks, err := c.commitChecks(ctx, repoURL, ref)
+	if err != nil {
+		return nil, CommitChecksOutput{}, err
+	}
+	out := CommitChecksOutput{SHA: checks.SHA, State: checks.state(), Failing: []string{}, Pending: []string{}, Statuses: checks.Statuses, CheckRuns: checks.CheckRuns}
+	for _, s := range checks.Statuses {
+		switch statusOutcome(s.State) {
+		case "failure":
+			out.Failing = append(out.Failing, s.Context)
+		case "pending":
+			out.Pending = append(out.Pending, s.Context)
+		}
+	}
+	for _, r := range checks.CheckRuns {
+		switch checkRunOutcome(r) {
+		case "failure":
+			out.Failing = append(out.Failing, r.Name)
+		case "pending":
+			out.Pending = append(out.Pending, r.Name)
+		}
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Checks of %s (%s): %s\n", target, out.SHA, out.State)
+	if len(out.Failing) > 0 {
+		fmt.Fprintf(&result, "Failing: %s\n", strings.Join(out.Failing, ", "))
+	}
+	if len(out.Pending) > 0 {
+		fmt.Fprintf(&result, "Pending: %s\n", strings.Join(out.Pending, ", "))
+	}
+	if len(out.Statuses) > 0 {
+		fmt.Fprintf(&result, "\n%d commit statuses:\n", len(out.Statuses))
+	}
+	for _, s := range out.Statuses {
+		fmt.Fprintf(&result, "[%s] %s: %s %s\n", s.State, s.Context, cmp.Or(s.Description, "no description"), s.TargetURL)
+	}
+	if len(out.CheckRuns) > 0 {
+		fmt.Fprintf(&result, "\n%d check runs:\n", len(out.CheckRuns))
+	}
+	for _, r := range out.CheckRuns {
+		title := ""
+		if r.Title != "" {
+			title = ": " + r.Title
+		}
+		fmt.Fprintf(&result, "[%s] %s (%s)%s %s\n", cmp.Or(r.Conclusion, r.Status), r.Name, r.App, title, cmp.Or(r.DetailsURL, r.HTMLURL))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// commitChecks are the commit statuses and check runs of a commit, failing
+// ones first, then pending ones.
+type commitChecks struct {
+	SHA       string
+	Statuses  []CommitStatus
+	CheckRuns []CheckRun
+}
+
+// commitChecks reads the latest commit statuses and check runs of ref, a
+// commit SHA, branch or tag.
+func (c *GithubClient) commitChecks(ctx context.Context, repoURL, ref string) (commitChecks, error) {
+	var status struct {
+		SHA      string         `json:"sha"`
+		Statuses []CommitStatus `json:"statuses"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/commits/%s/status?per_page=100", repoURL, escapePath(ref)), &status); err != nil {
+		return commitChecks{}, err
+	}
+	checks := commitChecks{SHA: status.SHA, Statuses: status.Statuses, CheckRuns: []CheckRun{}}
+	if checks.Statuses == nil {
+		checks.Statuses = []CommitStatus{}
+	}
+
+	// Check runs come in an object rather than a list, so they are paged
+	// through here.
+	for page := 1; page <= c.maxPages; page++ {
+		var runs struct {
+			TotalCount int `json:"total_count"`
+			CheckRuns  []struct {
+				Name        string     `json:"name"`
+				Status      string     `json:"status"`
+				Conclusion  string     `json:"conclusion"`
+				StartedAt   *time.Time `json:"started_at"`
+				CompletedAt *time.Time `json:"completed_at"`
+				DetailsURL  string     `json:"details_url"`
+				HTMLURL     string     `json:"html_url"`
+				Output      struct {
+					Title string `json:"title"`
+				} `json:"output"`
+				App struct {
+					Name string `json:"name"`
+				} `json:"app"`
+			} `json:"check_runs"`
+		}
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/commits/%s/check-runs?per_page=100&page=%d", repoURL, escapePath(ref), page), &runs); err != nil {
+			return commitChecks{}, err
+		}
+		for _, r := range runs.CheckRuns {
+			checks.CheckRuns = append(checks.CheckRuns, CheckRun{
+				Name:        r.Name,
+				App:         r.App.Name,
+				Status:      r.Status,
+				Conclusion:  r.Conclusion,
+				Title:       r.Output.Title,
+				StartedAt:   r.StartedAt,
+				CompletedAt: r.CompletedAt,
+				DetailsURL:  r.DetailsURL,
+				HTMLURL:     r.HTMLURL,
+			})
+		}
+		if len(runs.CheckRuns) < 100 || len(checks.CheckRuns) >= runs.TotalCount {
+			break
+		}
+	}
+
+	rank := map[string]int{"failure": 0, "pending": 1}
+	outcomeRank := func(outcome string) int {
+		if r, ok := rank[outcome]; ok {
+			return r
+		}
+		return len(rank)
+	}
+	slices.SortStableFunc(checks.Statuses, func(a, b CommitStatus) int {
+		return cmp.Or(cmp.Compare(outcomeRank(statusOutcome(a.State)), outcomeRank(statusOutcome(b.State))), strings.Compare(a.Context, b.Context))
+	})
+	slices.SortStableFunc(checks.CheckRuns, func(a, b CheckRun) int {
+		return cmp.Or(cmp.Compare(outcomeRank(checkRunOutcome(a)), outcomeRank(checkRunOutcome(b))), strings.Compare(a.Name, b.Name))
+	})
+	return checks, nil
+}
+
+// state sums up the checks: failure when any failed, pending while any
+// hasn't finished, success otherwise, or none when there are none.
+func (c commitChecks) state() string {
+	if len(c.Statuses) == 0 && len(c.CheckRuns) == 0 {
+		return "none"
+	}
+	failed, pending := false, false
+	for _, s := range c.Statuses {
+		failed = failed || statusOutcome(s.State) == "failure"
+		pending = pending || statusOutcome(s.State) == "pending"
+	}
+	for _, r := range c.CheckRuns {
+		failed = failed || checkRunOutcome(r) == "failure"
+		pending = pending || checkRunOutcome(r) == "pending"
+	}
+	switch {
+	case failed:
+		return "failure"
+	case pending:
+		return "pending"
+	}
+	return "success"
+}
+
+// statusOutcome tells whether a commit status state is a failure, pending
+// or success.
+func statusOutcome(state string) string {
+	switch state {
+	case "failure", "error":
+		return "failure"
+	case "pending":
+		return "pending"
+	}
+	return "success"
+}
+
+// checkRunOutcome tells whether a check run failed, is pending or passed,
+// neutral and skipped runs passing.
+func checkRunOutcome(r CheckRun) string {
+	switch {
+	case r.Status != "completed":
+		return "pending"
+	case r.Conclusion == "failure", r.Conclusion == "timed_out", r.Conclusion == "cancelled", r.Conclusion == "action_required", r.Conclusion == "startup_failure":
+		return "failure"
+	}
+	return "success"
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxFailedJobsPerRun bounds how many failed jobs of a run have their log
+// fetched.
+const maxFailedJobsPerRun = 5
+
+var analyzeCIFailuresTool = &mcp.Tool{
+	Name:        "analyze-ci-failures",
+	Description: "A tool to summarize the most recent failed GitHub Actions workflow runs of a repository: their failing jobs and steps, and the end of the failing jobs' logs",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"workflow": {
+				Type:        "string",
+				Description: "Only look at runs of this workflow, by file name (e.g., ci.yml), ID or display name",
+			},
+			"branch": {
+				Type:        "string",
+				Description: "Only look at runs for this branch",
+			},
+			"runs": {
+				Type:        "integer",
+				Description: "Number of failed runs to analyze, most recent first (defaults to 3)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(10.0),
+			},
+			"tail_lines": {
+				Type:        "integer",
+				Description: "Number of lines from the end of each failing job's log to return (defaults to 50)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(1000.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type AnalyzeCIFailuresArgs struct {
+	CommonArgs
+	Owner     string `json:"owner"`
+	Repo      string `json:"repo"`
+	Workflow  string `json:"workflow,omitempty"`
+	Branch    string `json:"branch,omitempty"`
+	Runs      int    `json:"runs,omitempty"`
+	TailLines int    `json:"tail_lines,omitempty"`
+}
+
+type FailedJob struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	FailedSteps []string `json:"failed_steps"`
+	HTMLURL     string   `json:"html_url"`
+	// LogTail is the end of the job's log, without timestamps.
+	LogTail string `json:"log_tail"`
+}
+
+type FailedRun struct {
+	WorkflowRun
+	Jobs []FailedJob `json:"jobs"`
+}
+
+// JobFailureCount tells how many of the analyzed runs a job failed in,
+// pointing out recurring failures.
+type JobFailureCount struct {
+	Name     string `json:"name"`
+	Failures int    `json:"failures"`
+}
+
+type CIFailuresOutput struct {
+	Repository  string            `json:"repository"`
+	Runs        []FailedRun       `json:"runs"`
+	FailingJobs []JobFailureCount `json:"failing_jobs"`
+}
+
+type workflowJob struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+	Steps      []struct {
+		Name       string `json:"name"`
+		Conclusion string `json:"conclusion"`
+	} `json:"steps"`
+}
+
+func (c *GithubClient) AnalyzeCIFailures(ctx context.Context, req *mcp.CallToolRequest, args AnalyzeCIFailuresArgs) (*mcp.CallToolResult, CIFailuresOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, CIFailuresOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, CIFailuresOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", baseURL, url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	tailLines := cmp.Or(args.TailLines, 50)
+
+	runs, err := c.workflowRuns(ctx, baseURL, ListWorkflowRunsArgs{
+		Owner:    args.Owner,
+		Repo:     args.Repo,
+		Workflow: args.Workflow,
+		Branch:   args.Branch,
+		Status:   "failure",
+		Limit:    cmp.Or(args.Runs, 3),
+	})
+	if err != nil {
+		return nil, CIFailuresOutput{}, err
+	}
+
+	out := CIFailuresOutput{
+		Repository:  args.Owner + "/" + args.Repo,
+		Runs:        make([]FailedRun, len(runs.WorkflowRuns)),
+		FailingJobs: []JobFailureCount{},
+	}
+	// The jobs of every run are listed, and the logs of the failed ones
+	// fetched, batchWorkers at a time.
+	var mu sync.Mutex
+	var errs []error
+	fail := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, run := range runs.WorkflowRuns {
+		out.Runs[i] = FailedRun{WorkflowRun: run, Jobs: []FailedJob{}}
+		wg.Go(func() {
+			sem <- struct{}{}
+			var jobs struct {
+				Jobs []workflowJob `json:"jobs"`
+			}
+			err := c.getJSON(ctx, fmt.Sprintf("%s/actions/runs/%d/jobs?filter=latest&per_page=100", repoURL, run.ID), &jobs)
+			<-sem
+			if err != nil {
+				fail(fmt.Errorf("listing jobs of run %d: %w", run.ID, err))
+				return
+			}
+			var failed []FailedJob
+			for _, job := range jobs.Jobs {
+				if job.Conclusion != "failure" && job.Conclusion != "timed_out" {
+					continue
+				}
+				fj := FailedJob{ID: job.ID, Name: job.Name, FailedSteps: []string{}, HTMLURL: job.HTMLURL}
+				for _, step := range job.Steps {
+					if step.Conclusion == "failure" || step.Conclusion == "timed_out" {
+						fj.FailedSteps = append(fj.FailedSteps, step.Name)
+					}
+				}
+				failed = append(failed, fj)
+			}
+			failed = failed[:min(len(failed), maxFailedJobsPerRun)]
+			var jobsWG sync.WaitGroup
+			for j := range failed {
+				jobsWG.Go(func() {
+					sem <- struct{}{}
+					defer func() { <-sem }()
+					logs, err := c.download(ctx, fmt.Sprintf("%s/actions/jobs/%d/logs", repoURL, failed[j].ID), maxLogArchiveBytes)
+					if err != nil {
+						// Logs expire, the failing steps are still worth
+						// reporting.
+						if isNotFound(err) || isGone(err) {
+							failed[j].LogTail = "[log no longer available]"
+							return
+						}
+						fail(fmt.Errorf("reading the log of job %d: %w", failed[j].ID, err))
+						return
+					}
+					failed[j].LogTail = logTail(string(logs), tailLines)
+				})
+			}
+			jobsWG.Wait()
+			out.Runs[i].Jobs = append(out.Runs[i].Jobs, failed...)
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, CIFailuresOutput{}, err
+	}
+
+	failures := map[string]int{}
+	for _, run := range out.Runs {
+		for _, job := range run.Jobs {
+			failures[job.Name]++
+		}
+	}
+	for name, n := range failures {
+		out.FailingJobs = append(out.FailingJobs, JobFailureCount{Name: name, Failures: n})
+	}
+	slices.SortFunc(out.FailingJobs, func(a, b JobFailureCount) int {
+		return cmp.Or(cmp.Compare(b.Failures, a.Failures), strings.Compare(a.Name, b.Name))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Analyzed %d failed workflow runs of %s/%s\n", len(out.Runs), args.Owner, args.Repo)
+	if len(out.FailingJobs) > 0 {
+		result.WriteString("Failing jobs:\n")
+		for _, j := range out.FailingJobs {
+			fmt.Fprintf(&result, "  %s: failed in %d of %d runs\n", j.Name, j.Failures, len(out.Runs))
+		}
+	}
+	for _, run := range out.Runs {
+		fmt.Fprintf(&result, "\nRun %d %s #%d on %s (%s) at %s %s\n",
+			run.ID, run.Name, run.RunNumber, run.Branch, run.HeadSHA, run.CreatedAt.Format(time.RFC3339), run.HTMLURL)
+		for _, job := range run.Jobs {
+			fmt.Fprintf(&result, "Job %s failed at: %s\n", job.Name, strings.Join(job.FailedSteps, ", "))
+			fmt.Fprintf(&result, "----- end of log -----\n%s\n----------------------\n", job.LogTail)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// logTail returns the last n lines of a job log, dropping the timestamp
+// GitHub prefixes every line with.
+func logTail(log string, n int) string {
+	lines := strings.Split(strings.TrimRight(log, "\n"), "\n")
+	lines = lines[max(len(lines)-n, 0):]
+	for i, line := range lines {
+		if stamp, rest, ok := strings.Cut(line, " "); ok {
+			if _, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+				lines[i] = rest
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var cloneRepositoryTool = &mcp.Tool{
+	Name:        "clone-repository",
+	Description: "A tool to shallow clone a Github repository at a ref into the server's clone cache, returning a handle the tools working on local clones accept. An existing clone is reused unless no_cache is set",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to check out (defaults to the default branch)",
+			},
+			"depth": {
+				Type:        "integer",
+				Description: "Number of commits of history to fetch, 0 for all of it (defaults to 1)",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type CloneRepositoryArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Ref   string `json:"ref,omitempty"`
+	Depth *int   `json:"depth,omitempty"`
+}
+
+type CloneOutput struct {
+	// Handle identifies the clone in the tools working on local clones,
+	// e.g. github.com/kubernetes/kubectl@master.
+	Handle string `json:"handle"`
+	Path   string `json:"path"`
+	Commit string `json:"commit"`
+	Size   int64  `json:"size"`
+	// Reused is set when an existing clone was returned.
+	Reused bool `json:"reused"`
+	// Evicted are the handles of the clones removed to make room.
+	Evicted []string `json:"evicted"`
+}
+
+func (c *GithubClient) CloneRepository(ctx context.Context, req *mcp.CallToolRequest, args CloneRepositoryArgs) (*mcp.CallToolResult, CloneOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be cloned?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, CloneOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, CloneOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	if err := c.scope.checkRepo(args.Owner, args.Repo); err != nil {
+		return nil, CloneOutput{}, err
+	}
+	depth := 1
+	if args.Depth != nil {
+		depth = max(*args.Depth, 0)
+	}
+	apiURL := c.apiURL(args.CommonArgs)
+	webURL, err := githubWebURL(apiURL)
+	if err != nil {
+		return nil, CloneOutput{}, err
+	}
+
+	ref := cmp.Or(args.Ref, "HEAD")
+	// Git refuses ref names starting with a dash, which read like options.
+	if strings.HasPrefix(ref, "-") {
+		return nil, CloneOutput{}, fmt.Errorf("invalid ref %q", ref)
+	}
+	handle := fmt.Sprintf("%s/%s/%s@%s", githubHost(apiURL), args.Owner, args.Repo, ref)
+	dir, err := c.clonePath(handle)
+	if err != nil {
+		return nil, CloneOutput{}, err
+	}
+	out := CloneOutput{Handle: handle, Path: dir, Evicted: []string{}}
+	if _, err := os.Stat(dir); err == nil && !args.NoCache {
+		out.Reused = true
+	} else {
+		// Clone next to the cache entries and move the clone in place once
+		// complete, so a failed clone never replaces a working one.
+		if err := os.MkdirAll(c.cloneDir, 0o755); err != nil {
+			return nil, CloneOutput{}, fmt.Errorf("failed to create clone directory: %w", err)
+		}
+		tmp, err := os.MkdirTemp(c.cloneDir, ".clone-*")
+		if err != nil {
+			return nil, CloneOutput{}, fmt.Errorf("failed to create clone directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		remote := fmt.Sprintf("%s/%s/%s.git", webURL, url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+		auth, err := c.gitAuth(ctx, args.Profile, remote)
+		if err != nil {
+			return nil, CloneOutput{}, err
+		}
+		repo, err := git.PlainInit(tmp, false)
+		if err != nil {
+			return nil, CloneOutput{}, fmt.Errorf("failed to create clone: %w", err)
+		}
+		if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remote}}); err != nil {
+			return nil, CloneOutput{}, fmt.Errorf("failed to create clone: %w", err)
+		}
+		// Fetching the ref, rather than cloning a branch, works for commit
+		// SHAs as well.
+		commit, err := fetchRef(ctx, repo, auth, ref, depth)
+		if err != nil {
+			return nil, CloneOutput{}, err
+		}
+		worktree, err := repo.Worktree()
+		if err != nil {
+			return nil, CloneOutput{}, err
+		}
+		if err := worktree.Checkout(&git.CheckoutOptions{Hash: commit.Hash}); err != nil {
+			return nil, CloneOutput{}, fmt.Errorf("failed to check out %s: %w", ref, err)
+		}
+		if err := os.Chmod(tmp, 0o755); err != nil {
+			return nil, CloneOutput{}, err
+		}
+
+		c.clonesMu.Lock()
+		err = os.RemoveAll(dir)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(dir), 0o755)
+		}
+		if err == nil {
+			err = os.Rename(tmp, dir)
+		}
+		c.clonesMu.Unlock()
+		if err != nil {
+			return nil, CloneOutput{}, fmt.Errorf("failed to move clone to %s: %w", dir, err)
+		}
+	}
+	if err := touchClone(dir); err != nil {
+		return nil, CloneOutput{}, err
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, CloneOutput{}, fmt.Errorf("failed to open clone: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, CloneOutput{}, fmt.Errorf("failed to read the commit of the clone: %w", err)
+	}
+	out.Commit = head.Hash().String()
+	if out.Size, err = dirSize(dir); err != nil {
+		return nil, CloneOutput{}, err
+	}
+	if !out.Reused {
+		if out.Evicted, err = c.evictClones(dir); err != nil {
+			return nil, CloneOutput{}, err
+		}
+	}
+
+	var result strings.Builder
+	verb := "Cloned"
+	if out.Reused {
+		verb = "Reused the clone of"
+	}
+	fmt.Fprintf(&result, "%s %s/%s@%s (%s, %d bytes) to %s\nhandle: %s\n", verb, args.Owner, args.Repo, ref, out.Commit, out.Size, dir, handle)
+	if len(out.Evicted) > 0 {
+		fmt.Fprintf(&result, "Removed %d least recently used clones to stay within the clone cache size: %s\n", len(out.Evicted), strings.Join(out.Evicted, ", "))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// handleProperty is the argument naming the clone a local tool works on.
+func handleProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Handle of a clone returned by clone-repository (e.g., github.com/kubernetes/kubectl@master)",
+	}
+}
+
+// defaultCloneDir is magnet/clones in the user cache directory, or in the
+// temporary directory when there is none.
+func defaultCloneDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "magnet", "clones")
+}
+
+// clonePath returns the directory of the clone identified by handle,
+// host/owner/repo@ref, whether it exists or not.
+func (c *GithubClient) clonePath(handle string) (string, error) {
+	host, owner, repo, ref, err := parseCloneHandle(handle)
+	if err != nil {
+		return "", err
+	}
+	// Refs may contain slashes, escaped to keep each clone one directory.
+	return filepath.Join(c.cloneDir, host, owner, repo, url.PathEscape(ref)), nil
+}
+
+func parseCloneHandle(handle string) (host, owner, repo, ref string, err error) {
+	host, rest, _ := strings.Cut(handle, "/")
+	owner, rest, _ = strings.Cut(rest, "/")
+	repo, ref, _ = strings.Cut(rest, "@")
+	for _, part := range []string{host, owner, repo, ref} {
+		if part == "" || part == "." || part == ".." || part != ref && strings.ContainsAny(part, `/\`) {
+			return "", "", "", "", fmt.Errorf("invalid clone handle %q, expected host/owner/repo@ref", handle)
+		}
+	}
+	return host, owner, repo, ref, nil
+}
+
+// openClone returns the directory of an existing clone, marking it as used
+// so it is evicted last.
+func (c *GithubClient) openClone(handle string) (string, error) {
+	_, owner, repo, _, err := parseCloneHandle(handle)
+	if err != nil {
+		return "", err
+	}
+	if err := c.scope.checkRepo(owner, repo); err != nil {
+		return "", err
+	}
+	dir, err := c.clonePath(handle)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("no clone %s, clone it with clone-repository first", handle)
+	}
+	return dir, touchClone(dir)
+}
+
+// touchClone records that a clone was used, its eviction order being that
+// of the modification times of the clone directories.
+func touchClone(dir string) error {
+	now := time.Now()
+	return os.Chtimes(dir, now, now)
+}
+
+// evictClones removes the least recently used clones other than keep until
+// they fit in cloneMaxBytes, and returns their handles. keep itself is
+// removed when it alone is too large.
+func (c *GithubClient) evictClones(keep string) ([]string, error) {
+	c.clonesMu.Lock()
+	defer c.clonesMu.Unlock()
+	dirs, fits, err := evictLRU(filepath.Join(c.cloneDir, "*", "*", "*", "*"), c.cloneMaxBytes, keep)
+	evicted := []string{}
+	for _, dir := range dirs {
+		evicted = append(evicted, c.cloneHandle(dir))
+	}
+	if err != nil {
+		return evicted, fmt.Errorf("failed to evict clone: %w", err)
+	}
+	if !fits {
+		return evicted, fmt.Errorf("the clone is larger than the %d bytes of the clone cache, try a smaller depth", c.cloneMaxBytes)
+	}
+	return evicted, nil
+}
+
+// evictLRU removes the least recently used directories matching pattern,
+// other than keep, until they take at most maxBytes, and returns them. When
+// keep alone takes more, it is removed as well and fits is false. Names
+// starting with a dot are work in progress and left alone.
+func evictLRU(pattern string, maxBytes int64, keep string) (evicted []string, fits bool, err error) {
+	dirs, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+	type entry struct {
+		dir     string
+		size    int64
+		modTime time.Time
+	}
+	var entries []entry
+	var total int64
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		size, err := dirSize(dir)
+		if err != nil {
+			return nil, false, err
+		}
+		entries = append(entries, entry{dir, size, info.ModTime()})
+		total += size
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return a.modTime.Compare(b.modTime) })
+
+	for _, e := range entries {
+		if total <= maxBytes {
+			break
+		}
+		if e.dir == keep {
+			continue
+		}
+		if err := os.RemoveAll(e.dir); err != nil {
+			return evicted, false, err
+		}
+		total -= e.size
+		evicted = append(evicted, e.dir)
+	}
+	if total > maxBytes {
+		os.RemoveAll(keep)
+		return evicted, false, nil
+	}
+	return evicted, true, nil
+}
+
+// cloneHandle is the reverse of clonePath.
+func (c *GithubClient) cloneHandle(dir string) string {
+	rel, err := filepath.Rel(c.cloneDir, dir)
+	if err != nil {
+		return dir
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 4 {
+		return dir
+	}
+	ref, err := url.PathUnescape(parts[3])
+	if err != nil {
+		ref = parts[3]
+	}
+	return fmt.Sprintf("%s/%s/%s@%s", parts[0], parts[1], parts[2], ref)
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// fetchedRef is where fetchRef stores the ref it fetched until it is peeled.
+// go-git doesn't write FETCH_HEAD.
+const fetchedRef = plumbing.ReferenceName("refs/magnet/fetched")
+
+// fetchRef fetches ref, a branch, tag or commit SHA, from the origin remote
+// of repo, like git fetch origin ref, and returns its commit.
+// Only depth commits of history are fetched when depth is positive.
+func fetchRef(ctx context.Context, repo *git.Repository, auth transport.AuthMethod, ref string, depth int) (*object.Commit, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return nil, err
+	}
+	// Names are looked up among the refs of the remote as git would, SHAs
+	// are fetched as they are.
+	src := ref
+	if !plumbing.IsHash(ref) {
+		refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the refs of %s: %w", remote.Config().URLs[0], err)
+		}
+		name, ok := matchRef(refs, ref)
+		if !ok {
+			return nil, fmt.Errorf("couldn't find remote ref %s", ref)
+		}
+		src = name.String()
+	}
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec("+" + src + ":" + fetchedRef.String())},
+		Depth:    depth,
+		Auth:     auth,
+		Tags:     git.NoTags,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+	fetched, err := repo.Reference(fetchedRef, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+	if err := repo.Storer.RemoveReference(fetchedRef); err != nil {
+		return nil, err
+	}
+	return peelCommit(repo.Storer, fetched.Hash())
+}
+
+// matchRef returns the ref of refs named by name, with the precedence of git:
+// the exact name first, then tags, branches and remote-tracking branches.
+func matchRef(refs []*plumbing.Reference, name string) (plumbing.ReferenceName, bool) {
+	for _, rule := range plumbing.RefRevParseRules {
+		full := plumbing.ReferenceName(fmt.Sprintf(rule, name))
+		for _, r := range refs {
+			if r.Name() == full {
+				return full, true
+			}
+		}
+	}
+	return "", false
+}
+
+// peelCommit returns the commit h is, or that the annotated tag h points to.
+func peelCommit(s storer.EncodedObjectStorer, h plumbing.Hash) (*object.Commit, error) {
+	if tag, err := object.GetTag(s, h); err == nil {
+		return tag.Commit()
+	}
+	return object.GetCommit(s, h)
+}
+
+// gitAuth returns the credentials of the fetches from remote: the token of
+// the named profile, when remote is on that profile's GitHub host. They are
+// passed to each fetch and never stored in the clone.
+func (c *GithubClient) gitAuth(ctx context.Context, profile, remote string) (transport.AuthMethod, error) {
+	profileURL, auth, err := c.profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	webURL, err := githubWebURL(profileURL)
+	if err != nil || auth == nil {
+		return nil, nil
+	}
+	u, err := url.Parse(remote)
+	if err != nil || !sameHost(webURL, u) {
+		return nil, nil
+	}
+	token, err := auth.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &githttp.BasicAuth{Username: "x-access-token", Password: token}, nil
+}
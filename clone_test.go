@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCloneRejectsOptionRef(t *testing.T) {
+	dir := t.TempDir()
+	gh := newTestClient(t, http.NotFoundHandler(), GithubClientOptions{CloneDir: dir})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, cloneRepositoryTool, gh.CloneRepository) }, "clone-repository", map[string]any{
+		"owner": "o", "repo": "r", "ref": "--upload-pack=touch pwned",
+	})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "invalid ref") {
+		t.Errorf("clone of an option ref = %q, want an invalid ref error", text)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("clone directory not empty after a rejected ref: %v", entries)
+	}
+}
+
+// testRepository is the repository served as o/r: main has the commits
+// first and second, the branch dev the commit dev on top of first, which is
+// tagged v1. The fetches must carry token, or no credentials when it is
+// empty.
+type testRepository struct {
+	repo               *git.Repository
+	first, second, dev plumbing.Hash
+	token              string
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{InitOptions: git.InitOptions{DefaultBranch: plumbing.Main}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := func(author, message string, when time.Time, files map[string]string) plumbing.Hash {
+		t.Helper()
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := worktree.Add(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h, err := worktree.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: author, Email: author + "@example.com", When: when}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	r := &testRepository{repo: repo, token: "test-token"}
+	r.first = commit("alice", "Add the readme\n\nWith some docs.\n", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), map[string]string{
+		"README.md": "one\ntwo\n", "docs/a.md": "a\n",
+	})
+	r.second = commit("bob", "Shout two\n", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), map[string]string{"README.md": "one\nTWO\n"})
+	if _, err := repo.CreateTag("v1", r.first, &git.CreateTagOptions{Tagger: &object.Signature{Name: "alice", When: time.Now()}, Message: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("dev"), Hash: r.first, Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	r.dev = commit("carol", "Add the dev file\n", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), map[string]string{"dev.txt": "dev\n"})
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Main}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// ServeHTTP serves the repository as GitHub does, with the smart HTTP
+// protocol at /o/r.git, checking the credentials of the fetches.
+func (r *testRepository) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.token == "" {
+		if auth := req.Header.Get("Authorization"); auth != "" {
+			http.Error(w, "unexpected credentials "+auth, http.StatusBadRequest)
+			return
+		}
+	} else if user, password, _ := req.BasicAuth(); user != "x-access-token" || password != r.token {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	ep, _ := transport.NewEndpoint("/o/r.git")
+	session, err := server.NewServer(server.MapLoader{ep.String(): r.repo.Storer}).NewUploadPackSession(ep, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/o/r.git/info/refs" && req.URL.Query().Get("service") == "git-upload-pack":
+		refs, err := session.AdvertisedReferencesContext(req.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		refs.Prefix = [][]byte{[]byte("# service=git-upload-pack"), pktline.Flush}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		refs.Encode(w)
+	case req.Method == http.MethodPost && req.URL.Path == "/o/r.git/git-upload-pack":
+		upload := packp.NewUploadPackRequest()
+		if err := upload.Decode(req.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := session.UploadPack(req.Context(), upload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+		resp.Encode(w)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// callCloneTool calls the tool name of the clone and local history tools.
+func callCloneTool(t *testing.T, gh *GithubClient, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	return callTool(t, func(s *mcp.Server) {
+		addTool(s, cloneRepositoryTool, gh.CloneRepository)
+		addTool(s, gitLogTool, gh.GitLog)
+		addTool(s, gitBlameTool, gh.GitBlame)
+		addTool(s, gitDiffTool, gh.GitDiff)
+	}, name, args)
+}
+
+// cloneTestRepository clones ref of the test repository with its whole
+// history and returns the clone.
+func cloneTestRepository(t *testing.T, gh *GithubClient, ref string) CloneOutput {
+	t.Helper()
+	res := callCloneTool(t, gh, "clone-repository", map[string]any{"owner": "o", "repo": "r", "ref": ref, "depth": 0})
+	if res.IsError {
+		t.Fatalf("clone-repository of %s = %q", ref, resultText(res))
+	}
+	var out CloneOutput
+	data, _ := json.Marshal(res.StructuredContent)
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestCloneRepository(t *testing.T) {
+	r := newTestRepository(t)
+	gh := newTestClient(t, r, GithubClientOptions{CloneDir: t.TempDir()})
+
+	out := cloneTestRepository(t, gh, "main")
+	if out.Commit != r.second.String() || out.Reused {
+		t.Errorf("clone of main = %+v, want a new clone of %s", out, r.second)
+	}
+	if data, err := os.ReadFile(filepath.Join(out.Path, "README.md")); err != nil || string(data) != "one\nTWO\n" {
+		t.Errorf("README.md of the clone = %q, %v", data, err)
+	}
+	// The token is only ever sent, never stored.
+	if data, err := os.ReadFile(filepath.Join(out.Path, ".git", "config")); err != nil || strings.Contains(string(data), "test-token") {
+		t.Errorf("clone config = %q, %v", data, err)
+	}
+	if again := cloneTestRepository(t, gh, "main"); !again.Reused || again.Commit != out.Commit {
+		t.Errorf("second clone of main = %+v, want it reused", again)
+	}
+
+	// Annotated tags are checked out at their commit, and so are SHAs.
+	if out := cloneTestRepository(t, gh, "v1"); out.Commit != r.first.String() {
+		t.Errorf("clone of v1 at %s, want %s", out.Commit, r.first)
+	}
+	if out := cloneTestRepository(t, gh, "HEAD"); out.Commit != r.second.String() {
+		t.Errorf("clone of HEAD at %s, want %s", out.Commit, r.second)
+	}
+
+	res := callCloneTool(t, gh, "clone-repository", map[string]any{"owner": "o", "repo": "r", "ref": "missing"})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "couldn't find remote ref missing") {
+		t.Errorf("clone of a missing ref = %q, want an error", text)
+	}
+}
+
+func TestLocalHistoryTools(t *testing.T) {
+	r := newTestRepository(t)
+	gh := newTestClient(t, r, GithubClientOptions{CloneDir: t.TempDir()})
+	handle := cloneTestRepository(t, gh, "main").Handle
+
+	res := callCloneTool(t, gh, "git-log", map[string]any{"handle": handle})
+	text := resultText(res)
+	if res.IsError || strings.Index(text, "2024-02-01 bob: Shout two") > strings.Index(text, "2024-01-01 alice: Add the readme") || strings.Contains(text, "dev") {
+		t.Errorf("git-log = %q, want main newest first", text)
+	}
+	for _, tt := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"paths": []string{"docs/"}}, r.first.String()[:12]},
+		{map[string]any{"author": "bob@example"}, r.second.String()[:12]},
+		{map[string]any{"since": "2024-01-15"}, r.second.String()[:12]},
+		{map[string]any{"limit": 1}, r.second.String()[:12]},
+	} {
+		tt.args["handle"] = handle
+		res := callCloneTool(t, gh, "git-log", tt.args)
+		if text := resultText(res); res.IsError || !strings.Contains(text, "1 commits") || !strings.Contains(text, tt.want) {
+			t.Errorf("git-log %v = %q, want only %s", tt.args, text, tt.want)
+		}
+	}
+
+	res = callCloneTool(t, gh, "git-blame", map[string]any{"handle": handle, "path": "README.md"})
+	text = resultText(res)
+	if res.IsError || !strings.Contains(text, r.first.String()[:12]+" 2024-01-01 alice: Add the readme\n     1| one\n") ||
+		!strings.Contains(text, r.second.String()[:12]+" 2024-02-01 bob: Shout two\n     2| TWO\n") {
+		t.Errorf("git-blame = %q", text)
+	}
+	res = callCloneTool(t, gh, "git-blame", map[string]any{"handle": handle, "path": "README.md", "start_line": 3})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "has 2 lines") {
+		t.Errorf("git-blame past the end = %q, want an error", text)
+	}
+
+	// dev isn't in the clone of main and is fetched.
+	res = callCloneTool(t, gh, "git-diff", map[string]any{"handle": handle, "base": "dev"})
+	text = resultText(res)
+	if res.IsError || !strings.Contains(text, "diff --git a/README.md b/README.md") || !strings.Contains(text, "-two\n+TWO\n") || !strings.Contains(text, "-dev\n") {
+		t.Errorf("git-diff from dev = %q", text)
+	}
+	res = callCloneTool(t, gh, "git-diff", map[string]any{"handle": handle, "base": "v1", "head": "dev", "paths": []string{"README.md"}})
+	if text := resultText(res); res.IsError || !strings.Contains(text, "No differences between v1 and dev") {
+		t.Errorf("git-diff of README.md from v1 to dev = %q", text)
+	}
+}
+
+func TestFetchesKeepTokenOnItsHost(t *testing.T) {
+	gh := newTestClient(t, http.NotFoundHandler(), GithubClientOptions{CloneDir: t.TempDir()})
+	other := newTestRepository(t)
+	other.token = ""
+	srv := httptest.NewServer(other)
+	t.Cleanup(srv.Close)
+
+	// A clone from another host, and the refs git-diff fetches into it later
+	// without the base_url, go there without the token.
+	res := callCloneTool(t, gh, "clone-repository", map[string]any{"owner": "o", "repo": "r", "ref": "main", "depth": 0, "base_url": srv.URL + "/api/v3"})
+	if res.IsError {
+		t.Fatalf("clone-repository from another host = %q", resultText(res))
+	}
+	var out CloneOutput
+	data, _ := json.Marshal(res.StructuredContent)
+	json.Unmarshal(data, &out)
+	res = callCloneTool(t, gh, "git-diff", map[string]any{"handle": out.Handle, "base": "dev"})
+	if text := resultText(res); res.IsError || !strings.Contains(text, "-dev\n") {
+		t.Errorf("git-diff from dev of a clone from another host = %q", text)
+	}
+}
+
+func TestShallowCloneHistory(t *testing.T) {
+	r := newTestRepository(t)
+	gh := newTestClient(t, r, GithubClientOptions{CloneDir: t.TempDir()})
+	out := cloneTestRepository(t, gh, "main")
+	repo, err := git.PlainOpen(out.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A fetch of the second commit alone marks it shallow, though the
+	// first is there.
+	if err := repo.Storer.SetShallow([]plumbing.Hash{r.second}); err != nil {
+		t.Fatal(err)
+	}
+	if err := restoreShallow(repo.Storer, nil); err != nil {
+		t.Fatal(err)
+	}
+	if shallow, _ := repo.Storer.Shallow(); len(shallow) > 0 {
+		t.Errorf("shallow commits %v left with their parents in the clone", shallow)
+	}
+
+	// The history of a shallow clone ends at its shallow commits.
+	if err := repo.Storer.SetShallow([]plumbing.Hash{r.second}); err != nil {
+		t.Fatal(err)
+	}
+	res := callCloneTool(t, gh, "git-log", map[string]any{"handle": out.Handle})
+	text := resultText(res)
+	if res.IsError || !strings.Contains(text, "1 commits") || !strings.Contains(text, "The clone is shallow") {
+		t.Errorf("git-log of a shallow clone = %q", text)
+	}
+	res = callCloneTool(t, gh, "git-blame", map[string]any{"handle": out.Handle, "path": "README.md"})
+	text = resultText(res)
+	if res.IsError || strings.Contains(text, "alice") || !strings.Contains(text, "Shout two (oldest commit of the shallow clone)") {
+		t.Errorf("git-blame of a shallow clone = %q", text)
+	}
+}
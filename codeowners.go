@@ -0,0 +1,269 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// codeownersPaths are the places GitHub looks for a CODEOWNERS file, in the
+// order it looks at them.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+var suggestReviewersTool = &mcp.Tool{
+	Name:        "suggest-reviewers",
+	Description: "A tool to suggest reviewers for a Github pull request by matching its changed files against the CODEOWNERS file of its base branch",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type SuggestReviewersArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+}
+
+type FileOwners struct {
+	Path string `json:"path"`
+	// Pattern is the CODEOWNERS rule the path matched, the last matching
+	// one winning, and is empty when no rule matched.
+	Pattern string   `json:"pattern,omitempty"`
+	Owners  []string `json:"owners"`
+}
+
+type SuggestedReviewer struct {
+	// Name is a user or team (@org/team) login, or an email address.
+	Name  string `json:"name"`
+	Team  bool   `json:"team"`
+	Files int    `json:"files"`
+	// Requested is set when the review of the reviewer is already
+	// requested.
+	Requested bool `json:"requested"`
+}
+
+type SuggestReviewersOutput struct {
+	Codeowners string              `json:"codeowners"`
+	Reviewers  []SuggestedReviewer `json:"reviewers"`
+	Files      []FileOwners        `json:"files"`
+	Unowned    []string            `json:"unowned"`
+}
+
+type codeownersRule struct {
+	pattern string
+	match   *regexp.Regexp
+	owners  []string
+}
+
+func (c *GithubClient) SuggestReviewers(ctx context.Context, req *mcp.CallToolRequest, args SuggestReviewersArgs) (*mcp.CallToolResult, SuggestReviewersOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, SuggestReviewersOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, SuggestReviewersOutput{}, fmt.Errorf("owner, repo and number are required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	prURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", baseURL, url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.Number)
+
+	var pr struct {
+		pullRequest
+		RequestedReviewers []struct {
+			Login string `json:"login"`
+		} `json:"requested_reviewers"`
+		RequestedTeams []struct {
+			Slug string `json:"slug"`
+		} `json:"requested_teams"`
+	}
+	if err := c.getJSON(ctx, prURL, &pr); err != nil {
+		return nil, SuggestReviewersOutput{}, err
+	}
+	files, err := getAllPages[ChangedFile](ctx, c, fmt.Sprintf("%s/files?per_page=%d", prURL, c.perPage))
+	if err != nil {
+		return nil, SuggestReviewersOutput{}, err
+	}
+
+	// The CODEOWNERS file of the base branch is the one GitHub requests
+	// reviews from.
+	var rules []codeownersRule
+	out := SuggestReviewersOutput{Reviewers: []SuggestedReviewer{}, Files: []FileOwners{}, Unowned: []string{}}
+	for _, path := range codeownersPaths {
+		file, err := c.getFileContent(ctx, baseURL, args.Owner, args.Repo, path, pr.Base.Ref)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, SuggestReviewersOutput{}, err
+		}
+		data, err := decodeFileContent(file)
+		if err != nil {
+			return nil, SuggestReviewersOutput{}, err
+		}
+		out.Codeowners = path
+		rules = parseCodeowners(string(data))
+		break
+	}
+	if out.Codeowners == "" {
+		return nil, SuggestReviewersOutput{}, fmt.Errorf("%s/%s has no CODEOWNERS file on %s", args.Owner, args.Repo, pr.Base.Ref)
+	}
+
+	requested := map[string]bool{}
+	for _, r := range pr.RequestedReviewers {
+		requested["@"+strings.ToLower(r.Login)] = true
+	}
+	for _, t := range pr.RequestedTeams {
+		requested["@"+strings.ToLower(args.Owner+"/"+t.Slug)] = true
+	}
+	counts := map[string]int{}
+	for _, f := range files {
+		owners := FileOwners{Path: f.Filename, Owners: []string{}}
+		if rule, ok := matchCodeowners(rules, f.Filename); ok {
+			owners.Pattern = rule.pattern
+			owners.Owners = rule.owners
+		}
+		// A rule without owners leaves its files without an owner.
+		if len(owners.Owners) == 0 {
+			out.Unowned = append(out.Unowned, f.Filename)
+		}
+		for _, o := range owners.Owners {
+			counts[o]++
+		}
+		out.Files = append(out.Files, owners)
+	}
+	for name, n := range counts {
+		// Authors can't review their own pull request.
+		if strings.EqualFold(name, "@"+pr.User.Login) {
+			continue
+		}
+		out.Reviewers = append(out.Reviewers, SuggestedReviewer{
+			Name:      name,
+			Team:      strings.HasPrefix(name, "@") && strings.Contains(name, "/"),
+			Files:     n,
+			Requested: requested[strings.ToLower(name)],
+		})
+	}
+	slices.SortFunc(out.Reviewers, func(a, b SuggestedReviewer) int {
+		return cmp.Or(cmp.Compare(b.Files, a.Files), strings.Compare(a.Name, b.Name))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Reviewers of %s/%s#%d from %s (%d changed files):\n", args.Owner, args.Repo, args.Number, out.Codeowners, len(files))
+	for _, r := range out.Reviewers {
+		note := ""
+		if r.Requested {
+			note = " (already requested)"
+		}
+		fmt.Fprintf(&result, "%s owns %d of the changed files%s\n", r.Name, r.Files, note)
+	}
+	if len(out.Unowned) > 0 {
+		fmt.Fprintf(&result, "Files without owner: %s\n", strings.Join(out.Unowned, ", "))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// parseCodeowners reads the rules of a CODEOWNERS file, skipping the lines
+// whose pattern can't be compiled like GitHub does.
+func parseCodeowners(data string) []codeownersRule {
+	var rules []codeownersRule
+	for line := range strings.Lines(data) {
+		if i := strings.Index(line, "#"); i >= 0 && (i == 0 || line[i-1] != '\\') {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		match, err := codeownersPattern(fields[0])
+		if err != nil {
+			continue
+		}
+		rules = append(rules, codeownersRule{pattern: fields[0], match: match, owners: fields[1:]})
+	}
+	return rules
+}
+
+// matchCodeowners returns the rule owning path, the last one matching it.
+func matchCodeowners(rules []codeownersRule, path string) (codeownersRule, bool) {
+	for _, rule := range slices.Backward(rules) {
+		if rule.match.MatchString(path) {
+			return rule, true
+		}
+	}
+	return codeownersRule{}, false
+}
+
+// codeownersPattern compiles a CODEOWNERS pattern, which follows the
+// gitignore rules: a pattern with a slash other than a trailing one is
+// relative to the root of the repository, and a pattern matching a directory
+// matches everything under it.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.ReplaceAll(pattern, `\#`, "#")
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored && !strings.HasPrefix(p, "**") {
+		re.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case p[i] == '*':
+			re.WriteString("[^/]*")
+		case p[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		re.WriteString("/.*")
+	case strings.HasSuffix(p, "/*") && !strings.HasSuffix(p, "**"):
+		// docs/* only matches the files directly in docs.
+	default:
+		re.WriteString("(?:/.*)?")
+	}
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCodeownersPattern(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		// Without a slash a pattern matches at any depth.
+		{"*.go", []string{"main.go", "cmd/magnet/main.go"}, []string{"main.go.orig", "go"}},
+		{"docs", []string{"docs", "docs/a.md", "web/docs/b.md"}, []string{"docs.md", "mydocs/a.md"}},
+		// A leading or inner slash anchors it to the root.
+		{"/README.md", []string{"README.md"}, []string{"docs/README.md"}},
+		{"src/api", []string{"src/api", "src/api/v1/a.go"}, []string{"lib/src/api/a.go", "src/apis/a.go"}},
+		// A trailing slash only matches directories, and isn't an anchor.
+		{"apps/", []string{"apps/a.js", "apps/web/b.js", "lib/apps/c.js"}, []string{"apps", "apps.js"}},
+		{"/build/logs/", []string{"build/logs/a.log", "build/logs/x/b.log"}, []string{"build/logs", "src/build/logs/a.log"}},
+		// dir/* only matches the entries directly in dir.
+		{"docs/*", []string{"docs/a.md"}, []string{"docs/sub/b.md", "docs"}},
+		// ** matches any number of directories.
+		{"**/logs", []string{"logs", "logs/a.log", "deep/down/logs/b.log"}, []string{"catalogs/a"}},
+		{"docs/**/*.md", []string{"docs/a.md", "docs/x/y/b.md"}, []string{"docs/a.txt", "web/docs/a.md"}},
+		{"src/**", []string{"src/a.go", "src/x/y/b.go"}, []string{"lib/src/a.go"}},
+		{"a?.txt", []string{"ab.txt", "x/ac.txt"}, []string{"a/.txt", "abc.txt"}},
+		{`\#notes`, []string{"#notes"}, []string{"notes"}},
+		{"*", []string{"a", "x/y/z.go"}, nil},
+	} {
+		re, err := codeownersPattern(tt.pattern)
+		if err != nil {
+			t.Errorf("codeownersPattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		for _, path := range tt.match {
+			if !re.MatchString(path) {
+				t.Errorf("%q doesn't match %q", tt.pattern, path)
+			}
+		}
+		for _, path := range tt.noMatch {
+			if re.MatchString(path) {
+				t.Errorf("%q matches %q", tt.pattern, path)
+			}
+		}
+	}
+}
+
+const testCodeowners = `# Owners of the repository
+* @global
+
+*.js @js-owner dev@example.com
+/docs/ @docs
+/docs/generated/
+apps/ @octo/apps   # the apps team
+\#notes @hash
+`
+
+func TestMatchCodeowners(t *testing.T) {
+	rules := parseCodeowners(testCodeowners)
+	if len(rules) != 6 {
+		t.Fatalf("parsed %d rules, want 6", len(rules))
+	}
+	// The last matching rule wins, even without owners.
+	for _, tt := range []struct {
+		path    string
+		pattern string
+		owners  []string
+	}{
+		{"main.go", "*", []string{"@global"}},
+		{"web/app.js", "*.js", []string{"@js-owner", "dev@example.com"}},
+		{"docs/app.js", "/docs/", []string{"@docs"}},
+		{"docs/generated/api.md", "/docs/generated/", []string{}},
+		{"apps/app.js", "apps/", []string{"@octo/apps"}},
+		{"lib/apps/x", "apps/", []string{"@octo/apps"}},
+		{"#notes", `\#notes`, []string{"@hash"}},
+	} {
+		rule, ok := matchCodeowners(rules, tt.path)
+		if !ok || rule.pattern != tt.pattern || !slices.Equal(rule.owners, tt.owners) {
+			t.Errorf("owners of %s = %q %q, want %q %q", tt.path, rule.pattern, rule.owners, tt.pattern, tt.owners)
+		}
+	}
+	if rule, ok := matchCodeowners(parseCodeowners("/docs/ @docs\n"), "main.go"); ok {
+		t.Errorf("main.go owned by %q", rule.pattern)
+	}
+}
+
+func TestSuggestReviewers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/r/pulls/1", jsonHandler(`{"number":1,"user":{"login":"Alice"},"base":{"ref":"main"},
+		"requested_reviewers":[{"login":"global"}],"requested_teams":[{"slug":"apps"}]}`))
+	mux.HandleFunc("GET /repos/octo/r/pulls/1/files", jsonHandler(`[{"filename":"main.go"},{"filename":"apps/a.js"},
+		{"filename":"apps/b.js"},{"filename":"docs/generated/api.md"}]`))
+	mux.HandleFunc("GET /repos/octo/r/contents/.github/CODEOWNERS", http.NotFound)
+	mux.HandleFunc("GET /repos/octo/r/contents/CODEOWNERS", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "main" {
+			t.Errorf("CODEOWNERS read at %q, want the base branch", r.URL.Query().Get("ref"))
+		}
+		content := base64.StdEncoding.EncodeToString([]byte(testCodeowners + "main.go @alice\n"))
+		fmt.Fprintf(w, `{"type":"file","encoding":"base64","content":%q}`, content)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, suggestReviewersTool, gh.SuggestReviewers) }, "suggest-reviewers", map[string]any{
+		"owner": "octo", "repo": "r", "number": 1,
+	})
+	text := resultText(res)
+	if res.IsError {
+		t.Fatal(text)
+	}
+	for _, want := range []string{
+		"Reviewers of octo/r#1 from CODEOWNERS (4 changed files):\n",
+		"@octo/apps owns 2 of the changed files (already requested)\n",
+		"Files without owner: docs/generated/api.md\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("suggest-reviewers = %q, want %q", text, want)
+		}
+	}
+	// The author isn't suggested, and main.go is theirs alone.
+	if strings.Contains(text, "@alice") || strings.Contains(text, "@global") {
+		t.Errorf("suggest-reviewers = %q, want neither the author nor the overridden owner", text)
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultLargestFiles = 10
+	// maxCountedFileBytes bounds the files whose lines are counted, larger
+	// ones only add to the byte counts.
+	maxCountedFileBytes = 16 << 20
+)
+
+// languageExtensions maps file extensions, and the names of files without
+// one, to the language they are written in.
+var languageExtensions = map[string]string{
+	".go": "Go", ".mod": "Go Module", ".sum": "Go Module",
+	".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++", ".cxx": "C++", ".hh": "C++", ".hpp": "C++",
+	".cs": "C#", ".java": "Java", ".kt": "Kotlin", ".kts": "Kotlin", ".scala": "Scala", ".groovy": "Groovy",
+	".js": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript", ".jsx": "JavaScript",
+	".ts": "TypeScript", ".tsx": "TypeScript", ".vue": "Vue", ".svelte": "Svelte",
+	".py": "Python", ".pyi": "Python", ".rb": "Ruby", ".php": "PHP", ".pl": "Perl", ".lua": "Lua",
+	".rs": "Rust", ".swift": "Swift", ".m": "Objective-C", ".mm": "Objective-C", ".dart": "Dart",
+	".ex": "Elixir", ".exs": "Elixir", ".erl": "Erlang", ".hs": "Haskell", ".ml": "OCaml", ".clj": "Clojure",
+	".r": "R", ".jl": "Julia", ".zig": "Zig", ".nim": "Nim", ".tf": "HCL", ".hcl": "HCL",
+	".sh": "Shell", ".bash": "Shell", ".zsh": "Shell", ".ps1": "PowerShell", ".bat": "Batchfile",
+	".html": "HTML", ".htm": "HTML", ".css": "CSS", ".scss": "SCSS", ".sass": "Sass", ".less": "Less",
+	".sql": "SQL", ".proto": "Protocol Buffers", ".graphql": "GraphQL",
+	".json": "JSON", ".yaml": "YAML", ".yml": "YAML", ".toml": "TOML", ".xml": "XML", ".ini": "INI",
+	".md": "Markdown", ".rst": "reStructuredText", ".txt": "Text", ".tex": "TeX",
+	"Makefile": "Makefile", "Dockerfile": "Dockerfile", "CMakeLists.txt": "CMake", "Gemfile": "Ruby", "Rakefile": "Ruby",
+}
+
+var getCodeStatsTool = &mcp.Tool{
+	Name:        "get-code-stats",
+	Description: "A tool to break down a repository by language, with lines of code, file counts and its largest files, counted in a local clone made by clone-repository. Without a clone it falls back to the bytes per language reported by Github",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"handle": handleProperty(),
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes), used without handle",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl), used without handle",
+			},
+			"top": {
+				Type:        "integer",
+				Description: fmt.Sprintf("Number of largest files to list (defaults to %d)", defaultLargestFiles),
+				Minimum:     jsonschema.Ptr(0.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type GetCodeStatsArgs struct {
+	CommonArgs
+	Handle string `json:"handle,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+	Repo   string `json:"repo,omitempty"`
+	Top    *int   `json:"top,omitempty"`
+}
+
+type LanguageStats struct {
+	Language string `json:"language"`
+	// Files, Lines and Blank are only counted in clones.
+	Files int   `json:"files,omitempty"`
+	Lines int   `json:"lines,omitempty"`
+	Blank int   `json:"blank,omitempty"`
+	Bytes int64 `json:"bytes"`
+	// Percent is the share of the bytes of the repository.
+	Percent float64 `json:"percent"`
+}
+
+type LargeFile struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"`
+	Lines int    `json:"lines"`
+}
+
+type CodeStatsOutput struct {
+	// Source is clone or languages_api.
+	Source     string          `json:"source"`
+	Languages  []LanguageStats `json:"languages"`
+	TotalFiles int             `json:"total_files"`
+	TotalLines int             `json:"total_lines"`
+	TotalBytes int64           `json:"total_bytes"`
+	Largest    []LargeFile     `json:"largest"`
+}
+
+func (c *GithubClient) GetCodeStats(ctx context.Context, req *mcp.CallToolRequest, args GetCodeStatsArgs) (*mcp.CallToolResult, CodeStatsOutput, error) {
+	top := defaultLargestFiles
+	if args.Top != nil {
+		top = max(*args.Top, 0)
+	}
+	var out CodeStatsOutput
+	var name string
+	if args.Handle != "" {
+		dir, err := c.openClone(args.Handle)
+		if err != nil {
+			return nil, CodeStatsOutput{}, err
+		}
+		if out, err = cloneCodeStats(ctx, dir, top); err != nil {
+			return nil, CodeStatsOutput{}, err
+		}
+		name = args.Handle
+	} else {
+		if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+			return nil, CodeStatsOutput{}, err
+		}
+		if args.Owner == "" || args.Repo == "" {
+			return nil, CodeStatsOutput{}, fmt.Errorf("handle, or owner and repo, are required")
+		}
+		var languages map[string]int64
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/repos/%s/%s/languages", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo)), &languages); err != nil {
+			return nil, CodeStatsOutput{}, err
+		}
+		out = CodeStatsOutput{Source: "languages_api", Languages: []LanguageStats{}, Largest: []LargeFile{}}
+		for lang, n := range languages {
+			out.Languages = append(out.Languages, LanguageStats{Language: lang, Bytes: n})
+			out.TotalBytes += n
+		}
+		name = args.Owner + "/" + args.Repo
+	}
+	for i := range out.Languages {
+		if out.TotalBytes > 0 {
+			out.Languages[i].Percent = float64(out.Languages[i].Bytes*1000/out.TotalBytes) / 10
+		}
+	}
+	slices.SortFunc(out.Languages, func(a, b LanguageStats) int {
+		return cmp.Or(cmp.Compare(b.Lines, a.Lines), cmp.Compare(b.Bytes, a.Bytes), strings.Compare(a.Language, b.Language))
+	})
+
+	var result strings.Builder
+	if out.Source == "clone" {
+		fmt.Fprintf(&result, "%s: %d files, %d lines, %d bytes\n", name, out.TotalFiles, out.TotalLines, out.TotalBytes)
+		for _, l := range out.Languages {
+			fmt.Fprintf(&result, "%s: %d files, %d lines (%d blank), %d bytes (%.1f%%)\n", l.Language, l.Files, l.Lines, l.Blank, l.Bytes, l.Percent)
+		}
+		if len(out.Largest) > 0 {
+			fmt.Fprintf(&result, "\nLargest files:\n")
+			for _, f := range out.Largest {
+				fmt.Fprintf(&result, "%s: %d bytes, %d lines\n", f.Path, f.Size, f.Lines)
+			}
+		}
+	} else {
+		fmt.Fprintf(&result, "%s: %d bytes of code as detected by GitHub, clone it with clone-repository to count lines and files\n", name, out.TotalBytes)
+		for _, l := range out.Languages {
+			fmt.Fprintf(&result, "%s: %d bytes (%.1f%%)\n", l.Language, l.Bytes, l.Percent)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// cloneCodeStats counts the files of the clone at dir by language. Binary
+// files only count towards the totals and the largest files.
+func cloneCodeStats(ctx context.Context, dir string, top int) (CodeStatsOutput, error) {
+	out := CodeStatsOutput{Source: "clone", Languages: []LanguageStats{}, Largest: []LargeFile{}}
+	byLanguage := map[string]*LanguageStats{}
+	var files []LargeFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		file := LargeFile{Path: filepath.ToSlash(rel), Size: info.Size()}
+		out.TotalFiles++
+		out.TotalBytes += file.Size
+
+		var blank int
+		binary := false
+		if file.Size <= maxCountedFileBytes {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			// Like git, take a NUL in the first 8000 bytes for binary.
+			binary = bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+			if !binary {
+				for line := range bytes.Lines(data) {
+					file.Lines++
+					if len(bytes.TrimSpace(line)) == 0 {
+						blank++
+					}
+				}
+			}
+		}
+		files = append(files, file)
+		if binary {
+			return nil
+		}
+		lang := languageOf(file.Path)
+		stats := byLanguage[lang]
+		if stats == nil {
+			stats = &LanguageStats{Language: lang}
+			byLanguage[lang] = stats
+		}
+		stats.Files++
+		stats.Lines += file.Lines
+		stats.Blank += blank
+		stats.Bytes += file.Size
+		out.TotalLines += file.Lines
+		return nil
+	})
+	if err != nil {
+		return CodeStatsOutput{}, err
+	}
+	for _, stats := range byLanguage {
+		out.Languages = append(out.Languages, *stats)
+	}
+	slices.SortFunc(files, func(a, b LargeFile) int { return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Path, b.Path)) })
+	out.Largest = append(out.Largest, files[:min(top, len(files))]...)
+	return out, nil
+}
+
+// languageOf guesses the language of a file from its name.
+func languageOf(name string) string {
+	base := path.Base(name)
+	if lang, ok := languageExtensions[base]; ok {
+		return lang
+	}
+	if lang, ok := languageExtensions[strings.ToLower(path.Ext(base))]; ok {
+		return lang
+	}
+	return "Other"
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listCommitsTool = &mcp.Tool{
+	Name:        "list-commits",
+	Description: "A tool to list the commits of a Github repository, newest first",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to list commits from (defaults to the default branch)",
+			},
+			"author": {
+				Type:        "string",
+				Description: "Only return commits by this GitHub login or email address",
+			},
+			"path": {
+				Type:        "string",
+				Description: "Only return commits touching this file or directory",
+			},
+			"since": {
+				Type:        "string",
+				Description: "Only return commits after this date (e.g., 2024-01-31 or 2024-01-31T15:04:05Z)",
+			},
+			"until": {
+				Type:        "string",
+				Description: "Only return commits before this date (e.g., 2024-01-31 or 2024-01-31T15:04:05Z)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListCommitsArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Ref    string `json:"ref,omitempty"`
+	Author string `json:"author,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Since  string `json:"since,omitempty"`
+	Until  string `json:"until,omitempty"`
+}
+
+type Commit struct {
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+	HTMLURL string    `json:"html_url"`
+}
+
+type CommitListOutput struct {
+	Repository string   `json:"repository"`
+	Commits    []Commit `json:"commits"`
+}
+
+type commit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	// Author is the GitHub account matching the commit author, if any.
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+func (c *GithubClient) ListCommits(ctx context.Context, req *mcp.CallToolRequest, args ListCommitsArgs) (*mcp.CallToolResult, CommitListOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, CommitListOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, CommitListOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(c.perPage))
+	if args.Ref != "" {
+		query.Set("sha", args.Ref)
+	}
+	if args.Author != "" {
+		query.Set("author", args.Author)
+	}
+	if args.Path != "" {
+		query.Set("path", args.Path)
+	}
+	for name, value := range map[string]string{"since": args.Since, "until": args.Until} {
+		if value == "" {
+			continue
+		}
+		t, err := parseDate(value)
+		if err != nil {
+			return nil, CommitListOutput{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		query.Set(name, t.Format(time.RFC3339))
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/commits?%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), query.Encode())
+
+	raw, err := getAllPages[commit](ctx, c, apiURL)
+	if err != nil {
+		return nil, CommitListOutput{}, err
+	}
+	commits := make([]Commit, 0, len(raw))
+	var result strings.Builder
+	fmt.Fprintf(&result, "Commits for repository %s/%s:\n", args.Owner, args.Repo)
+	for _, rc := range raw {
+		cm := Commit{
+			SHA:     rc.SHA,
+			Author:  rc.Commit.Author.Name,
+			Date:    rc.Commit.Author.Date,
+			Message: summaryLine(rc.Commit.Message),
+			HTMLURL: rc.HTMLURL,
+		}
+		if rc.Author != nil && rc.Author.Login != "" {
+			cm.Author = rc.Author.Login
+		}
+		commits = append(commits, cm)
+		fmt.Fprintf(&result, "%.7s %s %s: %s\n", cm.SHA, cm.Date.Format(time.DateOnly), cm.Author, cm.Message)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, CommitListOutput{
+		Repository: args.Owner + "/" + args.Repo,
+		Commits:    commits,
+	}, nil
+}
+
+// parseDate accepts either a full RFC 3339 timestamp or a plain date.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
+
+// summaryLine returns the first line of a commit message.
+func summaryLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var auditCommunityProfilesTool = &mcp.Tool{
+	Name:        "audit-community-profiles",
+	Description: "A tool to audit the repositories of a Github organization or user for compliance reviews: the license of each repository, whether it has a README, CONTRIBUTING and CODE_OF_CONDUCT, and its community profile health percentage",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Organization or user whose repositories are audited (e.g., kubernetes)",
+			},
+			"include_archived": {
+				Type:        "boolean",
+				Description: "Also audit archived repositories",
+			},
+			"include_forks": {
+				Type:        "boolean",
+				Description: "Also audit forks",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type AuditCommunityProfilesArgs struct {
+	CommonArgs
+	Owner           string `json:"owner"`
+	IncludeArchived bool   `json:"include_archived,omitempty"`
+	IncludeForks    bool   `json:"include_forks,omitempty"`
+}
+
+type CommunityProfile struct {
+	Repository string `json:"repository"`
+	Private    bool   `json:"private"`
+	// License is the SPDX ID of the license, or its name when it has none,
+	// and is empty without a license.
+	License       string `json:"license"`
+	Readme        bool   `json:"readme"`
+	Contributing  bool   `json:"contributing"`
+	CodeOfConduct bool   `json:"code_of_conduct"`
+	// HealthPercentage is only computed by GitHub for public repositories.
+	HealthPercentage *int   `json:"health_percentage,omitempty"`
+	HTMLURL          string `json:"html_url"`
+}
+
+type CommunityAuditOutput struct {
+	Owner        string             `json:"owner"`
+	Repositories []CommunityProfile `json:"repositories"`
+	// The Missing counts tell how many repositories lack each file.
+	MissingLicense       int `json:"missing_license"`
+	MissingReadme        int `json:"missing_readme"`
+	MissingContributing  int `json:"missing_contributing"`
+	MissingCodeOfConduct int `json:"missing_code_of_conduct"`
+}
+
+type communityProfile struct {
+	HealthPercentage int `json:"health_percentage"`
+	Files            struct {
+		Readme            *struct{} `json:"readme"`
+		Contributing      *struct{} `json:"contributing"`
+		CodeOfConduct     *struct{} `json:"code_of_conduct"`
+		CodeOfConductFile *struct{} `json:"code_of_conduct_file"`
+		License           *struct {
+			SPDXID string `json:"spdx_id"`
+			Name   string `json:"name"`
+		} `json:"license"`
+	} `json:"files"`
+}
+
+func (c *GithubClient) AuditCommunityProfiles(ctx context.Context, req *mcp.CallToolRequest, args AuditCommunityProfilesArgs) (*mcp.CallToolResult, CommunityAuditOutput, error) {
+	if err := elicitMissing(ctx, req, "Which organization or user should be audited?", ownerField(&args.Owner)); err != nil {
+		return nil, CommunityAuditOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, CommunityAuditOutput{}, fmt.Errorf("owner is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	repos, err := c.accountRepositories(ctx, baseURL, args.Owner, "", nil)
+	if err != nil {
+		return nil, CommunityAuditOutput{}, err
+	}
+	repos = slices.DeleteFunc(repos, func(r Repository) bool {
+		return (r.Archived && !args.IncludeArchived) || (r.Fork && !args.IncludeForks)
+	})
+
+	// Repositories are audited batchWorkers at a time.
+	out := CommunityAuditOutput{Owner: args.Owner, Repositories: make([]CommunityProfile, len(repos))}
+	errs := make([]error, len(repos))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, repo := range repos {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			profile, err := c.communityProfile(ctx, baseURL, repo)
+			if err != nil {
+				errs[i] = fmt.Errorf("auditing %s: %w", repo.FullName, err)
+				return
+			}
+			out.Repositories[i] = profile
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, CommunityAuditOutput{}, err
+	}
+	slices.SortFunc(out.Repositories, func(a, b CommunityProfile) int { return strings.Compare(a.Repository, b.Repository) })
+	for _, p := range out.Repositories {
+		if p.License == "" {
+			out.MissingLicense++
+		}
+		if !p.Readme {
+			out.MissingReadme++
+		}
+		if !p.Contributing {
+			out.MissingContributing++
+		}
+		if !p.CodeOfConduct {
+			out.MissingCodeOfConduct++
+		}
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Community profiles of the %d repositories of %s\n", len(out.Repositories), args.Owner)
+	fmt.Fprintf(&result, "Missing: license %d, README %d, CONTRIBUTING %d, CODE_OF_CONDUCT %d\n",
+		out.MissingLicense, out.MissingReadme, out.MissingContributing, out.MissingCodeOfConduct)
+	yesNo := map[bool]string{true: "yes", false: "no"}
+	for _, p := range out.Repositories {
+		health := "n/a"
+		if p.HealthPercentage != nil {
+			health = fmt.Sprintf("%d%%", *p.HealthPercentage)
+		}
+		fmt.Fprintf(&result, "%s: license %s, README %s, CONTRIBUTING %s, CODE_OF_CONDUCT %s, health %s\n",
+			p.Repository, cmp.Or(p.License, "none"), yesNo[p.Readme], yesNo[p.Contributing], yesNo[p.CodeOfConduct], health)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// communityProfile audits repo through its community profile, which GitHub
+// only has for public repositories. The files of the others are looked for
+// where GitHub would find them: the root, .github and docs directories.
+func (c *GithubClient) communityProfile(ctx context.Context, baseURL string, repo Repository) (CommunityProfile, error) {
+	p := CommunityProfile{Repository: repo.FullName, Private: repo.Private, HTMLURL: repo.HTMLURL}
+	owner, name, _ := strings.Cut(repo.FullName, "/")
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", baseURL, url.PathEscape(owner), url.PathEscape(name))
+
+	var profile communityProfile
+	err := c.getJSON(ctx, repoURL+"/community/profile", &profile)
+	if err == nil {
+		files := profile.Files
+		p.HealthPercentage = &profile.HealthPercentage
+		p.Readme = files.Readme != nil
+		p.Contributing = files.Contributing != nil
+		p.CodeOfConduct = files.CodeOfConduct != nil || files.CodeOfConductFile != nil
+		if files.License != nil {
+			p.License = licenseName(files.License.SPDXID, files.License.Name)
+		}
+		return p, nil
+	}
+	if !isNotFound(err) {
+		return p, err
+	}
+
+	var license struct {
+		License struct {
+			SPDXID string `json:"spdx_id"`
+			Name   string `json:"name"`
+		} `json:"license"`
+	}
+	switch err := c.getJSON(ctx, repoURL+"/license", &license); {
+	case err == nil:
+		p.License = licenseName(license.License.SPDXID, license.License.Name)
+	case !isNotFound(err):
+		return p, err
+	}
+	for _, dir := range []string{"", ".github", "docs"} {
+		var entries []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		err := c.getJSON(ctx, repoURL+"/contents/"+dir, &entries)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return p, err
+		}
+		for _, e := range entries {
+			if e.Type != "file" {
+				continue
+			}
+			base, _, _ := strings.Cut(strings.ToUpper(e.Name), ".")
+			switch base {
+			case "README":
+				p.Readme = true
+			case "CONTRIBUTING":
+				p.Contributing = true
+			case "CODE_OF_CONDUCT":
+				p.CodeOfConduct = true
+			}
+		}
+	}
+	return p, nil
+}
+
+// licenseName prefers the SPDX ID of a license, which GitHub sets to
+// NOASSERTION for licenses it doesn't recognize.
+func licenseName(spdxID, name string) string {
+	if spdxID != "" && spdxID != "NOASSERTION" {
+		return spdxID
+	}
+	return cmp.Or(name, spdxID)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var compareRefsTool = &mcp.Tool{
+	Name:        "compare-refs",
+	Description: "A tool to summarize what changed between two branches, tags or commits of a Github repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"base": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to compare from (e.g., v1.2.0)",
+			},
+			"head": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to compare to (e.g., main)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "base", "head"},
+	},
+}
+
+type CompareRefsArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Base  string `json:"base"`
+	Head  string `json:"head"`
+}
+
+type ChangedFile struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+	// PreviousFilename is the name of a renamed file before the change.
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+}
+
+type CompareOutput struct {
+	Base string `json:"base"`
+	Head string `json:"head"`
+	// Status is one of ahead, behind, identical or diverged.
+	Status       string        `json:"status"`
+	AheadBy      int           `json:"ahead_by"`
+	BehindBy     int           `json:"behind_by"`
+	TotalCommits int           `json:"total_commits"`
+	Files        []ChangedFile `json:"files"`
+	HTMLURL      string        `json:"html_url"`
+}
+
+func (c *GithubClient) CompareRefs(ctx context.Context, req *mcp.CallToolRequest, args CompareRefsArgs) (*mcp.CallToolResult, CompareOutput, error) {
+	err := elicitMissing(ctx, req, "Which refs should be compared?", ownerField(&args.Owner), repoField(&args.Repo),
+		elicitField{name: "base", description: "Branch, tag or commit to compare from", value: &args.Base},
+		elicitField{name: "head", description: "Branch, tag or commit to compare to", value: &args.Head})
+	if err != nil {
+		return nil, CompareOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Base == "" || args.Head == "" {
+		return nil, CompareOutput{}, fmt.Errorf("owner, repo, base and head are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), escapePath(args.Base), escapePath(args.Head))
+
+	out := CompareOutput{Base: args.Base, Head: args.Head}
+	if err := c.getJSON(ctx, apiURL, &out); err != nil {
+		return nil, CompareOutput{}, err
+	}
+	if out.Files == nil {
+		out.Files = []ChangedFile{}
+	}
+	var additions, deletions int
+	for _, f := range out.Files {
+		additions += f.Additions
+		deletions += f.Deletions
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Comparing %s...%s in %s/%s: %s\n", args.Base, args.Head, args.Owner, args.Repo, out.Status)
+	fmt.Fprintf(&result, "%s is %d commits ahead and %d commits behind %s\n", args.Head, out.AheadBy, out.BehindBy, args.Base)
+	fmt.Fprintf(&result, "%d files changed, %d additions, %d deletions:\n", len(out.Files), additions, deletions)
+	for _, f := range out.Files {
+		fmt.Fprintf(&result, "%s [%s] +%d -%d\n", f.Filename, f.Status, f.Additions, f.Deletions)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
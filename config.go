@@ -0,0 +1,384 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting an operator can tune. Values are resolved in
+// this order, later sources winning:
+//
+//  1. the defaults from defaultConfig
+//  2. the YAML config file
+//  3. environment variables (MAGNET_<KEY>, and GITHUB_TOKEN for the token)
+//  4. command line flags (--<key> with underscores replaced by dashes)
+type Config struct {
+	GithubToken   string `yaml:"github_token"`
+	GithubBaseURL string `yaml:"github_base_url"`
+	// GithubAppID, when set, authenticates as an installation of that GitHub
+	// App instead of with GithubToken. The installation can be left out
+	// when the app has only one.
+	GithubAppID             int    `yaml:"github_app_id"`
+	GithubAppPrivateKeyFile string `yaml:"github_app_private_key_file"`
+	GithubAppInstallationID int    `yaml:"github_app_installation_id"`
+	Transport               string `yaml:"transport"`
+	HTTPAddr                string `yaml:"http_addr"`
+	// AdminAddr, when set, serves /metrics on a listener of its own
+	// instead of next to the MCP endpoint.
+	AdminAddr string `yaml:"admin_addr"`
+	// AdminDebug adds the pprof profiles and the expvar variables to the
+	// admin listener, which must then be on a loopback address.
+	AdminDebug     bool          `yaml:"admin_debug"`
+	Timeout        time.Duration `yaml:"timeout"`
+	PerPage        int           `yaml:"per_page"`
+	MaxPages       int           `yaml:"max_pages"`
+	MaxRetries     int           `yaml:"max_retries"`
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay"`
+	// CacheTTL is how long GitHub responses are reused, zero disables it.
+	CacheTTL  time.Duration `yaml:"cache_ttl"`
+	CacheSize int           `yaml:"cache_size"`
+	// CacheFile, when set, persists ETag validated responses across
+	// restarts in a database of at most CacheMaxBytes, evicting entries by
+	// CacheEviction: lru or fifo.
+	CacheFile     string `yaml:"cache_file"`
+	CacheMaxBytes int    `yaml:"cache_max_bytes"`
+	CacheEviction string `yaml:"cache_eviction"`
+	// OAuthClientID is the OAuth App used by --login, with OAuthScopes
+	// being the access requested.
+	OAuthClientID string `yaml:"oauth_client_id"`
+	OAuthScopes   string `yaml:"oauth_scopes"`
+	// Login runs the device flow login instead of the server. It can only
+	// be set on the command line.
+	Login bool `yaml:"-"`
+	// SubscriptionPollInterval is how often subscribed repositories are
+	// checked for updates, zero disables resource subscriptions.
+	SubscriptionPollInterval time.Duration `yaml:"subscription_poll_interval"`
+	// ReadOnly keeps the server from changing data on GitHub: the tools
+	// that would, like create-issue, aren't registered and the client
+	// refuses any request other than a read.
+	ReadOnly bool `yaml:"read_only"`
+	// DryRun makes the tools that change data on GitHub report the
+	// requests they would send instead of sending them. They are then
+	// registered even in read-only mode.
+	DryRun bool `yaml:"dry_run"`
+	// Allow and Deny are owner/repo patterns, like mycorp/* or
+	// */secrets-*, limiting the repositories the server may access.
+	Allow patternList `yaml:"allow"`
+	Deny  patternList `yaml:"deny"`
+	// GithubAPI is the API repositories are listed with: rest or graphql.
+	GithubAPI string `yaml:"github_api"`
+	// GitlabToken and GitlabBaseURL configure the gitlab provider, see
+	// Forge.
+	GitlabToken   string `yaml:"gitlab_token"`
+	GitlabBaseURL string `yaml:"gitlab_base_url"`
+	// BitbucketUsername and BitbucketToken configure the bitbucket
+	// provider. Without a username the token is an access token.
+	BitbucketUsername string `yaml:"bitbucket_username"`
+	BitbucketToken    string `yaml:"bitbucket_token"`
+	// GiteaToken and GiteaBaseURL configure the gitea provider, a Gitea or
+	// Forgejo instance, Codeberg by default.
+	GiteaToken   string `yaml:"gitea_token"`
+	GiteaBaseURL string `yaml:"gitea_base_url"`
+	// AzureDevOpsToken and AzureDevOpsBaseURL configure the azure
+	// provider.
+	AzureDevOpsToken   string `yaml:"azure_devops_token"`
+	AzureDevOpsBaseURL string `yaml:"azure_devops_base_url"`
+	// LogFile is where logs are written, stderr when empty. LogLevel is
+	// the least severe level logged, LogFormat text or json.
+	LogFile   string `yaml:"log_file"`
+	LogLevel  string `yaml:"log_level"`
+	LogFormat string `yaml:"log_format"`
+	// WireLog logs every MCP message the server reads and writes, with
+	// credentials redacted, to WireLogFile or else the log. WireLogFile is
+	// rotated past WireLogMaxBytes, keeping WireLogBackups older files.
+	WireLog         bool   `yaml:"wire_log"`
+	WireLogFile     string `yaml:"wire_log_file"`
+	WireLogMaxBytes int    `yaml:"wire_log_max_bytes"`
+	WireLogBackups  int    `yaml:"wire_log_backups"`
+	// AuditLogFile, when set, is appended a JSON line for every tool call.
+	AuditLogFile string `yaml:"audit_log_file"`
+	// ScratchDir is where repository archives are extracted for offline
+	// analysis, magnet in the temporary directory when empty. The least
+	// recently downloaded are removed once they take more than
+	// ScratchMaxBytes.
+	ScratchDir      string `yaml:"scratch_dir"`
+	ScratchMaxBytes int    `yaml:"scratch_max_bytes"`
+	// CloneDir is where clone-repository keeps its clones, evicting the
+	// least recently used once they take more than CloneMaxBytes.
+	CloneDir      string `yaml:"clone_dir"`
+	CloneMaxBytes int    `yaml:"clone_max_bytes"`
+	// Profiles are additional GitHub identities, selected per tool call.
+	// They can only be set in the config file.
+	Profiles map[string]ProfileConfig `yaml:"profiles"`
+}
+
+// ProfileConfig is a named set of GitHub credentials. Without a token the
+// one stored by --login or the gh CLI for its host is used.
+type ProfileConfig struct {
+	GithubToken   string `yaml:"github_token"`
+	GithubBaseURL string `yaml:"github_base_url"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		GithubBaseURL:            githubAPIURL,
+		Transport:                "stdio",
+		HTTPAddr:                 "localhost:8080",
+		Timeout:                  10 * time.Second,
+		PerPage:                  100,
+		MaxPages:                 defaultMaxPages,
+		MaxRetries:               3,
+		RetryBaseDelay:           time.Second,
+		RetryMaxDelay:            time.Minute,
+		CacheTTL:                 time.Minute,
+		CacheSize:                500,
+		CacheMaxBytes:            100 << 20,
+		CacheEviction:            "lru",
+		OAuthScopes:              "repo read:org",
+		SubscriptionPollInterval: time.Minute,
+		ReadOnly:                 true,
+		GithubAPI:                "rest",
+		GitlabBaseURL:            gitlabAPIURL,
+		GiteaBaseURL:             codebergAPIURL,
+		AzureDevOpsBaseURL:       azureDevOpsURL,
+		ScratchMaxBytes:          4 << 30,
+		CloneMaxBytes:            2 << 30,
+		LogLevel:                 "info",
+		LogFormat:                "text",
+		WireLogMaxBytes:          10 << 20,
+		WireLogBackups:           3,
+	}
+}
+
+// configField describes one Config setting. Its flag and environment
+// variable names are derived from key.
+type configField struct {
+	key   string
+	value any // pointer to the Config field
+	usage string
+	// env overrides the default MAGNET_<KEY> environment variable name.
+	env string
+}
+
+func (cfg *Config) fields() []configField {
+	return []configField{
+		{key: "github_token", value: &cfg.GithubToken, usage: "GitHub personal access token", env: "GITHUB_TOKEN"},
+		{key: "github_base_url", value: &cfg.GithubBaseURL, usage: "GitHub API base URL, e.g. https://github.mycorp.com/api/v3 for GitHub Enterprise Server"},
+		{key: "github_app_id", value: &cfg.GithubAppID, usage: "ID of a GitHub App to authenticate as instead of using a token"},
+		{key: "github_app_private_key_file", value: &cfg.GithubAppPrivateKeyFile, usage: "PEM private key of the GitHub App"},
+		{key: "github_app_installation_id", value: &cfg.GithubAppInstallationID, usage: "installation of the GitHub App to use, required when it is installed more than once"},
+		{key: "transport", value: &cfg.Transport, usage: "transport to serve MCP over: stdio or http"},
+		{key: "http_addr", value: &cfg.HTTPAddr, usage: "address to listen on when --transport=http"},
+		{key: "admin_addr", value: &cfg.AdminAddr, usage: "address to serve /metrics on, next to the MCP endpoint of --transport=http when empty"},
+		{key: "admin_debug", value: &cfg.AdminDebug, usage: "serve pprof at /debug/pprof/ and runtime variables at /debug/vars on the admin_addr, which must be a loopback address"},
+		{key: "timeout", value: &cfg.Timeout, usage: "how long a single GitHub API request may wait for its response"},
+		{key: "per_page", value: &cfg.PerPage, usage: "number of results requested per page from GitHub (at most 100)"},
+		{key: "max_pages", value: &cfg.MaxPages, usage: "maximum number of result pages fetched by a single listing"},
+		{key: "max_retries", value: &cfg.MaxRetries, usage: "how many times a rate limited or failed GitHub request is retried"},
+		{key: "retry_base_delay", value: &cfg.RetryBaseDelay, usage: "initial delay between retries, doubled on each attempt"},
+		{key: "retry_max_delay", value: &cfg.RetryMaxDelay, usage: "longest wait before a retry, including rate limit resets"},
+		{key: "cache_ttl", value: &cfg.CacheTTL, usage: "how long GitHub responses are served from the in-memory cache, 0 disables it"},
+		{key: "cache_size", value: &cfg.CacheSize, usage: "maximum number of GitHub responses kept in the in-memory cache"},
+		{key: "cache_file", value: &cfg.CacheFile, usage: "file to persist the ETag cache in across restarts, in memory when empty"},
+		{key: "cache_max_bytes", value: &cfg.CacheMaxBytes, usage: "size limit of the persistent cache in bytes"},
+		{key: "cache_eviction", value: &cfg.CacheEviction, usage: "eviction policy of the persistent cache: lru or fifo"},
+		{key: "oauth_client_id", value: &cfg.OAuthClientID, usage: "client ID of the OAuth App used by --login"},
+		{key: "oauth_scopes", value: &cfg.OAuthScopes, usage: "space separated scopes requested by --login"},
+		{key: "subscription_poll_interval", value: &cfg.SubscriptionPollInterval, usage: "how often subscribed repositories are polled for new pushes, 0 disables resource subscriptions"},
+		{key: "read_only", value: &cfg.ReadOnly, usage: "refuse to register or run the tools that create or change data on GitHub, --read-only=false enables them"},
+		{key: "dry_run", value: &cfg.DryRun, usage: "make the tools that change data on GitHub only report the requests they would send, registering them even when --read-only"},
+		{key: "allow", value: &cfg.Allow, usage: "comma separated owner/repo patterns, like mycorp/*, of the only repositories the server may access"},
+		{key: "deny", value: &cfg.Deny, usage: "comma separated owner/repo patterns, like */secrets-*, of repositories the server may not access"},
+		{key: "github_api", value: &cfg.GithubAPI, usage: "GitHub API to list repositories with: rest, or graphql to get their topics and latest release in fewer requests"},
+		{key: "gitlab_token", value: &cfg.GitlabToken, usage: "GitLab personal access token", env: "GITLAB_TOKEN"},
+		{key: "gitlab_base_url", value: &cfg.GitlabBaseURL, usage: "GitLab API base URL, e.g. https://gitlab.mycorp.com/api/v4 for a self-managed instance"},
+		{key: "bitbucket_username", value: &cfg.BitbucketUsername, usage: "Bitbucket Cloud username or email the bitbucket_token belongs to, when it is an API token or app password"},
+		{key: "bitbucket_token", value: &cfg.BitbucketToken, usage: "Bitbucket Cloud access token, API token or app password", env: "BITBUCKET_TOKEN"},
+		{key: "gitea_token", value: &cfg.GiteaToken, usage: "Gitea or Forgejo access token", env: "GITEA_TOKEN"},
+		{key: "gitea_base_url", value: &cfg.GiteaBaseURL, usage: "Gitea or Forgejo API base URL, e.g. https://git.mycorp.com/api/v1"},
+		{key: "azure_devops_token", value: &cfg.AzureDevOpsToken, usage: "Azure DevOps personal access token", env: "AZURE_DEVOPS_EXT_PAT"},
+		{key: "azure_devops_base_url", value: &cfg.AzureDevOpsBaseURL, usage: "Azure DevOps root URL, e.g. https://devops.mycorp.com/tfs for an Azure DevOps Server collection"},
+		{key: "log_file", value: &cfg.LogFile, usage: "file to append logs to instead of stderr"},
+		{key: "log_level", value: &cfg.LogLevel, usage: "least severe level logged: debug, info, warn or error"},
+		{key: "log_format", value: &cfg.LogFormat, usage: "format of the logs: text or json"},
+		{key: "wire_log", value: &cfg.WireLog, usage: "log every MCP message read and written, with credentials redacted"},
+		{key: "wire_log_file", value: &cfg.WireLogFile, usage: "file to write the wire log to instead of the log"},
+		{key: "wire_log_max_bytes", value: &cfg.WireLogMaxBytes, usage: "size in bytes past which the wire log file is rotated"},
+		{key: "wire_log_backups", value: &cfg.WireLogBackups, usage: "number of rotated wire log files kept"},
+		{key: "audit_log_file", value: &cfg.AuditLogFile, usage: "file to append a JSON line to for every tool call"},
+		{key: "scratch_dir", value: &cfg.ScratchDir, usage: "directory download-archive extracts repositories to, magnet in the temporary directory when empty"},
+		{key: "scratch_max_bytes", value: &cfg.ScratchMaxBytes, usage: "size limit of the extracted archives in bytes, the least recently downloaded are removed beyond it"},
+		{key: "clone_dir", value: &cfg.CloneDir, usage: "directory clone-repository keeps its clones in, magnet/clones in the user cache directory when empty"},
+		{key: "clone_max_bytes", value: &cfg.CloneMaxBytes, usage: "size limit of the clones in bytes, the least recently used are removed beyond it"},
+	}
+}
+
+// loadConfig resolves the configuration from the config file, the
+// environment and the command line arguments.
+func loadConfig(args []string) (*Config, error) {
+	cfg := defaultConfig()
+	flags := flag.NewFlagSet("magnet", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a YAML config file (defaults to magnet/config.yaml in the user config directory, if present)")
+	login := flags.Bool("login", false, "log in to GitHub in the browser, or save the given --github-token, and store the token in the system keyring, then exit")
+	for _, f := range cfg.fields() {
+		name := strings.ReplaceAll(f.key, "_", "-")
+		switch v := f.value.(type) {
+		case *string:
+			flags.StringVar(v, name, *v, f.usage)
+		case *int:
+			flags.IntVar(v, name, *v, f.usage)
+		case *time.Duration:
+			flags.DurationVar(v, name, *v, f.usage)
+		case *bool:
+			flags.BoolVar(v, name, *v, f.usage)
+		case *patternList:
+			flags.Var(v, name, f.usage)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// Flags have to be parsed first to find the config file, but they must
+	// win over it, so remember the explicit ones and apply them again last.
+	explicit := map[string]string{}
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+	cfg = defaultConfig()
+	if err := cfg.loadFile(*configPath); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	for name, value := range explicit {
+		if err := flags.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Transport != "stdio" && cfg.Transport != "http" {
+		return nil, fmt.Errorf("unknown transport %q, expected stdio or http", cfg.Transport)
+	}
+	if cfg.PerPage <= 0 || cfg.PerPage > 100 {
+		return nil, fmt.Errorf("per_page must be between 1 and 100, got %d", cfg.PerPage)
+	}
+	if cfg.GithubAppID != 0 && cfg.GithubAppPrivateKeyFile == "" {
+		return nil, fmt.Errorf("github_app_private_key_file is required with github_app_id")
+	}
+	// The token would otherwise silently win over the app, or the other way
+	// around, and the server run with other access than intended.
+	if cfg.GithubAppID != 0 && cfg.GithubToken != "" {
+		return nil, fmt.Errorf("github_app_id and github_token (or GITHUB_TOKEN) can't both be set, unset one of them")
+	}
+	if cfg.CacheSize <= 0 {
+		return nil, fmt.Errorf("cache_size must be positive, got %d", cfg.CacheSize)
+	}
+	if cfg.CacheMaxBytes <= 0 {
+		return nil, fmt.Errorf("cache_max_bytes must be positive, got %d", cfg.CacheMaxBytes)
+	}
+	if cfg.ScratchMaxBytes <= 0 {
+		return nil, fmt.Errorf("scratch_max_bytes must be positive, got %d", cfg.ScratchMaxBytes)
+	}
+	if cfg.CloneMaxBytes <= 0 {
+		return nil, fmt.Errorf("clone_max_bytes must be positive, got %d", cfg.CloneMaxBytes)
+	}
+	if cfg.CacheEviction != "lru" && cfg.CacheEviction != "fifo" {
+		return nil, fmt.Errorf("unknown cache_eviction %q, expected lru or fifo", cfg.CacheEviction)
+	}
+	if _, err := parseLogLevel(cfg.LogLevel); err != nil {
+		return nil, err
+	}
+	if cfg.LogFormat != "text" && cfg.LogFormat != "json" {
+		return nil, fmt.Errorf("unknown log_format %q, expected text or json", cfg.LogFormat)
+	}
+	if cfg.WireLogMaxBytes <= 0 {
+		return nil, fmt.Errorf("wire_log_max_bytes must be positive, got %d", cfg.WireLogMaxBytes)
+	}
+	if cfg.WireLogBackups < 0 {
+		return nil, fmt.Errorf("wire_log_backups can't be negative, got %d", cfg.WireLogBackups)
+	}
+	if cfg.AdminDebug && !isLoopback(cfg.AdminAddr) {
+		return nil, fmt.Errorf("admin_debug needs an admin_addr on a loopback address, like localhost:9090, got %q", cfg.AdminAddr)
+	}
+	if cfg.GithubAPI != "rest" && cfg.GithubAPI != "graphql" {
+		return nil, fmt.Errorf("unknown github_api %q, expected rest or graphql", cfg.GithubAPI)
+	}
+	if err := cfg.Allow.validate(); err != nil {
+		return nil, fmt.Errorf("allow: %w", err)
+	}
+	if err := cfg.Deny.validate(); err != nil {
+		return nil, fmt.Errorf("deny: %w", err)
+	}
+	cfg.Login = *login
+	return &cfg, nil
+}
+
+// loadFile reads the YAML config file at path. When path is empty the
+// default location is tried, and it's fine for it not to exist.
+func (cfg *Config) loadFile(path string) error {
+	explicit := path != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(dir, "magnet", "config.yaml")
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overrides settings from their environment variables.
+func (cfg *Config) loadEnv() error {
+	for _, f := range cfg.fields() {
+		name := f.env
+		if name == "" {
+			name = "MAGNET_" + strings.ToUpper(f.key)
+		}
+		s, ok := os.LookupEnv(name)
+		if !ok || s == "" {
+			continue
+		}
+		var err error
+		switch v := f.value.(type) {
+		case *string:
+			*v = s
+		case *int:
+			*v, err = strconv.Atoi(s)
+		case *time.Duration:
+			*v, err = time.ParseDuration(s)
+		case *bool:
+			*v, err = strconv.ParseBool(s)
+		case *patternList:
+			err = v.Set(s)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
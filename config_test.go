@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigRejectsAppAndToken(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "ghp_test")
+	_, err := loadConfig([]string{"--github-app-id=1", "--github-app-private-key-file=app.pem"})
+	if err == nil || !strings.Contains(err.Error(), "can't both be set") {
+		t.Fatalf("loadConfig() error = %v, want a conflict", err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	cfg, err := loadConfig([]string{"--github-app-id=1", "--github-app-private-key-file=app.pem"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GithubAppID != 1 {
+		t.Errorf("GithubAppID = %d, want 1", cfg.GithubAppID)
+	}
+}
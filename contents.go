@@ -0,0 +1,548 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMaxFileBytes is how much of a file is returned when the caller
+// doesn't ask for a specific limit. Large files are truncated rather than
+// flooding the client's context window.
+const defaultMaxFileBytes = 100 * 1024
+
+var getFileContentsTool = &mcp.Tool{
+	Name:        "get-file-contents",
+	Description: "A tool to read a file from a Github repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"path": {
+				Type:        "string",
+				Description: "Path of the file inside the repository (e.g., cmd/kubectl/kubectl.go)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to read from (defaults to the default branch)",
+			},
+			"max_bytes": {
+				Type:        "integer",
+				Description: "Maximum number of bytes to return, the rest of the file is truncated (defaults to 102400)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"provider": providerProperty(),
+		},
+		Required: []string{"owner", "repo", "path"},
+	},
+}
+
+type GetFileContentsArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	Path     string `json:"path"`
+	Ref      string `json:"ref,omitempty"`
+	MaxBytes int    `json:"max_bytes,omitempty"`
+}
+
+type fileContent struct {
+	Type     string `json:"type"`
+	Encoding string `json:"encoding"`
+	Size     int    `json:"size"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+}
+
+func (c *GithubClient) GetFileContents(ctx context.Context, req *mcp.CallToolRequest, args GetFileContentsArgs) (*mcp.CallToolResult, any, error) {
+	err := elicitMissing(ctx, req, "Which file should be read?", ownerField(&args.Owner), repoField(&args.Repo),
+		elicitField{name: "path", description: "Path of the file in the repository (e.g., README.md)", value: &args.Path})
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Path == "" {
+		return nil, nil, fmt.Errorf("owner, repo and path are required")
+	}
+	maxBytes := args.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxFileBytes
+	}
+
+	forge, err := c.forge(args.CommonArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := forge.GetFile(ctx, args.Owner, args.Repo, args.Path, args.Ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := file.Content
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, nil, fmt.Errorf("%s is a binary file (%d bytes)", file.Path, file.Size)
+	}
+	text := string(data)
+	if len(data) > maxBytes {
+		n := runeBoundary(data, maxBytes)
+		text = fmt.Sprintf("%s\n[... truncated, showing %d of %d bytes ...]", data[:n], n, len(data))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// getFileContent fetches a single file through the contents API.
+func (c *GithubClient) getFileContent(ctx context.Context, baseURL, owner, repo, path, ref string) (*fileContent, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s", baseURL, url.PathEscape(owner), url.PathEscape(repo), escapePath(path))
+	if ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(ref)
+	}
+
+	// The endpoint answers with an array when path is a directory.
+	var raw json.RawMessage
+	if err := c.getJSON(ctx, apiURL, &raw); err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return nil, fmt.Errorf("%s is a directory, not a file", path)
+	}
+	var file fileContent
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if file.Type != "file" {
+		return nil, fmt.Errorf("%s is a %s, not a file", path, file.Type)
+	}
+	return &file, nil
+}
+
+// decodeFileContent returns the raw bytes of a file returned by the contents API.
+func decodeFileContent(file *fileContent) ([]byte, error) {
+	switch file.Encoding {
+	case "base64":
+		// GitHub wraps the base64 payload at 60 columns.
+		data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file.Path, err)
+		}
+		return data, nil
+	case "none":
+		// Files over 1MB are listed without their content.
+		return nil, fmt.Errorf("%s is too large to be fetched through the contents API (%d bytes)", file.Path, file.Size)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q for %s", file.Encoding, file.Path)
+	}
+}
+
+// escapePath escapes each segment of a slash separated repository path.
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// runeBoundary returns where to cut data to keep at most n bytes without
+// splitting a UTF-8 encoded character.
+func runeBoundary(data []byte, n int) int {
+	if n >= len(data) {
+		return len(data)
+	}
+	for i := n; i >= 0 && i > n-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			return i
+		}
+	}
+	// Not UTF-8, any byte will do.
+	return n
+}
+
+// bytesPerToken is a rough estimate used to turn a token budget into a size.
+const bytesPerToken = 4
+
+var getReadmeTool = &mcp.Tool{
+	Name:        "get-readme",
+	Description: "A tool to read the README of a Github repository as Markdown",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to read from (defaults to the default branch)",
+			},
+			"dir": {
+				Type:        "string",
+				Description: "Directory to look for the README in (defaults to the repository root)",
+			},
+			"max_tokens": {
+				Type:        "integer",
+				Description: "Approximate token budget, the README is truncated to fit it",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type GetReadmeArgs struct {
+	CommonArgs
+	Owner     string `json:"owner"`
+	Repo      string `json:"repo"`
+	Ref       string `json:"ref,omitempty"`
+	Dir       string `json:"dir,omitempty"`
+	MaxTokens int    `json:"max_tokens,omitempty"`
+}
+
+func (c *GithubClient) GetReadme(ctx context.Context, req *mcp.CallToolRequest, args GetReadmeArgs) (*mcp.CallToolResult, any, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, nil, fmt.Errorf("owner and repo are required")
+	}
+	// The readme endpoint picks the file the same way github.com does
+	// (README.md, README.rst, docs/README...).
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/readme", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	if args.Dir != "" {
+		apiURL += "/" + escapePath(args.Dir)
+	}
+	if args.Ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(args.Ref)
+	}
+
+	var file fileContent
+	if err := c.getJSON(ctx, apiURL, &file); err != nil {
+		return nil, nil, err
+	}
+	data, err := decodeFileContent(&file)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := string(data)
+	if maxBytes := args.MaxTokens * bytesPerToken; maxBytes > 0 && len(data) > maxBytes {
+		n := runeBoundary(data, maxBytes)
+		text = fmt.Sprintf("%s\n\n[... truncated to fit %d tokens, showing %d of %d bytes ...]", data[:n], args.MaxTokens, n, len(data))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+var listDirectoryTool = &mcp.Tool{
+	Name:        "list-directory",
+	Description: "A tool to list the files and directories at a path of a Github repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"path": {
+				Type:        "string",
+				Description: "Directory to list (defaults to the repository root)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to list (defaults to the default branch)",
+			},
+			"recursive": {
+				Type:        "boolean",
+				Description: "Also list the contents of every subdirectory",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListDirectoryArgs struct {
+	CommonArgs
+	Owner     string `json:"owner"`
+	Repo      string `json:"repo"`
+	Path      string `json:"path,omitempty"`
+	Ref       string `json:"ref,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+type DirectoryEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Type is one of file, dir, symlink or submodule.
+	Type string `json:"type"`
+	Size int    `json:"size"`
+	// SHA is the git object SHA, needed to update a file.
+	SHA string `json:"sha"`
+}
+
+type DirectoryListOutput struct {
+	Repository string           `json:"repository"`
+	Path       string           `json:"path"`
+	Entries    []DirectoryEntry `json:"entries"`
+	// Truncated is set when GitHub returned only part of a large tree.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func (c *GithubClient) ListDirectory(ctx context.Context, req *mcp.CallToolRequest, args ListDirectoryArgs) (*mcp.CallToolResult, DirectoryListOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, DirectoryListOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, DirectoryListOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	dir := strings.Trim(args.Path, "/")
+	out := DirectoryListOutput{
+		Repository: args.Owner + "/" + args.Repo,
+		Path:       "/" + dir,
+		Entries:    []DirectoryEntry{},
+	}
+
+	if args.Recursive {
+		entries, truncated, err := c.treeEntries(ctx, repoURL, args.Ref, dir)
+		if err != nil {
+			return nil, DirectoryListOutput{}, err
+		}
+		out.Entries = append(out.Entries, entries...)
+		out.Truncated = truncated
+	} else {
+		apiURL := repoURL + "/contents/" + escapePath(dir)
+		if args.Ref != "" {
+			apiURL += "?ref=" + url.QueryEscape(args.Ref)
+		}
+		var raw json.RawMessage
+		if err := c.getJSON(ctx, apiURL, &raw); err != nil {
+			return nil, DirectoryListOutput{}, err
+		}
+		if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			return nil, DirectoryListOutput{}, fmt.Errorf("%s is a file, not a directory", args.Path)
+		}
+		if err := json.Unmarshal(raw, &out.Entries); err != nil {
+			return nil, DirectoryListOutput{}, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Contents of %s in %s:\n", out.Path, out.Repository)
+	for _, e := range out.Entries {
+		switch e.Type {
+		case "dir":
+			fmt.Fprintf(&result, "%s/\n", e.Path)
+		case "file":
+			fmt.Fprintf(&result, "%s (%d bytes)\n", e.Path, e.Size)
+		default:
+			fmt.Fprintf(&result, "%s (%s)\n", e.Path, e.Type)
+		}
+	}
+	if out.Truncated {
+		result.WriteString("[... the tree is too large and was truncated by GitHub, list a subdirectory instead ...]\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// treeEntries lists everything below dir at ref using the git trees API,
+// which returns a whole tree in a single request.
+func (c *GithubClient) treeEntries(ctx context.Context, repoURL, ref, dir string) ([]DirectoryEntry, bool, error) {
+	if ref == "" {
+		var repo repositoryDetails
+		if err := c.getJSON(ctx, repoURL, &repo); err != nil {
+			return nil, false, err
+		}
+		ref = repo.DefaultBranch
+	}
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			Mode string `json:"mode"`
+			Size int    `json:"size"`
+			SHA  string `json:"sha"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/git/trees/%s?recursive=1", repoURL, url.PathEscape(ref)), &tree); err != nil {
+		return nil, false, err
+	}
+
+	entries := []DirectoryEntry{}
+	for _, t := range tree.Tree {
+		if dir != "" && !strings.HasPrefix(t.Path, dir+"/") {
+			continue
+		}
+		e := DirectoryEntry{Name: path.Base(t.Path), Path: t.Path, Size: t.Size, SHA: t.SHA}
+		// Translate git object types to the contents API vocabulary.
+		switch {
+		case t.Type == "tree":
+			e.Type = "dir"
+		case t.Type == "commit":
+			e.Type = "submodule"
+		case t.Mode == "120000":
+			e.Type = "symlink"
+		default:
+			e.Type = "file"
+		}
+		entries = append(entries, e)
+	}
+	return entries, tree.Truncated, nil
+}
+
+var createOrUpdateFileTool = &mcp.Tool{
+	Name:        "create-or-update-file",
+	Description: "A tool to commit a new or changed file to a branch of a Github repository",
+	Annotations: writeAnnotations(true, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"path": {
+				Type:        "string",
+				Description: "Path of the file in the repository (e.g., version.txt)",
+			},
+			"content": {
+				Type:        "string",
+				Description: "New content of the file",
+			},
+			"message": {
+				Type:        "string",
+				Description: "Commit message",
+			},
+			"branch": {
+				Type:        "string",
+				Description: "Branch to commit to (defaults to the default branch)",
+			},
+			"sha": {
+				Type:        "string",
+				Description: "Blob SHA of the file being replaced, as listed by list-directory; required to update an existing file",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "path", "content", "message"},
+	},
+}
+
+type CreateOrUpdateFileArgs struct {
+	CommonArgs
+	Owner   string `json:"owner"`
+	Repo    string `json:"repo"`
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	Message string `json:"message"`
+	Branch  string `json:"branch,omitempty"`
+	SHA     string `json:"sha,omitempty"`
+}
+
+type FileCommit struct {
+	Path string `json:"path"`
+	// SHA is the blob SHA of the new content, to pass on the next update.
+	SHA       string `json:"sha"`
+	CommitSHA string `json:"commit_sha"`
+	HTMLURL   string `json:"html_url"`
+}
+
+func (c *GithubClient) CreateOrUpdateFile(ctx context.Context, req *mcp.CallToolRequest, args CreateOrUpdateFileArgs) (*mcp.CallToolResult, FileCommit, error) {
+	if args.Owner == "" || args.Repo == "" || args.Path == "" || args.Message == "" {
+		return nil, FileCommit{}, fmt.Errorf("owner, repo, path and message are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), escapePath(args.Path))
+	payload := map[string]string{
+		"message": args.Message,
+		"content": base64.StdEncoding.EncodeToString([]byte(args.Content)),
+	}
+	if args.Branch != "" {
+		payload["branch"] = args.Branch
+	}
+	if args.SHA != "" {
+		payload["sha"] = args.SHA
+	}
+
+	var resp struct {
+		Content struct {
+			Path string `json:"path"`
+			SHA  string `json:"sha"`
+		} `json:"content"`
+		Commit struct {
+			SHA     string `json:"sha"`
+			HTMLURL string `json:"html_url"`
+		} `json:"commit"`
+	}
+	if err := c.sendJSON(ctx, http.MethodPut, apiURL, payload, &resp); err != nil {
+		return nil, FileCommit{}, err
+	}
+	out := FileCommit{
+		Path:      resp.Content.Path,
+		SHA:       resp.Content.SHA,
+		CommitSHA: resp.Commit.SHA,
+		HTMLURL:   resp.Commit.HTMLURL,
+	}
+
+	verb := "Created"
+	if args.SHA != "" {
+		verb = "Updated"
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s %s in %s/%s with commit %s: %s\n", verb, out.Path, args.Owner, args.Repo, out.CommitSHA, out.HTMLURL)},
+		},
+	}, out, nil
+}
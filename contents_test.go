@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRuneBoundary(t *testing.T) {
+	data := []byte("aé€😀") // 1, 2, 3 and 4 bytes long
+	for n, want := range map[int]int{
+		0: 0, 1: 1, 2: 1, 3: 3, 4: 3, 5: 3, 6: 6, 7: 6, 8: 6, 9: 6, 10: 10, 11: 10,
+	} {
+		got := runeBoundary(data, n)
+		if got != want {
+			t.Errorf("runeBoundary(%q, %d) = %d, want %d", data, n, got, want)
+		}
+		if !utf8.Valid(data[:got]) {
+			t.Errorf("runeBoundary(%q, %d) splits a character", data, n)
+		}
+	}
+	if got := runeBoundary([]byte{0x80, 0x80, 0x80, 0x80, 0x80}, 4); got != 4 {
+		t.Errorf("runeBoundary of invalid UTF-8 = %d, want 4", got)
+	}
+}
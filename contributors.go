@@ -0,0 +1,121 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listContributorsTool = &mcp.Tool{
+	Name:        "list-contributors",
+	Description: "A tool to list the contributors of a Github repository, or of every repository in an organization when repo is omitted",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository, or the organization to aggregate over (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl). Omit it to aggregate contributions across all repositories of the organization",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type ListContributorsArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo,omitempty"`
+}
+
+type Contributor struct {
+	Login         string `json:"login"`
+	Contributions int    `json:"contributions"`
+	HTMLURL       string `json:"html_url"`
+}
+
+type ContributorListOutput struct {
+	// Scope is the repository (owner/repo) or organization the
+	// contributions were counted over.
+	Scope        string        `json:"scope"`
+	Contributors []Contributor `json:"contributors"`
+}
+
+func (c *GithubClient) ListContributors(ctx context.Context, req *mcp.CallToolRequest, args ListContributorsArgs) (*mcp.CallToolResult, ContributorListOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose contributors should be listed?", ownerField(&args.Owner)); err != nil {
+		return nil, ContributorListOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, ContributorListOutput{}, fmt.Errorf("owner is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+
+	var repos []string
+	scope := args.Owner
+	if args.Repo != "" {
+		repos = []string{args.Repo}
+		scope = args.Owner + "/" + args.Repo
+	} else {
+		ownerRepos, err := c.accountRepositories(ctx, baseURL, args.Owner, "", nil)
+		if err != nil {
+			return nil, ContributorListOutput{}, err
+		}
+		for _, r := range ownerRepos {
+			repos = append(repos, r.Name)
+		}
+	}
+
+	byLogin := map[string]*Contributor{}
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return nil, ContributorListOutput{}, err
+		}
+		apiURL := fmt.Sprintf("%s/repos/%s/%s/contributors?per_page=%d", baseURL, url.PathEscape(args.Owner), url.PathEscape(repo), c.perPage)
+		contributors, err := getAllPages[Contributor](ctx, c, apiURL)
+		if err != nil {
+			return nil, ContributorListOutput{}, fmt.Errorf("listing contributors of %s/%s: %w", args.Owner, repo, err)
+		}
+		for _, contributor := range contributors {
+			if existing, ok := byLogin[contributor.Login]; ok {
+				existing.Contributions += contributor.Contributions
+				continue
+			}
+			byLogin[contributor.Login] = &contributor
+		}
+	}
+	contributors := []Contributor{}
+	for _, contributor := range byLogin {
+		contributors = append(contributors, *contributor)
+	}
+	slices.SortFunc(contributors, func(a, b Contributor) int {
+		return cmp.Or(cmp.Compare(b.Contributions, a.Contributions), strings.Compare(a.Login, b.Login))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Contributors to %s:\n", scope)
+	for _, contributor := range contributors {
+		fmt.Fprintf(&result, "%s: %d contributions %s\n", contributor.Login, contributor.Contributions, contributor.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, ContributorListOutput{
+		Scope:        scope,
+		Contributors: contributors,
+	}, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listDependabotAlertsTool = &mcp.Tool{
+	Name:        "list-dependabot-alerts",
+	Description: "A tool to list the open Dependabot alerts of a Github repository, or of every repository of an organization, with the vulnerable package, severity, CVE and the version fixing it. The token needs the security_events scope",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository, or the organization to list the alerts of (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl). Omit it to list the alerts of every repository of the organization",
+			},
+			"severity": {
+				Type:        "string",
+				Description: "Only return alerts of this severity",
+				Enum:        []any{"low", "medium", "high", "critical"},
+			},
+			"ecosystem": {
+				Type:        "string",
+				Description: "Only return alerts of packages of this ecosystem (e.g., npm, pip, go, maven)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of alerts to return, most recent first (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type ListDependabotAlertsArgs struct {
+	CommonArgs
+	Owner     string `json:"owner"`
+	Repo      string `json:"repo,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+	Ecosystem string `json:"ecosystem,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+type DependabotAlert struct {
+	Repository   string `json:"repository"`
+	Number       int    `json:"number"`
+	Package      string `json:"package"`
+	Ecosystem    string `json:"ecosystem"`
+	ManifestPath string `json:"manifest_path"`
+	Severity     string `json:"severity"`
+	GHSA         string `json:"ghsa"`
+	CVE          string `json:"cve,omitempty"`
+	Summary      string `json:"summary"`
+	// VulnerableRange is the range of vulnerable versions, like < 1.2.3,
+	// and FixedVersion the first one patched, empty when there is none.
+	VulnerableRange string    `json:"vulnerable_range"`
+	FixedVersion    string    `json:"fixed_version,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	HTMLURL         string    `json:"html_url"`
+}
+
+type DependabotAlertsOutput struct {
+	Alerts []DependabotAlert `json:"alerts"`
+}
+
+type dependabotAlert struct {
+	Number     int `json:"number"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		ManifestPath string `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID   string `json:"ghsa_id"`
+		CVEID    string `json:"cve_id"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"security_advisory"`
+	SecurityVulnerability struct {
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+		FirstPatchedVersion    *struct {
+			Identifier string `json:"identifier"`
+		} `json:"first_patched_version"`
+	} `json:"security_vulnerability"`
+	CreatedAt time.Time `json:"created_at"`
+	HTMLURL   string    `json:"html_url"`
+	// Repository is only set by the organization endpoint.
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (c *GithubClient) ListDependabotAlerts(ctx context.Context, req *mcp.CallToolRequest, args ListDependabotAlertsArgs) (*mcp.CallToolResult, DependabotAlertsOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose Dependabot alerts should be listed?", ownerField(&args.Owner)); err != nil {
+		return nil, DependabotAlertsOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, DependabotAlertsOutput{}, fmt.Errorf("owner is required")
+	}
+	query := url.Values{}
+	query.Set("state", "open")
+	query.Set("sort", "created")
+	query.Set("direction", "desc")
+	query.Set("per_page", fmt.Sprint(cmp.Or(args.Limit, 30)))
+	if args.Severity != "" {
+		query.Set("severity", args.Severity)
+	}
+	if args.Ecosystem != "" {
+		query.Set("ecosystem", args.Ecosystem)
+	}
+	apiURL, target := c.alertsURL(args.CommonArgs, args.Owner, args.Repo, "dependabot", query)
+
+	var alerts []dependabotAlert
+	if err := c.getJSON(ctx, apiURL, &alerts); err != nil {
+		return nil, DependabotAlertsOutput{}, scopeError(err, "listing the Dependabot alerts of "+target, "security_events", "Dependabot alerts")
+	}
+
+	out := DependabotAlertsOutput{Alerts: []DependabotAlert{}}
+	for _, a := range alerts {
+		// Organization alerts span its repositories, some maybe out of
+		// scope.
+		if !c.scope.allowsFullName(cmp.Or(a.Repository.FullName, target)) {
+			continue
+		}
+		alert := DependabotAlert{
+			Repository:      cmp.Or(a.Repository.FullName, target),
+			Number:          a.Number,
+			Package:         a.Dependency.Package.Name,
+			Ecosystem:       a.Dependency.Package.Ecosystem,
+			ManifestPath:    a.Dependency.ManifestPath,
+			Severity:        a.SecurityAdvisory.Severity,
+			GHSA:            a.SecurityAdvisory.GHSAID,
+			CVE:             a.SecurityAdvisory.CVEID,
+			Summary:         a.SecurityAdvisory.Summary,
+			VulnerableRange: a.SecurityVulnerability.VulnerableVersionRange,
+			CreatedAt:       a.CreatedAt,
+			HTMLURL:         a.HTMLURL,
+		}
+		if a.SecurityVulnerability.FirstPatchedVersion != nil {
+			alert.FixedVersion = a.SecurityVulnerability.FirstPatchedVersion.Identifier
+		}
+		out.Alerts = append(out.Alerts, alert)
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d open Dependabot alerts in %s:\n", len(out.Alerts), target)
+	for _, a := range out.Alerts {
+		id := a.GHSA
+		if a.CVE != "" {
+			id = a.CVE + ", " + a.GHSA
+		}
+		fmt.Fprintf(&result, "%s#%d [%s] %s %s %s (%s) in %s: %s, fixed in %s %s\n",
+			a.Repository, a.Number, a.Severity, a.Ecosystem, a.Package, a.VulnerableRange, id, a.ManifestPath, a.Summary, cmp.Or(a.FixedVersion, "no version yet"), a.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// dependencyManifest is a kind of manifest find-dependency-usage reads the
+// version of a dependency from.
+type dependencyManifest struct {
+	ecosystem string
+	filename  string
+	parse     func(data []byte, dependency string) ([]dependencyVersion, error)
+}
+
+var dependencyManifests = []dependencyManifest{
+	{ecosystem: "go", filename: "go.mod", parse: parseGoMod},
+	{ecosystem: "npm", filename: "package.json", parse: parsePackageJSON},
+	{ecosystem: "pip", filename: "requirements.txt", parse: parseRequirements},
+}
+
+var findDependencyUsageTool = &mcp.Tool{
+	Name:        "find-dependency-usage",
+	Description: "A tool to find which repositories of a Github organization or user depend on a package, and on which version, by searching their go.mod, package.json and requirements.txt files. Requires a GitHub token",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Organization or user whose repositories are searched (e.g., kubernetes)",
+			},
+			"dependency": {
+				Type:        "string",
+				Description: "Name of the package as written in the manifests (e.g., github.com/spf13/cobra, react or requests)",
+			},
+			"ecosystem": {
+				Type:        "string",
+				Description: "Only search the manifests of this ecosystem (defaults to all)",
+				Enum:        []any{"go", "npm", "pip"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "dependency"},
+	},
+}
+
+type FindDependencyUsageArgs struct {
+	CommonArgs
+	Owner      string `json:"owner"`
+	Dependency string `json:"dependency"`
+	Ecosystem  string `json:"ecosystem,omitempty"`
+}
+
+type DependencyUsage struct {
+	Repository string `json:"repository"`
+	Ecosystem  string `json:"ecosystem"`
+	Path       string `json:"path"`
+	// Version is the version or version specifier the manifest asks for.
+	Version string `json:"version"`
+	// Kind tells how the package is depended on, like indirect in go.mod or
+	// dev in package.json, and is empty for direct dependencies.
+	Kind    string `json:"kind,omitempty"`
+	HTMLURL string `json:"html_url"`
+}
+
+type DependencyVersionUsage struct {
+	Version      string   `json:"version"`
+	Repositories []string `json:"repositories"`
+}
+
+type DependencyUsageOutput struct {
+	Dependency string                   `json:"dependency"`
+	Usages     []DependencyUsage        `json:"usages"`
+	Versions   []DependencyVersionUsage `json:"versions"`
+	// Truncated is set when code search found more manifests than it
+	// returns in a page, which weren't read.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type dependencyVersion struct {
+	version string
+	kind    string
+}
+
+func (c *GithubClient) FindDependencyUsage(ctx context.Context, req *mcp.CallToolRequest, args FindDependencyUsageArgs) (*mcp.CallToolResult, DependencyUsageOutput, error) {
+	err := elicitMissing(ctx, req, "Which dependency should be looked for, and where?", ownerField(&args.Owner),
+		elicitField{name: "dependency", description: "Name of the package (e.g., github.com/spf13/cobra)", value: &args.Dependency})
+	if err != nil {
+		return nil, DependencyUsageOutput{}, err
+	}
+	if args.Owner == "" || args.Dependency == "" {
+		return nil, DependencyUsageOutput{}, fmt.Errorf("owner and dependency are required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	accountType, err := c.accountType(ctx, baseURL, args.Owner)
+	if err != nil {
+		return nil, DependencyUsageOutput{}, err
+	}
+	qualifier := map[string]string{"org": "org:", "user": "user:"}[accountType] + args.Owner
+
+	// Code search finds the manifests mentioning the dependency, which are
+	// then read to tell a dependency from a mere mention and get its
+	// version.
+	type manifestFile struct {
+		manifest dependencyManifest
+		repo     string
+		path     string
+		htmlURL  string
+	}
+	var files []manifestFile
+	out := DependencyUsageOutput{Dependency: args.Dependency, Usages: []DependencyUsage{}, Versions: []DependencyVersionUsage{}}
+	for _, m := range dependencyManifests {
+		if args.Ecosystem != "" && args.Ecosystem != m.ecosystem {
+			continue
+		}
+		q := fmt.Sprintf("%q %s filename:%s", args.Dependency, qualifier, m.filename)
+		var found codeSearchResult
+		if err := c.search(ctx, baseURL, "code", searchQuery(q, "", "", 100), "", &found); err != nil {
+			return nil, DependencyUsageOutput{}, err
+		}
+		out.Truncated = out.Truncated || found.TotalCount > len(found.Items)
+		for _, item := range found.Items {
+			// filename: also matches longer names, like go.mod.orig.
+			if path.Base(item.Path) == m.filename {
+				files = append(files, manifestFile{manifest: m, repo: item.Repository.FullName, path: item.Path, htmlURL: item.HTMLURL})
+			}
+		}
+	}
+
+	// Manifests are read batchWorkers at a time.
+	versions := make([][]dependencyVersion, len(files))
+	errs := make([]error, len(files))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, f := range files {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			owner, repo, _ := strings.Cut(f.repo, "/")
+			file, err := c.getFileContent(ctx, baseURL, owner, repo, f.path, "")
+			if err != nil {
+				errs[i] = fmt.Errorf("reading %s in %s: %w", f.path, f.repo, err)
+				return
+			}
+			data, err := decodeFileContent(file)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if versions[i], err = f.manifest.parse(data, args.Dependency); err != nil {
+				errs[i] = fmt.Errorf("parsing %s in %s: %w", f.path, f.repo, err)
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, DependencyUsageOutput{}, err
+	}
+
+	byVersion := map[string][]string{}
+	for i, f := range files {
+		for _, v := range versions[i] {
+			out.Usages = append(out.Usages, DependencyUsage{
+				Repository: f.repo,
+				Ecosystem:  f.manifest.ecosystem,
+				Path:       f.path,
+				Version:    v.version,
+				Kind:       v.kind,
+				HTMLURL:    f.htmlURL,
+			})
+			if !slices.Contains(byVersion[v.version], f.repo) {
+				byVersion[v.version] = append(byVersion[v.version], f.repo)
+			}
+		}
+	}
+	slices.SortFunc(out.Usages, func(a, b DependencyUsage) int {
+		return cmp.Or(strings.Compare(a.Repository, b.Repository), strings.Compare(a.Path, b.Path))
+	})
+	for version, repos := range byVersion {
+		slices.Sort(repos)
+		out.Versions = append(out.Versions, DependencyVersionUsage{Version: version, Repositories: repos})
+	}
+	slices.SortFunc(out.Versions, func(a, b DependencyVersionUsage) int {
+		return cmp.Or(cmp.Compare(len(b.Repositories), len(a.Repositories)), strings.Compare(a.Version, b.Version))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%s is used %d times in the repositories of %s\n", args.Dependency, len(out.Usages), args.Owner)
+	if out.Truncated {
+		result.WriteString("Code search found more manifests than could be read, some repositories may be missing\n")
+	}
+	for _, v := range out.Versions {
+		fmt.Fprintf(&result, "%s: %s\n", v.Version, strings.Join(v.Repositories, ", "))
+	}
+	for _, u := range out.Usages {
+		kind := ""
+		if u.Kind != "" {
+			kind = " (" + u.Kind + ")"
+		}
+		fmt.Fprintf(&result, "  %s %s: %s%s\n", u.Repository, u.Path, u.Version, kind)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// parseGoMod returns the version of the requirement on module in a go.mod
+// file.
+func parseGoMod(data []byte, module string) ([]dependencyVersion, error) {
+	var found []dependencyVersion
+	inRequire := false
+	for line := range strings.Lines(string(data)) {
+		code, comment, _ := strings.Cut(line, "//")
+		fields := strings.Fields(code)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+		if len(fields) >= 2 && strings.Trim(fields[0], `"`) == module {
+			v := dependencyVersion{version: fields[1]}
+			if strings.TrimSpace(comment) == "indirect" {
+				v.kind = "indirect"
+			}
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+// parsePackageJSON returns the version ranges of pkg in the dependency
+// sections of a package.json file.
+func parsePackageJSON(data []byte, pkg string) ([]dependencyVersion, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	var found []dependencyVersion
+	for _, section := range []struct{ key, kind string }{
+		{"dependencies", ""},
+		{"devDependencies", "dev"},
+		{"peerDependencies", "peer"},
+		{"optionalDependencies", "optional"},
+	} {
+		var deps map[string]string
+		// Malformed sections are skipped, npm ignores them too.
+		if json.Unmarshal(manifest[section.key], &deps) != nil {
+			continue
+		}
+		if version, ok := deps[pkg]; ok {
+			found = append(found, dependencyVersion{version: version, kind: section.kind})
+		}
+	}
+	return found, nil
+}
+
+// requirementName matches the project name starting a requirement, followed
+// by its optional extras.
+var requirementName = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?`)
+
+// parseRequirements returns the version specifiers of project in a pip
+// requirements file, project names being compared as PEP 503 normalizes
+// them.
+func parseRequirements(data []byte, project string) ([]dependencyVersion, error) {
+	var found []dependencyVersion
+	for line := range strings.Lines(string(data)) {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		// Options, like -r other.txt or --index-url, aren't requirements.
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		m := requirementName.FindStringSubmatch(line)
+		if m == nil || normalizeProject(m[1]) != normalizeProject(project) {
+			continue
+		}
+		spec, _, _ := strings.Cut(line[len(m[0]):], ";")
+		spec = strings.TrimSpace(spec)
+		if pinned, ok := strings.CutPrefix(spec, "=="); ok {
+			spec = strings.TrimSpace(pinned)
+		}
+		found = append(found, dependencyVersion{version: cmp.Or(spec, "unpinned")})
+	}
+	return found, nil
+}
+
+var projectSeparators = regexp.MustCompile(`[-_.]+`)
+
+func normalizeProject(name string) string {
+	return projectSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
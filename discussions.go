@@ -0,0 +1,567 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// discussionCategoriesQuery lists the discussion categories of a
+// repository. Discussions are only served by the GraphQL API.
+const discussionCategoriesQuery = `query($owner: String!, $repo: String!) {
+  repository(owner: $owner, name: $repo) {
+    hasDiscussionsEnabled
+    discussionCategories(first: 100) {
+      nodes { id name slug description isAnswerable }
+    }
+  }
+}`
+
+type discussionCategory struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	Description  string `json:"description"`
+	IsAnswerable bool   `json:"isAnswerable"`
+}
+
+var listDiscussionCategoriesTool = &mcp.Tool{
+	Name:        "list-discussion-categories",
+	Description: "A tool to list the discussion categories of a Github repository, and which of them take answers, like Q&A. Requires a GitHub token",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListDiscussionCategoriesArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+}
+
+type DiscussionCategory struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	// Answerable is set for the categories whose discussions can have a
+	// comment marked as the answer.
+	Answerable bool `json:"answerable"`
+}
+
+type DiscussionCategoriesOutput struct {
+	Categories []DiscussionCategory `json:"categories"`
+}
+
+func (c *GithubClient) ListDiscussionCategories(ctx context.Context, req *mcp.CallToolRequest, args ListDiscussionCategoriesArgs) (*mcp.CallToolResult, DiscussionCategoriesOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, DiscussionCategoriesOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, DiscussionCategoriesOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	categories, err := c.discussionCategories(ctx, c.apiURL(args.CommonArgs), args.Owner, args.Repo)
+	if err != nil {
+		return nil, DiscussionCategoriesOutput{}, err
+	}
+
+	out := DiscussionCategoriesOutput{Categories: []DiscussionCategory{}}
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d discussion categories of %s/%s:\n", len(categories), args.Owner, args.Repo)
+	for _, cat := range categories {
+		out.Categories = append(out.Categories, DiscussionCategory{Name: cat.Name, Slug: cat.Slug, Description: cat.Description, Answerable: cat.IsAnswerable})
+		answerable := ""
+		if cat.IsAnswerable {
+			answerable = " (answerable)"
+		}
+		fmt.Fprintf(&result, "%s [%s]%s: %s\n", cat.Name, cat.Slug, answerable, cmp.Or(cat.Description, "no description"))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+func (c *GithubClient) discussionCategories(ctx context.Context, baseURL, owner, repo string) ([]discussionCategory, error) {
+	// GraphQL requests don't name the repository in their path, so they
+	// must be checked before.
+	if err := c.scope.checkRepo(owner, repo); err != nil {
+		return nil, err
+	}
+	var data struct {
+		Repository *struct {
+			HasDiscussionsEnabled bool `json:"hasDiscussionsEnabled"`
+			DiscussionCategories  struct {
+				Nodes []discussionCategory `json:"nodes"`
+			} `json:"discussionCategories"`
+		} `json:"repository"`
+	}
+	if err := c.graphQL(ctx, baseURL, discussionCategoriesQuery, map[string]any{"owner": owner, "repo": repo}, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil {
+		return nil, &apiError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no repository %s/%s", owner, repo)}
+	}
+	if !data.Repository.HasDiscussionsEnabled {
+		return nil, fmt.Errorf("discussions are disabled on %s/%s", owner, repo)
+	}
+	return data.Repository.DiscussionCategories.Nodes, nil
+}
+
+// discussionsQuery lists a page of the discussions of a repository, most
+// recently updated first.
+const discussionsQuery = `query($owner: String!, $repo: String!, $first: Int!, $after: String, $categoryId: ID, $answered: Boolean) {
+  repository(owner: $owner, name: $repo) {
+    discussions(first: $first, after: $after, categoryId: $categoryId, answered: $answered, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        url
+        createdAt
+        updatedAt
+        closed
+        upvoteCount
+        author { login }
+        category { name }
+        answer { id }
+        comments { totalCount }
+      }
+    }
+  }
+}`
+
+var listDiscussionsTool = &mcp.Tool{
+	Name:        "list-discussions",
+	Description: "A tool to list the discussions of a Github repository, most recently updated first. Requires a GitHub token",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"category": {
+				Type:        "string",
+				Description: "Only list the discussions of this category, by name or slug (e.g., q-a)",
+			},
+			"answered": {
+				Type:        "boolean",
+				Description: "Only list the discussions of answerable categories that are answered, or with false that aren't",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of discussions to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(500.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListDiscussionsArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	Category string `json:"category,omitempty"`
+	Answered *bool  `json:"answered,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type Discussion struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+	Author   string `json:"author"`
+	// Answered is only set in answerable categories.
+	Answered  bool      `json:"answered"`
+	Closed    bool      `json:"closed"`
+	Comments  int       `json:"comments"`
+	Upvotes   int       `json:"upvotes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	HTMLURL   string    `json:"html_url"`
+}
+
+type DiscussionsOutput struct {
+	Discussions []Discussion `json:"discussions"`
+}
+
+func (c *GithubClient) ListDiscussions(ctx context.Context, req *mcp.CallToolRequest, args ListDiscussionsArgs) (*mcp.CallToolResult, DiscussionsOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, DiscussionsOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, DiscussionsOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	if err := c.scope.checkRepo(args.Owner, args.Repo); err != nil {
+		return nil, DiscussionsOutput{}, err
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	limit := cmp.Or(args.Limit, 30)
+	first := min(limit, 100)
+	variables := map[string]any{"owner": args.Owner, "repo": args.Repo, "first": first}
+	if args.Category != "" {
+		// Discussions are filtered by category ID, looked up from its name.
+		categories, err := c.discussionCategories(ctx, baseURL, args.Owner, args.Repo)
+		if err != nil {
+			return nil, DiscussionsOutput{}, err
+		}
+		var names []string
+		for _, cat := range categories {
+			if strings.EqualFold(cat.Slug, args.Category) || strings.EqualFold(cat.Name, args.Category) {
+				variables["categoryId"] = cat.ID
+			}
+			names = append(names, cat.Slug)
+		}
+		if variables["categoryId"] == nil {
+			return nil, DiscussionsOutput{}, fmt.Errorf("no discussion category %q in %s/%s, expected one of %s", args.Category, args.Owner, args.Repo, strings.Join(names, ", "))
+		}
+	}
+	if args.Answered != nil {
+		variables["answered"] = *args.Answered
+	}
+
+	out := DiscussionsOutput{Discussions: []Discussion{}}
+	for page := 0; page < c.maxPages && len(out.Discussions) < limit; page++ {
+		var data struct {
+			Repository *struct {
+				Discussions struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						Number      int         `json:"number"`
+						Title       string      `json:"title"`
+						URL         string      `json:"url"`
+						CreatedAt   time.Time   `json:"createdAt"`
+						UpdatedAt   time.Time   `json:"updatedAt"`
+						Closed      bool        `json:"closed"`
+						UpvoteCount int         `json:"upvoteCount"`
+						Author      *githubUser `json:"author"`
+						Category    struct {
+							Name string `json:"name"`
+						} `json:"category"`
+						Answer *struct {
+							ID string `json:"id"`
+						} `json:"answer"`
+						Comments struct {
+							TotalCount int `json:"totalCount"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"discussions"`
+			} `json:"repository"`
+		}
+		if err := c.graphQL(ctx, baseURL, discussionsQuery, variables, &data); err != nil {
+			return nil, DiscussionsOutput{}, err
+		}
+		if data.Repository == nil {
+			return nil, DiscussionsOutput{}, &apiError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no repository %s/%s", args.Owner, args.Repo)}
+		}
+		list := data.Repository.Discussions
+		for _, d := range list.Nodes {
+			out.Discussions = append(out.Discussions, Discussion{
+				Number:    d.Number,
+				Title:     d.Title,
+				Category:  d.Category.Name,
+				Author:    d.Author.login(),
+				Answered:  d.Answer != nil,
+				Closed:    d.Closed,
+				Comments:  d.Comments.TotalCount,
+				Upvotes:   d.UpvoteCount,
+				CreatedAt: d.CreatedAt,
+				UpdatedAt: d.UpdatedAt,
+				HTMLURL:   d.URL,
+			})
+		}
+		if !list.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = list.PageInfo.EndCursor
+	}
+	out.Discussions = out.Discussions[:min(len(out.Discussions), limit)]
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d discussions of %s/%s:\n", len(out.Discussions), args.Owner, args.Repo)
+	for _, d := range out.Discussions {
+		var flags []string
+		if d.Answered {
+			flags = append(flags, "answered")
+		}
+		if d.Closed {
+			flags = append(flags, "closed")
+		}
+		state := ""
+		if len(flags) > 0 {
+			state = " (" + strings.Join(flags, ", ") + ")"
+		}
+		fmt.Fprintf(&result, "#%d [%s] %s%s by %s, %d comments, %d upvotes, updated %s %s\n",
+			d.Number, d.Category, d.Title, state, d.Author, d.Comments, d.Upvotes, d.UpdatedAt.Format(time.DateOnly), d.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// discussionQuery reads a discussion with its first comments and their
+// first replies, which covers all but the longest threads.
+const discussionQuery = `query($owner: String!, $repo: String!, $number: Int!, $comments: Int!) {
+  repository(owner: $owner, name: $repo) {
+    discussion(number: $number) {
+      number
+      title
+      body
+      url
+      createdAt
+      closed
+      upvoteCount
+      author { login }
+      category { name }
+      answer { id }
+      comments(first: $comments) {
+        totalCount
+        nodes {
+          id
+          body
+          createdAt
+          upvoteCount
+          url
+          author { login }
+          replies(first: 50) {
+            totalCount
+            nodes { body createdAt url author { login } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+var getDiscussionTool = &mcp.Tool{
+	Name:        "get-discussion",
+	Description: "A tool to read a discussion of a Github repository with its answer and comment thread, replies included. Requires a GitHub token",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the discussion",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"comments": {
+				Type:        "integer",
+				Description: "Maximum number of comments to return, oldest first (defaults to 50)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type GetDiscussionArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	Number   int    `json:"number"`
+	Comments int    `json:"comments,omitempty"`
+}
+
+type DiscussionReply struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	HTMLURL   string    `json:"html_url"`
+}
+
+type DiscussionComment struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+	// IsAnswer is set on the comment marked as the answer.
+	IsAnswer  bool              `json:"is_answer"`
+	Upvotes   int               `json:"upvotes"`
+	CreatedAt time.Time         `json:"created_at"`
+	HTMLURL   string            `json:"html_url"`
+	Replies   []DiscussionReply `json:"replies"`
+	// TotalReplies may exceed the replies returned on very long threads.
+	TotalReplies int `json:"total_replies"`
+}
+
+type DiscussionOutput struct {
+	Discussion
+	Body   string              `json:"body"`
+	Thread []DiscussionComment `json:"thread"`
+}
+
+func (c *GithubClient) GetDiscussion(ctx context.Context, req *mcp.CallToolRequest, args GetDiscussionArgs) (*mcp.CallToolResult, DiscussionOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, DiscussionOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, DiscussionOutput{}, fmt.Errorf("owner, repo and number are required")
+	}
+	if err := c.scope.checkRepo(args.Owner, args.Repo); err != nil {
+		return nil, DiscussionOutput{}, err
+	}
+	type reply struct {
+		Body      string      `json:"body"`
+		CreatedAt time.Time   `json:"createdAt"`
+		URL       string      `json:"url"`
+		Author    *githubUser `json:"author"`
+	}
+	var data struct {
+		Repository *struct {
+			Discussion *struct {
+				Number      int         `json:"number"`
+				Title       string      `json:"title"`
+				Body        string      `json:"body"`
+				URL         string      `json:"url"`
+				CreatedAt   time.Time   `json:"createdAt"`
+				Closed      bool        `json:"closed"`
+				UpvoteCount int         `json:"upvoteCount"`
+				Author      *githubUser `json:"author"`
+				Category    struct {
+					Name string `json:"name"`
+				} `json:"category"`
+				Answer *struct {
+					ID string `json:"id"`
+				} `json:"answer"`
+				Comments struct {
+					TotalCount int `json:"totalCount"`
+					Nodes      []struct {
+						ID          string      `json:"id"`
+						Body        string      `json:"body"`
+						CreatedAt   time.Time   `json:"createdAt"`
+						UpvoteCount int         `json:"upvoteCount"`
+						URL         string      `json:"url"`
+						Author      *githubUser `json:"author"`
+						Replies     struct {
+							TotalCount int     `json:"totalCount"`
+							Nodes      []reply `json:"nodes"`
+						} `json:"replies"`
+					} `json:"nodes"`
+				} `json:"comments"`
+			} `json:"discussion"`
+		} `json:"repository"`
+	}
+	variables := map[string]any{"owner": args.Owner, "repo": args.Repo, "number": args.Number, "comments": cmp.Or(args.Comments, 50)}
+	if err := c.graphQL(ctx, c.apiURL(args.CommonArgs), discussionQuery, variables, &data); err != nil {
+		return nil, DiscussionOutput{}, err
+	}
+	if data.Repository == nil || data.Repository.Discussion == nil {
+		return nil, DiscussionOutput{}, &apiError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no discussion #%d in %s/%s", args.Number, args.Owner, args.Repo)}
+	}
+	d := data.Repository.Discussion
+
+	out := DiscussionOutput{
+		Discussion: Discussion{
+			Number:    d.Number,
+			Title:     d.Title,
+			Category:  d.Category.Name,
+			Author:    d.Author.login(),
+			Answered:  d.Answer != nil,
+			Closed:    d.Closed,
+			Comments:  d.Comments.TotalCount,
+			Upvotes:   d.UpvoteCount,
+			CreatedAt: d.CreatedAt,
+			HTMLURL:   d.URL,
+		},
+		Body:   d.Body,
+		Thread: []DiscussionComment{},
+	}
+	for _, cm := range d.Comments.Nodes {
+		comment := DiscussionComment{
+			Author:       cm.Author.login(),
+			Body:         cm.Body,
+			IsAnswer:     d.Answer != nil && d.Answer.ID == cm.ID,
+			Upvotes:      cm.UpvoteCount,
+			CreatedAt:    cm.CreatedAt,
+			HTMLURL:      cm.URL,
+			Replies:      []DiscussionReply{},
+			TotalReplies: cm.Replies.TotalCount,
+		}
+		for _, r := range cm.Replies.Nodes {
+			comment.Replies = append(comment.Replies, DiscussionReply{Author: r.Author.login(), Body: r.Body, CreatedAt: r.CreatedAt, HTMLURL: r.URL})
+		}
+		out.Thread = append(out.Thread, comment)
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Discussion %s/%s#%d [%s]: %s\n", args.Owner, args.Repo, out.Number, out.Category, out.Title)
+	fmt.Fprintf(&result, "Started by %s on %s, %d upvotes %s\n", out.Author, out.CreatedAt.Format(time.DateOnly), out.Upvotes, out.HTMLURL)
+	fmt.Fprintf(&result, "\n%s\n", cmp.Or(out.Body, "No description provided."))
+	for _, cm := range out.Thread {
+		if cm.IsAnswer {
+			fmt.Fprintf(&result, "\nAnswer by %s:\n%s\n", cm.Author, cm.Body)
+		}
+	}
+	if len(out.Thread) > 0 {
+		fmt.Fprintf(&result, "\n%d of %d comments:\n", len(out.Thread), out.Comments)
+	}
+	for _, cm := range out.Thread {
+		answer := ""
+		if cm.IsAnswer {
+			answer = ", marked as answer"
+		}
+		fmt.Fprintf(&result, "\n----- %s, %s, %d upvotes%s -----\n%s\n", cm.Author, cm.CreatedAt.Format(time.RFC3339), cm.Upvotes, answer, cm.Body)
+		for _, r := range cm.Replies {
+			fmt.Fprintf(&result, "  > %s, %s: %s\n", r.Author, r.CreatedAt.Format(time.RFC3339), strings.ReplaceAll(r.Body, "\n", "\n    "))
+		}
+		if cm.TotalReplies > len(cm.Replies) {
+			fmt.Fprintf(&result, "  [... %d more replies at %s ...]\n", cm.TotalReplies-len(cm.Replies), cm.HTMLURL)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
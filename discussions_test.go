@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDiscussionsDeniedRepository(t *testing.T) {
+	var queries int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		http.Error(w, "unexpected query", http.StatusInternalServerError)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{Deny: []string{"*/secrets-*"}})
+	register := func(s *mcp.Server) {
+		addTool(s, listDiscussionCategoriesTool, gh.ListDiscussionCategories)
+		addTool(s, listDiscussionsTool, gh.ListDiscussions)
+		addTool(s, getDiscussionTool, gh.GetDiscussion)
+	}
+
+	for name, args := range map[string]map[string]any{
+		"list-discussion-categories": {"owner": "o", "repo": "secrets-db"},
+		"list-discussions":           {"owner": "o", "repo": "secrets-db"},
+		"get-discussion":             {"owner": "o", "repo": "secrets-db", "number": 1},
+	} {
+		res := callTool(t, register, name, args)
+		if text := resultText(res); !res.IsError || !strings.Contains(text, "outside the repositories") {
+			t.Errorf("%s of a denied repository = %q, want a scope error", name, text)
+		}
+	}
+	if queries > 0 {
+		t.Errorf("%d GraphQL queries sent about a denied repository", queries)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var diskCacheBucket = []byte("responses")
+
+// diskETagStore is an etagStore kept in a bbolt database, so the validators
+// of past responses survive restarts. Once the stored responses exceed
+// maxBytes the oldest ones are evicted, by last use for the "lru" policy or
+// by the time they were stored for "fifo".
+type diskETagStore struct {
+	db       *bolt.DB
+	maxBytes int64
+	policy   string
+
+	mu   sync.Mutex
+	size int64 // total size of the stored values
+}
+
+// diskEntry is how a cachedResponse is stored on disk.
+type diskEntry struct {
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	Stored   time.Time   `json:"stored"`
+	Accessed time.Time   `json:"accessed"`
+}
+
+// openDiskETagStore opens, or creates, the cache database at path.
+func openDiskETagStore(path string, maxBytes int64, policy string) (*diskETagStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	// Another server using the same file holds its lock, don't wait for it
+	// forever.
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening cache %s: %w", path, err)
+	}
+	s := &diskETagStore{db: db, maxBytes: maxBytes, policy: policy}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(diskCacheBucket)
+		if err != nil {
+			return err
+		}
+		return b.ForEach(func(k, v []byte) error {
+			s.size += int64(len(k) + len(v))
+			return nil
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening cache %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *diskETagStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *diskETagStore) Get(key string) *cachedResponse {
+	var entry diskEntry
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(diskCacheBucket).Get([]byte(key))
+		if v == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(v, &entry)
+	})
+	if err != nil || !found {
+		return nil
+	}
+	if s.policy == "lru" {
+		entry.Accessed = time.Now()
+		s.write(key, &entry)
+	}
+	return &cachedResponse{Header: entry.Header, Body: entry.Body}
+}
+
+func (s *diskETagStore) Put(key string, r *cachedResponse) {
+	now := time.Now()
+	s.write(key, &diskEntry{Header: r.Header, Body: r.Body, Stored: now, Accessed: now})
+}
+
+// write stores entry under key, evicting older entries when the store grows
+// past its size limit. Failures only cost a full request later, so they are
+// logged rather than returned.
+func (s *diskETagStore) write(key string, entry *diskEntry) {
+	v, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("Failed to encode cache entry", "err", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(diskCacheBucket)
+		size := s.size
+		if old := b.Get([]byte(key)); old != nil {
+			size -= int64(len(key) + len(old))
+		}
+		if err := b.Put([]byte(key), v); err != nil {
+			return err
+		}
+		size += int64(len(key) + len(v))
+		if size > s.maxBytes {
+			var err error
+			if size, err = s.evict(b, size); err != nil {
+				return err
+			}
+		}
+		s.size = size
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Failed to write cache entry", "err", err)
+	}
+}
+
+// evict deletes the oldest entries of b until size, the current total, is
+// below 90% of the limit, so that evictions don't happen on every write. It
+// returns the new total.
+func (s *diskETagStore) evict(b *bolt.Bucket, size int64) (int64, error) {
+	type candidate struct {
+		key  string
+		size int64
+		at   time.Time
+	}
+	var candidates []candidate
+	err := b.ForEach(func(k, v []byte) error {
+		var entry diskEntry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			// Unreadable entries go first.
+			candidates = append(candidates, candidate{key: string(k), size: int64(len(k) + len(v))})
+			return nil
+		}
+		at := entry.Stored
+		if s.policy == "lru" {
+			at = entry.Accessed
+		}
+		candidates = append(candidates, candidate{key: string(k), size: int64(len(k) + len(v)), at: at})
+		return nil
+	})
+	if err != nil {
+		return size, err
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int { return a.at.Compare(b.at) })
+
+	target := s.maxBytes * 9 / 10
+	for _, c := range candidates {
+		if size <= target {
+			break
+		}
+		if err := b.Delete([]byte(c.key)); err != nil {
+			return size, err
+		}
+		size -= c.size
+	}
+	return size, nil
+}
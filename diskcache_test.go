@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestDiskStore(t *testing.T, path string, maxBytes int64, policy string) *diskETagStore {
+	t.Helper()
+	s, err := openDiskETagStore(path, maxBytes, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// storedSize is the size diskETagStore should account for.
+func storedSize(t *testing.T, s *diskETagStore) int64 {
+	t.Helper()
+	var size int64
+	s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(diskCacheBucket).ForEach(func(k, v []byte) error {
+			size += int64(len(k) + len(v))
+			return nil
+		})
+	})
+	return size
+}
+
+func TestDiskETagStoreSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "etags.db")
+	s, err := openDiskETagStore(path, 1<<20, "lru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := http.Header{"Etag": {`"v1"`}}
+	s.Put("k", &cachedResponse{Header: header, Body: []byte("body")})
+	s.Put("k", &cachedResponse{Header: header, Body: []byte("new body")})
+	s.Close()
+
+	s = openTestDiskStore(t, path, 1<<20, "lru")
+	got := s.Get("k")
+	if got == nil || string(got.Body) != "new body" || got.Header.Get("ETag") != `"v1"` {
+		t.Fatalf("Get after reopening = %+v, want the last stored response", got)
+	}
+	if s.size != storedSize(t, s) {
+		t.Errorf("accounted size after reopening = %d, stored %d", s.size, storedSize(t, s))
+	}
+	if s.Get("missing") != nil {
+		t.Error("Get of a missing key returned a response")
+	}
+}
+
+func TestDiskETagStoreEviction(t *testing.T) {
+	body := []byte(strings.Repeat("x", 200))
+	for policy, evicted := range map[string]string{"lru": "b", "fifo": "a"} {
+		s := openTestDiskStore(t, filepath.Join(t.TempDir(), "etags.db"), 1<<20, policy)
+		for _, key := range []string{"a", "b", "c"} {
+			s.Put(key, &cachedResponse{Body: body})
+			time.Sleep(time.Millisecond)
+		}
+		// Room for three and a half entries: the fourth evicts one.
+		s.maxBytes = s.size * 7 / 6
+		// Reading a makes it the most recently used one for lru.
+		s.Get("a")
+		time.Sleep(time.Millisecond)
+		s.Put("d", &cachedResponse{Body: body})
+
+		if s.size > s.maxBytes {
+			t.Errorf("%s: size %d after eviction, over the limit", policy, s.size)
+		}
+		if s.size != storedSize(t, s) {
+			t.Errorf("%s: accounted size %d, stored %d", policy, s.size, storedSize(t, s))
+		}
+		for _, key := range []string{"a", "b", "c", "d"} {
+			if stored := s.Get(key) != nil; stored != (key != evicted) {
+				t.Errorf("%s: %s stored = %v, want only %s evicted", policy, key, stored, evicted)
+			}
+		}
+	}
+}
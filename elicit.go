@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// elicitField is a tool argument that can be asked from the user when the
+// model left it out. value points to a string or int64 argument.
+type elicitField struct {
+	name        string
+	description string
+	value       any
+}
+
+func ownerField(owner *string) elicitField {
+	return elicitField{name: "owner", description: "Owner of the repository (e.g., kubernetes)", value: owner}
+}
+
+func repoField(repo *string) elicitField {
+	return elicitField{name: "repo", description: "Name of the repository (e.g., kubectl)", value: repo}
+}
+
+func (f elicitField) missing() bool {
+	switch v := f.value.(type) {
+	case *string:
+		return *v == ""
+	case *int64:
+		return *v == 0
+	}
+	return false
+}
+
+// elicitMissing asks the user, through MCP elicitation, for the fields that
+// are still empty and fills them in. It does nothing when the client can't
+// elicit or the user declines, leaving the tool to report the missing
+// arguments as usual.
+func elicitMissing(ctx context.Context, req *mcp.CallToolRequest, message string, fields ...elicitField) error {
+	var missing []elicitField
+	for _, f := range fields {
+		if f.missing() {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) == 0 || req == nil || req.Session == nil {
+		return nil
+	}
+	if params := req.Session.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.Elicitation == nil {
+		return nil
+	}
+
+	schema := &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}}
+	for _, f := range missing {
+		prop := &jsonschema.Schema{Type: "string", Description: f.description}
+		if _, ok := f.value.(*int64); ok {
+			prop.Type = "integer"
+		}
+		schema.Properties[f.name] = prop
+		schema.Required = append(schema.Required, f.name)
+	}
+	res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{Message: message, RequestedSchema: schema})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.WarnContext(ctx, "Asking for missing arguments failed", "fields", fieldNames(missing), "err", err)
+		return nil
+	}
+	if res.Action != "accept" {
+		return nil
+	}
+	for _, f := range missing {
+		switch v := f.value.(type) {
+		case *string:
+			s, _ := res.Content[f.name].(string)
+			*v = strings.TrimSpace(s)
+		case *int64:
+			// JSON numbers decode as float64.
+			n, _ := res.Content[f.name].(float64)
+			*v = int64(n)
+		}
+	}
+	return nil
+}
+
+func fieldNames(fields []elicitField) string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return strings.Join(names, ", ")
+}
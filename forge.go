@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// Forge is a git hosting service. list-repositories, list-issues,
+// list-pull-requests and get-file-contents go through it, so they work with
+// every provider; the other tools only support GitHub.
+type Forge interface {
+	// ListRepos lists every repository of an organization, group or user.
+	ListRepos(ctx context.Context, owner string, opts RepoListOptions) ([]Repository, error)
+	// GetFile returns a file of a repository at ref, the default branch
+	// when ref is empty.
+	GetFile(ctx context.Context, owner, repo, path, ref string) (*File, error)
+	ListIssues(ctx context.Context, owner, repo string, opts IssueListOptions) ([]Issue, error)
+	ListPullRequests(ctx context.Context, owner, repo string, opts PullRequestListOptions) ([]PullRequest, error)
+}
+
+// runForge is implemented by the providers whose CI runs list-workflow-runs
+// can list.
+type runForge interface {
+	ListRuns(ctx context.Context, args ListWorkflowRunsArgs) (*WorkflowRunListOutput, error)
+}
+
+// RepoListOptions are the filters of list-repositories. Providers ignore the
+// ones they have no equivalent for.
+type RepoListOptions struct {
+	// AccountType is "org" or "user", looked up when empty.
+	AccountType string
+	Type        string
+	Sort        string
+	Direction   string
+}
+
+type IssueListOptions struct {
+	State    string
+	Labels   []string
+	Assignee string
+}
+
+type PullRequestListOptions struct {
+	State string
+	Base  string
+}
+
+// File is a file read from a repository.
+type File struct {
+	Path    string
+	Size    int
+	Content []byte
+}
+
+// Issue is an issue of any provider. Pull requests are never listed as
+// issues.
+type Issue struct {
+	Number int
+	Title  string
+	State  string
+	URL    string
+	Labels []string
+}
+
+// PullRequest is a pull request, or merge request, of any provider.
+type PullRequest struct {
+	Number int
+	Title  string
+	State  string
+	Draft  bool
+	Author string
+	Head   string
+	Base   string
+	URL    string
+	// Mergeable is the provider's mergeability state of an open pull
+	// request, "unknown" when it has none.
+	Mergeable string
+}
+
+// githubProvider is the name of the default provider.
+const githubProvider = "github"
+
+// forgeProvider is a provider the server can talk to besides GitHub.
+type forgeProvider struct {
+	// hosts are the web and API hosts of the provider, used to pick it from
+	// a base_url argument.
+	hosts []string
+	// forge returns the provider's Forge, for the API root baseURL when it
+	// isn't empty.
+	forge func(baseURL string) Forge
+	// authorize adds the provider's credentials to a request for one of
+	// its hosts. It is nil without credentials.
+	authorize func(req *http.Request)
+	// nestedOwners is set when owners span several path segments, like
+	// GitLab subgroups.
+	nestedOwners bool
+	// sitePath is the path the website is served under on its hosts, like
+	// /tfs on an Azure DevOps Server.
+	sitePath string
+}
+
+// addProvider makes a provider other than GitHub available to the tools.
+func (c *GithubClient) addProvider(name string, p forgeProvider) {
+	if c.providers == nil {
+		c.providers = map[string]forgeProvider{}
+	}
+	c.providers[name] = p
+}
+
+// forge returns the Forge a tool call should use: the one named by its
+// provider argument, the one whose host its base_url points to, or GitHub.
+func (c *GithubClient) forge(args CommonArgs) (Forge, error) {
+	name := args.Provider
+	if name == "" && args.BaseURL != "" {
+		name = c.providerForURL(args.BaseURL)
+	}
+	if name == "" || name == githubProvider {
+		return &githubForge{c: c, baseURL: c.apiURL(args)}, nil
+	}
+	p, ok := c.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q, expected one of %s", name, strings.Join(c.providerNames(), ", "))
+	}
+	return p.forge(strings.TrimSuffix(args.BaseURL, "/")), nil
+}
+
+// providerForURL returns the provider serving rawURL, or "" when it is none
+// of the configured ones.
+func (c *GithubClient) providerForURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	name, _ := c.providerForHost(u.Hostname())
+	return name
+}
+
+// accountFromURL returns the owner named by the organization, group or user
+// URL rawURL, and points args to the provider hosting it unless the call
+// already picked one with provider, base_url or profile. Hosts that aren't
+// the main GitHub host, a profile's or another provider's are taken for
+// GitHub Enterprise Server.
+func (c *GithubClient) accountFromURL(rawURL string, args *CommonArgs) (string, error) {
+	ref, err := parseRepoURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	explicit := args.Provider != "" || args.BaseURL != "" || args.Profile != ""
+	name, p := c.providerForHost(ref.Host)
+	if explicit {
+		name = args.Provider
+		if name == "" && args.BaseURL != "" {
+			name = c.providerForURL(args.BaseURL)
+		}
+		p = c.providers[name]
+	}
+	owner := ref.Owner
+	if name != "" && p.nestedOwners {
+		segments := ref.Segments
+		for site := range strings.SplitSeq(strings.Trim(p.sitePath, "/"), "/") {
+			if site != "" && len(segments) > 1 && strings.EqualFold(segments[0], site) {
+				segments = segments[1:]
+			}
+		}
+		// Stop at the pages of the account, like /-/ on GitLab or /_git on
+		// Azure DevOps.
+		i := slices.IndexFunc(segments, func(s string) bool { return strings.HasPrefix(s, "-") || strings.HasPrefix(s, "_") })
+		if i < 0 {
+			i = len(segments)
+		}
+		owner = strings.Join(segments[:i], "/")
+	}
+	if explicit || ref.Host == "" {
+		return owner, nil
+	}
+	switch {
+	case name != "":
+		args.Provider = name
+	case strings.EqualFold(ref.Host, githubWebHost(c.baseURL)):
+	default:
+		profile := ""
+		for _, n := range slices.Sorted(maps.Keys(c.profiles)) {
+			if strings.EqualFold(ref.Host, githubWebHost(c.profiles[n].BaseURL)) {
+				profile = n
+				break
+			}
+		}
+		switch {
+		case profile != "":
+			args.Profile = profile
+		case isGitHubDotCom(ref.Host):
+			args.BaseURL = githubAPIURL
+		default:
+			args.BaseURL = enterpriseAPIURL(ref.Host)
+		}
+	}
+	return owner, nil
+}
+
+// githubWebHost returns the host of the website whose API root is baseURL:
+// github.com for api.github.com, and the host itself for GitHub Enterprise
+// Server.
+func githubWebHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	if host := u.Hostname(); !strings.EqualFold(host, "api.github.com") {
+		return host
+	}
+	return "github.com"
+}
+
+func (c *GithubClient) providerForHost(host string) (string, forgeProvider) {
+	for name, p := range c.providers {
+		if slices.ContainsFunc(p.hosts, func(h string) bool { return strings.EqualFold(h, host) }) {
+			return name, p
+		}
+	}
+	return "", forgeProvider{}
+}
+
+// bearerAuth authorizes requests with token, when there is one.
+func bearerAuth(token string) func(*http.Request) {
+	if token == "" {
+		return nil
+	}
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+func (c *GithubClient) providerNames() []string {
+	return append([]string{githubProvider}, slices.Sorted(maps.Keys(c.providers))...)
+}
+
+func providerProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Git hosting provider to use for this call (defaults to the provider of base_url, or github)",
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ghCLIToken returns the token the gh CLI is logged in with for host, or ""
+// when it isn't. Older gh versions keep the token in hosts.yml, newer ones in
+// the system keyring, where it is read by asking gh itself.
+func ghCLIToken(host string) (string, error) {
+	token, err := ghHostsToken(host)
+	if err != nil || token != "" {
+		return token, err
+	}
+	gh, err := exec.LookPath("gh")
+	if err != nil {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, gh, "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		// gh exits with an error when it isn't logged in to host.
+		return "", nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ghHostsToken reads the token of host from the gh CLI's hosts.yml.
+func ghHostsToken(host string) (string, error) {
+	path := filepath.Join(ghConfigDir(), "hosts.yml")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading gh CLI credentials: %w", err)
+	}
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("parsing gh CLI credentials %s: %w", path, err)
+	}
+	return hosts[host].OAuthToken, nil
+}
+
+// ghConfigDir mirrors how the gh CLI locates its configuration directory.
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI")
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gh")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// repoRef identifies an account, and optionally one of its repositories, on
+// a GitHub host. Host is empty when it could not be told from the input, as
+// with the shorthand owner/repo form.
+type repoRef struct {
+	Host  string
+	Owner string
+	Repo  string
+	// Segments is the whole path the owner and repository were read from.
+	Segments []string
+}
+
+// parseRepoURL extracts the host, owner and repository from the ways a
+// GitHub repository is usually written down:
+//
+//	https://github.com/owner/repo[.git][/tree/main/...][?query][#fragment]
+//	github.com/owner/repo
+//	git@github.com:owner/repo.git
+//	ssh://git@github.com/owner/repo.git
+//	owner/repo
+//	owner
+//
+// Enterprise hosts are accepted in the same shapes, and a leading www. is
+// dropped from the host. Repo is empty when the input only names an account.
+func parseRepoURL(raw string) (repoRef, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return repoRef{}, fmt.Errorf("empty repository URL")
+	}
+
+	var ref repoRef
+	var rest string
+	switch {
+	case strings.Contains(s, "://"):
+		u, err := url.Parse(s)
+		if err != nil {
+			return repoRef{}, fmt.Errorf("invalid repository URL %q: %w", raw, err)
+		}
+		ref.Host = u.Hostname()
+		rest = u.Path
+	case isSCPLike(s):
+		// git@host:owner/repo.git
+		hostPart, p, _ := strings.Cut(s, ":")
+		_, host, found := strings.Cut(hostPart, "@")
+		if !found {
+			host = hostPart
+		}
+		ref.Host = host
+		rest = p
+	default:
+		// Either host/owner[/repo] without a scheme or the owner[/repo] shorthand.
+		s, _, _ = strings.Cut(s, "?")
+		s, _, _ = strings.Cut(s, "#")
+		first, remainder, _ := strings.Cut(s, "/")
+		if strings.Contains(first, ".") || strings.Contains(first, ":") {
+			ref.Host = first
+			rest = remainder
+		} else {
+			rest = s
+		}
+	}
+	ref.Host = strings.TrimPrefix(strings.ToLower(ref.Host), "www.")
+
+	segments := strings.FieldsFunc(rest, func(r rune) bool { return r == '/' })
+	if len(segments) == 0 {
+		return repoRef{}, fmt.Errorf("no owner in repository URL %q", raw)
+	}
+	ref.Segments = segments
+	ref.Owner = segments[0]
+	if len(segments) > 1 {
+		ref.Repo = strings.TrimSuffix(segments[1], ".git")
+	}
+	if ref.Owner == "" || (len(segments) > 1 && ref.Repo == "") {
+		return repoRef{}, fmt.Errorf("invalid repository URL %q", raw)
+	}
+	return ref, nil
+}
+
+// isSCPLike reports whether s is written in the scp-like syntax git uses for
+// SSH remotes, user@host:path.
+func isSCPLike(s string) bool {
+	colon := strings.Index(s, ":")
+	if colon < 0 || strings.HasPrefix(s[colon:], "://") {
+		return false
+	}
+	slash := strings.Index(s, "/")
+	if slash >= 0 && colon > slash {
+		return false
+	}
+	// host:port/owner is a host with a port, not an scp-like path.
+	port := s[colon+1:]
+	if slash >= 0 {
+		port = s[colon+1 : slash]
+	}
+	return port == "" || strings.Trim(port, "0123456789") != ""
+}
+
+// isGitHubDotCom reports whether host is github.com or its API host.
+func isGitHubDotCom(host string) bool {
+	return host == "github.com" || host == "api.github.com"
+}
+
+// enterpriseAPIURL returns the REST API root of a GitHub Enterprise Server
+// host, or "" for github.com and unknown hosts.
+func enterpriseAPIURL(host string) string {
+	if host == "" || isGitHubDotCom(host) {
+		return ""
+	}
+	return "https://" + host + "/api/v3"
+}
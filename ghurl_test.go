@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRepoURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want repoRef
+	}{
+		{"https://github.com/o/r", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r"}}},
+		{"https://github.com/o/r.git", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r.git"}}},
+		{"https://www.github.com/o/r/tree/main/docs?x=1#top", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r", "tree", "main", "docs"}}},
+		{"https://github.com/o", repoRef{Host: "github.com", Owner: "o", Segments: []string{"o"}}},
+		{"github.com/o/r", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r"}}},
+		{"git@github.com:o/r.git", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r.git"}}},
+		{"github.com:o/r", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r"}}},
+		{"ssh://git@github.com/o/r.git", repoRef{Host: "github.com", Owner: "o", Repo: "r", Segments: []string{"o", "r.git"}}},
+		{"ssh://git@ghe.example.com:2222/o/r.git", repoRef{Host: "ghe.example.com", Owner: "o", Repo: "r", Segments: []string{"o", "r.git"}}},
+		{"https://GHE.example.com/o/r", repoRef{Host: "ghe.example.com", Owner: "o", Repo: "r", Segments: []string{"o", "r"}}},
+		{"git@ghe.example.com:o/r.git", repoRef{Host: "ghe.example.com", Owner: "o", Repo: "r", Segments: []string{"o", "r.git"}}},
+		{"ghe.example.com:8443/o/r", repoRef{Host: "ghe.example.com:8443", Owner: "o", Repo: "r", Segments: []string{"o", "r"}}},
+		{"o/r", repoRef{Owner: "o", Repo: "r", Segments: []string{"o", "r"}}},
+		{"o", repoRef{Owner: "o", Segments: []string{"o"}}},
+	}
+	for _, tt := range tests {
+		got, err := parseRepoURL(tt.in)
+		if err != nil {
+			t.Errorf("parseRepoURL(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseRepoURL(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "  ", "https://github.com/", "https://github.com/o/.git"} {
+		if got, err := parseRepoURL(in); err == nil {
+			t.Errorf("parseRepoURL(%q) = %+v, want an error", in, got)
+		}
+	}
+}
+
+func TestIsSCPLike(t *testing.T) {
+	for s, want := range map[string]bool{
+		"git@github.com:o/r.git":     true,
+		"github.com:o/r":             true,
+		"git@ghe.example.com:o":      true,
+		"ghe.example.com:8443/o/r":   false,
+		"github.com/o/r":             false,
+		"o/r":                        false,
+		"https://github.com/o/r":     false,
+		"github.com/o/r:with-colon":  false,
+		"ssh://git@github.com/o/r":   false,
+		"git@ghe.example.com:2222/o": false,
+	} {
+		if got := isSCPLike(s); got != want {
+			t.Errorf("isSCPLike(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestEnterpriseAPIURL(t *testing.T) {
+	for host, want := range map[string]string{
+		"":                "",
+		"github.com":      "",
+		"api.github.com":  "",
+		"ghe.example.com": "https://ghe.example.com/api/v3",
+	} {
+		if got := enterpriseAPIURL(host); got != want {
+			t.Errorf("enterpriseAPIURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
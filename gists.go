@@ -0,0 +1,272 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listGistsTool = &mcp.Tool{
+	Name:        "list-gists",
+	Description: "A tool to list the gists of a GitHub user, most recently updated first",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"user": {
+				Type:        "string",
+				Description: "User whose public gists are listed (defaults to the authenticated user, whose secret gists are listed too)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of gists to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type ListGistsArgs struct {
+	CommonArgs
+	User  string `json:"user,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type Gist struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Public      bool      `json:"public"`
+	Owner       string    `json:"owner"`
+	Files       []string  `json:"files"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+type GistListOutput struct {
+	Gists []Gist `json:"gists"`
+}
+
+type gistFile struct {
+	Filename  string `json:"filename"`
+	Language  string `json:"language"`
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated"`
+	Content   string `json:"content"`
+}
+
+type gist struct {
+	ID          string              `json:"id"`
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	HTMLURL     string              `json:"html_url"`
+	Files       map[string]gistFile `json:"files"`
+	Owner       struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+func (g gist) toGist() Gist {
+	files := make([]string, 0, len(g.Files))
+	for name := range g.Files {
+		files = append(files, name)
+	}
+	slices.Sort(files)
+	return Gist{ID: g.ID, Description: g.Description, Public: g.Public, Owner: g.Owner.Login, Files: files, UpdatedAt: g.UpdatedAt, HTMLURL: g.HTMLURL}
+}
+
+func (c *GithubClient) ListGists(ctx context.Context, req *mcp.CallToolRequest, args ListGistsArgs) (*mcp.CallToolResult, GistListOutput, error) {
+	apiURL := fmt.Sprintf("%s/gists?per_page=%d", c.apiURL(args.CommonArgs), cmp.Or(args.Limit, 30))
+	if args.User != "" {
+		apiURL = fmt.Sprintf("%s/users/%s/gists?per_page=%d", c.apiURL(args.CommonArgs), url.PathEscape(args.User), cmp.Or(args.Limit, 30))
+	}
+	var gists []gist
+	if err := c.getJSON(ctx, apiURL, &gists); err != nil {
+		return nil, GistListOutput{}, err
+	}
+
+	out := GistListOutput{Gists: []Gist{}}
+	for _, g := range gists {
+		out.Gists = append(out.Gists, g.toGist())
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d gists:\n", len(out.Gists))
+	for _, g := range out.Gists {
+		visibility := "public"
+		if !g.Public {
+			visibility = "secret"
+		}
+		fmt.Fprintf(&result, "%s %s (%s, %s, updated %s): %s %s\n",
+			g.ID, cmp.Or(g.Description, "no description"), visibility, strings.Join(g.Files, ", "), g.UpdatedAt.Format(time.DateOnly), g.Owner, g.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var getGistTool = &mcp.Tool{
+	Name:        "get-gist",
+	Description: "A tool to read the files of a GitHub gist",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"id": {
+				Type:        "string",
+				Description: "ID of the gist, the last segment of its URL",
+			},
+			"max_bytes": {
+				Type:        "integer",
+				Description: "Maximum number of bytes to return of each file, the rest is truncated (defaults to 102400)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"id"},
+	},
+}
+
+type GetGistArgs struct {
+	CommonArgs
+	ID       string `json:"id"`
+	MaxBytes int    `json:"max_bytes,omitempty"`
+}
+
+type GistFile struct {
+	Filename string `json:"filename"`
+	Language string `json:"language"`
+	Size     int    `json:"size"`
+	Content  string `json:"content"`
+	// Truncated is set when Content isn't the whole file.
+	Truncated bool `json:"truncated"`
+}
+
+type GistOutput struct {
+	Gist
+	Contents []GistFile `json:"contents"`
+}
+
+func (c *GithubClient) GetGist(ctx context.Context, req *mcp.CallToolRequest, args GetGistArgs) (*mcp.CallToolResult, GistOutput, error) {
+	err := elicitMissing(ctx, req, "Which gist should be read?",
+		elicitField{name: "id", description: "ID of the gist", value: &args.ID})
+	if err != nil {
+		return nil, GistOutput{}, err
+	}
+	if args.ID == "" {
+		return nil, GistOutput{}, fmt.Errorf("id is required")
+	}
+	maxBytes := cmp.Or(args.MaxBytes, defaultMaxFileBytes)
+	var g gist
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/gists/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.ID)), &g); err != nil {
+		return nil, GistOutput{}, err
+	}
+
+	out := GistOutput{Gist: g.toGist(), Contents: []GistFile{}}
+	var result strings.Builder
+	fmt.Fprintf(&result, "Gist %s by %s: %s %s\n", g.ID, g.Owner.Login, cmp.Or(g.Description, "no description"), g.HTMLURL)
+	for _, name := range out.Files {
+		f := g.Files[name]
+		// GitHub truncates the content of files over 1MB itself.
+		file := GistFile{Filename: f.Filename, Language: f.Language, Size: f.Size, Content: f.Content, Truncated: f.Truncated}
+		if len(file.Content) > maxBytes {
+			file.Content, file.Truncated = file.Content[:maxBytes], true
+		}
+		out.Contents = append(out.Contents, file)
+		fmt.Fprintf(&result, "\n----- %s (%s, %d bytes) -----\n%s\n", file.Filename, cmp.Or(file.Language, "text"), file.Size, file.Content)
+		if file.Truncated {
+			fmt.Fprintf(&result, "[... truncated, showing %d of %d bytes ...]\n", len(file.Content), file.Size)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var createGistTool = &mcp.Tool{
+	Name:        "create-gist",
+	Description: "A tool to create a GitHub gist of the authenticated user from one or more files, to share a snippet",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"description": {
+				Type:        "string",
+				Description: "Description of the gist",
+			},
+			"files": {
+				Type:        "object",
+				Description: "Contents of the files of the gist by file name (e.g., {\"main.go\": \"package main\"})",
+				AdditionalProperties: &jsonschema.Schema{
+					Type: "string",
+				},
+			},
+			"public": {
+				Type:        "boolean",
+				Description: "Make the gist public, it is secret otherwise: unlisted, but readable by anyone with its URL",
+			},
+			"base_url": baseURLProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"files"},
+	},
+}
+
+type CreateGistArgs struct {
+	CommonArgs
+	Description string            `json:"description,omitempty"`
+	Files       map[string]string `json:"files"`
+	Public      bool              `json:"public,omitempty"`
+}
+
+func (c *GithubClient) CreateGist(ctx context.Context, req *mcp.CallToolRequest, args CreateGistArgs) (*mcp.CallToolResult, Gist, error) {
+	if len(args.Files) == 0 {
+		return nil, Gist{}, fmt.Errorf("files is required")
+	}
+	files := map[string]map[string]string{}
+	for name, content := range args.Files {
+		// GitHub refuses empty files.
+		if strings.TrimSpace(content) == "" {
+			return nil, Gist{}, fmt.Errorf("file %s is empty", name)
+		}
+		files[name] = map[string]string{"content": content}
+	}
+	payload := map[string]any{
+		"description": args.Description,
+		"public":      args.Public,
+		"files":       files,
+	}
+
+	var g gist
+	if err := c.sendJSON(ctx, http.MethodPost, c.apiURL(args.CommonArgs)+"/gists", payload, &g); err != nil {
+		return nil, Gist{}, err
+	}
+	out := g.toGist()
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Created gist %s with %s: %s\n", out.ID, strings.Join(out.Files, ", "), out.HTMLURL)},
+		},
+	}, out, nil
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// codebergAPIURL is the API root of Codeberg, the public Forgejo instance.
+const codebergAPIURL = "https://codeberg.org/api/v1"
+
+// addGitea makes the Gitea or Forgejo instance at the API root baseURL
+// available as the "gitea" provider. Codeberg is the default instance and
+// stays available as the "codeberg" provider when another one is
+// configured, without credentials.
+func (c *GithubClient) addGitea(baseURL, token string) {
+	baseURL = strings.TrimSuffix(cmp.Or(baseURL, codebergAPIURL), "/")
+	c.addProvider("gitea", giteaProvider(c, baseURL, token))
+	if !sameHost(baseURL, &url.URL{Host: "codeberg.org"}) {
+		c.addProvider("codeberg", giteaProvider(c, codebergAPIURL, ""))
+	}
+}
+
+func giteaProvider(c *GithubClient, baseURL, token string) forgeProvider {
+	var hosts []string
+	if u, err := url.Parse(baseURL); err == nil {
+		hosts = append(hosts, u.Hostname())
+	}
+	return forgeProvider{
+		hosts: hosts,
+		forge: func(override string) Forge {
+			// A base_url on the website stands for its API.
+			if u, err := url.Parse(override); err == nil && override != "" && !strings.Contains(u.Path, "/api/") {
+				override = strings.TrimSuffix(override, "/") + "/api/v1"
+			}
+			return &giteaForge{c: c, baseURL: cmp.Or(override, baseURL)}
+		},
+		authorize: bearerAuth(token),
+	}
+}
+
+// giteaForge is the Forge of Gitea and its fork Forgejo, whose API mostly
+// mirrors GitHub's.
+type giteaForge struct {
+	c       *GithubClient
+	baseURL string
+}
+
+func (f *giteaForge) repoURL(owner, repo string) string {
+	return fmt.Sprintf("%s/repos/%s/%s", f.baseURL, url.PathEscape(owner), url.PathEscape(repo))
+}
+
+// pageSize is the limit parameter of the listings, which Gitea caps at 50 by
+// default.
+func (f *giteaForge) pageSize() string {
+	return strconv.Itoa(min(f.c.perPage, 50))
+}
+
+type giteaRepository struct {
+	Name        string    `json:"name"`
+	FullName    string    `json:"full_name"`
+	HTMLURL     string    `json:"html_url"`
+	Private     bool      `json:"private"`
+	Description string    `json:"description"`
+	Language    string    `json:"language"`
+	StarsCount  int       `json:"stars_count"`
+	Fork        bool      `json:"fork"`
+	Archived    bool      `json:"archived"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (f *giteaForge) ListRepos(ctx context.Context, owner string, opts RepoListOptions) ([]Repository, error) {
+	collection := "orgs"
+	if opts.AccountType == "user" {
+		collection = "users"
+	}
+	listURL := func(collection string) string {
+		return fmt.Sprintf("%s/%s/%s/repos?limit=%s", f.baseURL, collection, url.PathEscape(owner), f.pageSize())
+	}
+	raw, err := getAllPages[giteaRepository](ctx, f.c, listURL(collection))
+	if opts.AccountType == "" && isNotFound(err) {
+		raw, err = getAllPages[giteaRepository](ctx, f.c, listURL("users"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	repos := []Repository{}
+	for _, r := range raw {
+		if !f.c.scope.allowsFullName(r.FullName) {
+			continue
+		}
+		switch {
+		case opts.Type == "forks" && !r.Fork, opts.Type == "sources" && r.Fork,
+			opts.Type == "public" && r.Private, opts.Type == "private" && !r.Private:
+			continue
+		}
+		repos = append(repos, Repository{
+			Name:            r.Name,
+			FullName:        r.FullName,
+			HTMLURL:         r.HTMLURL,
+			Private:         r.Private,
+			Description:     r.Description,
+			Language:        r.Language,
+			StargazersCount: r.StarsCount,
+			Fork:            r.Fork,
+			Archived:        r.Archived,
+			PushedAt:        r.UpdatedAt,
+		})
+	}
+	// The API has no sort options, so sort like GitHub would.
+	if opts.Sort != "" {
+		slices.SortStableFunc(repos, func(a, b Repository) int {
+			if opts.Sort == "full_name" {
+				if opts.Direction == "desc" {
+					return strings.Compare(b.FullName, a.FullName)
+				}
+				return strings.Compare(a.FullName, b.FullName)
+			}
+			if opts.Direction == "asc" {
+				return a.PushedAt.Compare(b.PushedAt)
+			}
+			return b.PushedAt.Compare(a.PushedAt)
+		})
+	}
+	return repos, nil
+}
+
+func (f *giteaForge) GetFile(ctx context.Context, owner, repo, path, ref string) (*File, error) {
+	// The contents API answers like GitHub's.
+	file, err := f.c.getFileContent(ctx, f.baseURL, owner, repo, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeFileContent(file)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: file.Path, Size: file.Size, Content: data}, nil
+}
+
+type giteaIssue struct {
+	issue
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+func (f *giteaForge) ListIssues(ctx context.Context, owner, repo string, opts IssueListOptions) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("limit", f.pageSize())
+	query.Set("type", "issues")
+	query.Set("state", cmp.Or(opts.State, "open"))
+	if len(opts.Labels) > 0 {
+		query.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	if opts.Assignee != "" && opts.Assignee != "none" && opts.Assignee != "*" {
+		query.Set("assigned_by", opts.Assignee)
+	}
+
+	raw, err := getAllPages[giteaIssue](ctx, f.c, f.repoURL(owner, repo)+"/issues?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	issues := []Issue{}
+	for _, is := range raw {
+		// Only specific assignees can be filtered on by the API.
+		if (opts.Assignee == "none" && len(is.Assignees) > 0) || (opts.Assignee == "*" && len(is.Assignees) == 0) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Number: is.Number,
+			Title:  is.Title,
+			State:  is.State,
+			URL:    is.HTMLURL,
+			Labels: is.labelNames(),
+		})
+	}
+	return issues, nil
+}
+
+type giteaPullRequest struct {
+	pullRequest
+	Mergeable bool `json:"mergeable"`
+}
+
+func (f *giteaForge) ListPullRequests(ctx context.Context, owner, repo string, opts PullRequestListOptions) ([]PullRequest, error) {
+	query := url.Values{}
+	query.Set("limit", f.pageSize())
+	query.Set("state", cmp.Or(opts.State, "open"))
+
+	raw, err := getAllPages[giteaPullRequest](ctx, f.c, f.repoURL(owner, repo)+"/pulls?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	pulls := []PullRequest{}
+	for _, pr := range raw {
+		if opts.Base != "" && pr.Base.Ref != opts.Base {
+			continue
+		}
+		mergeable := "unknown"
+		if pr.State == "open" {
+			mergeable = "conflicting"
+			if pr.Mergeable {
+				mergeable = "mergeable"
+			}
+		}
+		pulls = append(pulls, PullRequest{
+			Number:    pr.Number,
+			Title:     pr.Title,
+			State:     pr.State,
+			Draft:     pr.Draft,
+			Author:    pr.User.Login,
+			Head:      pr.Head.Ref,
+			Base:      pr.Base.Ref,
+			URL:       pr.HTMLURL,
+			Mergeable: mergeable,
+		})
+	}
+	return pulls, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newGiteaClient returns a client whose gitea provider is served by mux,
+// authenticated with the token gitea-test, and the test server's URL.
+func newGiteaClient(t *testing.T, mux *http.ServeMux) (*GithubClient, string) {
+	t.Helper()
+	gh, url := newForgeTestClient(t, mux, "Bearer gitea-test", GithubClientOptions{})
+	gh.addGitea(url+"/api/v1/", "gitea-test")
+	return gh, url
+}
+
+func TestGiteaKeepsCodeberg(t *testing.T) {
+	gh, _ := newGiteaClient(t, http.NewServeMux())
+	if p, ok := gh.providers["codeberg"]; !ok || p.authorize != nil {
+		t.Errorf("codeberg provider = %+v, %v, want one without credentials", p, ok)
+	}
+
+	gh = newTestClient(t, http.NotFoundHandler(), GithubClientOptions{})
+	gh.addGitea("", "token")
+	if _, ok := gh.providers["codeberg"]; ok {
+		t.Error("Codeberg is listed twice when it is the gitea instance")
+	}
+}
+
+func TestGiteaListsUserRepositories(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/orgs/alice/repos", http.NotFound)
+	mux.HandleFunc("GET /api/v1/users/alice/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") == "" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		jsonHandler(`[
+			{"name":"old","full_name":"alice/old","updated_at":"2020-01-01T00:00:00Z"},
+			{"name":"new","full_name":"alice/new","updated_at":"2024-01-01T00:00:00Z","stars_count":3},
+			{"name":"fork","full_name":"alice/fork","fork":true,"updated_at":"2025-01-01T00:00:00Z"}
+		]`)(w, r)
+	})
+	gh, _ := newGiteaClient(t, mux)
+
+	res := callForgeTool(t, gh, "list-repositories", map[string]any{"name": "alice", "provider": "gitea", "sort": "pushed", "type": "sources"})
+	text := resultText(res)
+	if res.IsError || strings.Contains(text, "fork") || !strings.Contains(text, "Name: old") || strings.Index(text, "Name: new") > strings.Index(text, "Name: old") {
+		t.Errorf("list-repositories = %q, want new before old and no fork", text)
+	}
+}
+
+func TestGiteaIssuesAndPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/o/r/issues", func(w http.ResponseWriter, r *http.Request) {
+		// Unassigned issues can't be asked for, so they're filtered here.
+		if q := r.URL.Query(); q.Get("type") != "issues" || q.Get("state") != "open" || q.Get("labels") != "bug" || q.Has("assigned_by") {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		jsonHandler(`[
+			{"number":1,"title":"Taken","state":"open","assignees":[{"login":"bob"}]},
+			{"number":2,"title":"Free","state":"open","labels":[{"name":"bug"}]}
+		]`)(w, r)
+	})
+	mux.HandleFunc("GET /api/v1/repos/o/r/pulls", jsonHandler(`[
+		{"number":3,"title":"Ready","state":"open","mergeable":true,"user":{"login":"bob"},"head":{"ref":"fix"},"base":{"ref":"main"}},
+		{"number":4,"title":"Elsewhere","state":"open","base":{"ref":"dev"}}
+	]`))
+	gh, _ := newGiteaClient(t, mux)
+
+	res := callForgeTool(t, gh, "list-issues", map[string]any{"owner": "o", "repo": "r", "provider": "gitea", "assignee": "none", "labels": []string{"bug"}})
+	text := resultText(res)
+	if res.IsError || strings.Contains(text, "Taken") || !strings.Contains(text, "#2 [open] Free (labels: bug)") {
+		t.Errorf("list-issues = %q, want only the unassigned issue", text)
+	}
+	res = callForgeTool(t, gh, "list-pull-requests", map[string]any{"owner": "o", "repo": "r", "provider": "gitea", "base": "main"})
+	text = resultText(res)
+	if res.IsError || strings.Contains(text, "Elsewhere") || !strings.Contains(text, "#3 [open] Ready by bob, fix -> main, mergeable: mergeable") {
+		t.Errorf("list-pull-requests = %q", text)
+	}
+}
+
+func TestGiteaGetFileFromWebsiteURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/o/r/contents/docs/a.md", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "main" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		jsonHandler(`{"type":"file","path":"docs/a.md","size":5,"encoding":"base64","content":"SGVs\nbG8="}`)(w, r)
+	})
+	gh, url := newGiteaClient(t, mux)
+
+	// A base_url on the website is taken for the API below it.
+	res := callForgeTool(t, gh, "get-file-contents", map[string]any{"owner": "o", "repo": "r", "path": "docs/a.md", "ref": "main", "provider": "gitea", "base_url": url})
+	if text := resultText(res); res.IsError || text != "Hello" {
+		t.Errorf("get-file-contents = %q", text)
+	}
+}
@@ -0,0 +1,769 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// githubAPIURL is the base URL of the public GitHub API. GitHub
+	// Enterprise Server instances serve it under https://<host>/api/v3.
+	githubAPIURL = "https://api.github.com"
+	// defaultMaxPages caps how many pages a paginated listing may fetch so a
+	// huge org can't keep a tool call busy indefinitely.
+	defaultMaxPages = 10
+)
+
+// GithubClient is shared by all the tools to talk to the GitHub REST API.
+// When a token is configured it is sent with every request, which gives access
+// to private repositories and the higher authenticated rate limit.
+type GithubClient struct {
+	httpClient *http.Client
+	baseURL    string
+	// auth is nil when the client is unauthenticated.
+	auth     tokenSource
+	perPage  int
+	maxPages int
+	// cache is nil when the response cache is disabled.
+	cache *responseCache
+	// profiles are the named alternatives to baseURL and auth a tool call
+	// can select.
+	profiles map[string]ClientProfile
+	// server receives the repositories discovered by the tools as
+	// resources, resources holds the URIs already added.
+	server    *mcp.Server
+	resources sync.Map
+	// subs are the subscribed repository resources, by URI.
+	subsMu sync.Mutex
+	subs   map[string]*subscription
+	// readOnly refuses requests other than GET and HEAD.
+	readOnly bool
+	// dryRun records the requests that would change data instead of
+	// sending them.
+	dryRun bool
+	// scope is nil when every repository may be accessed.
+	scope *repoScope
+	// useGraphQL lists repositories with the GraphQL API instead of REST.
+	useGraphQL bool
+	// providers are the git hosting providers other than GitHub, by name.
+	providers map[string]forgeProvider
+	// scratchDir is where repository archives are extracted, at most
+	// scratchMaxBytes of them. scratchMu serializes their replacement and
+	// eviction.
+	scratchDir      string
+	scratchMaxBytes int64
+	scratchMu       sync.Mutex
+	// cloneDir holds the clones of clone-repository, at most cloneMaxBytes
+	// of them. clonesMu serializes their replacement and eviction.
+	cloneDir      string
+	cloneMaxBytes int64
+	clonesMu      sync.Mutex
+	// started is set once the server around the client is set up, the
+	// readiness probe failing until then.
+	started atomic.Bool
+}
+
+// ClientProfile is a named GitHub identity: an API root and the credentials
+// to use with it.
+type ClientProfile struct {
+	BaseURL     string
+	TokenSource tokenSource
+}
+
+// GithubClientOptions configures a GithubClient. Zero values select the
+// defaults, except for MaxRetries.
+type GithubClientOptions struct {
+	// BaseURL is the root of the GitHub API, e.g.
+	// https://github.mycorp.com/api/v3 for GitHub Enterprise Server.
+	BaseURL string
+	Token   string
+	// TokenSource takes precedence over Token, e.g. to authenticate as a
+	// GitHub App installation.
+	TokenSource tokenSource
+	// PerPage is the page size requested from listing endpoints.
+	PerPage  int
+	MaxPages int
+	// Timeout bounds how long a single HTTP request to GitHub waits for its
+	// response headers.
+	Timeout time.Duration
+	// MaxRetries is how many times a rate limited or failed request is
+	// retried. Zero disables retries.
+	MaxRetries int
+	// RetryBaseDelay is the first backoff delay, doubled on each retry.
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay is the longest the client will wait before a retry,
+	// including waits for a rate limit reset.
+	RetryMaxDelay time.Duration
+	// CacheTTL is how long responses are served from memory. Zero disables
+	// the response cache.
+	CacheTTL time.Duration
+	// CacheSize is the number of responses the cache holds.
+	CacheSize int
+	// ETagStore keeps the responses revalidated with their ETag. When nil
+	// they are kept in memory.
+	ETagStore etagStore
+	// Profiles are selected by the profile argument of the tools. A profile
+	// without a BaseURL uses the public GitHub API.
+	Profiles map[string]ClientProfile
+	// ReadOnly makes the client refuse every request that could change
+	// data, whichever tool sends it.
+	ReadOnly bool
+	// DryRun makes every tool call a dry run.
+	DryRun bool
+	// Allow and Deny are owner/repo patterns limiting the repositories
+	// requests may be about, see repoScope.
+	Allow []string
+	Deny  []string
+	// GraphQL lists repositories with the GraphQL API, in one request per
+	// page with their topics and latest release.
+	GraphQL bool
+	// ScratchDir is where repository archives are extracted, magnet in the
+	// temporary directory when empty. ScratchMaxBytes bounds the size of the
+	// extracted archives, 4 GiB when zero.
+	ScratchDir      string
+	ScratchMaxBytes int64
+	// CloneDir is where repositories are cloned, magnet/clones in the user
+	// cache directory when empty. CloneMaxBytes bounds the size of the
+	// clones, 2 GiB when zero.
+	CloneDir      string
+	CloneMaxBytes int64
+	// Metrics, when set, records the requests sent to GitHub and the
+	// response cache statistics.
+	Metrics *serverMetrics
+	// Tracer, when set, traces the requests sent to GitHub.
+	Tracer *tracer
+}
+
+func NewGithubClient(opts *GithubClientOptions) *GithubClient {
+	if opts == nil {
+		opts = &GithubClientOptions{}
+	}
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	base := opts.Tracer.transport(http.DefaultTransport)
+	if opts.Metrics != nil {
+		base = opts.Metrics.transport(base)
+	}
+	rt := &retryTransport{
+		base:       base,
+		maxRetries: max(opts.MaxRetries, 0),
+		baseDelay:  cmp.Or(opts.RetryBaseDelay, time.Second),
+		maxDelay:   cmp.Or(opts.RetryMaxDelay, time.Minute),
+		timeout:    cmp.Or(opts.Timeout, 10*time.Second),
+	}
+	c := &GithubClient{
+		baseURL:         strings.TrimSuffix(cmp.Or(opts.BaseURL, githubAPIURL), "/"),
+		auth:            opts.TokenSource,
+		perPage:         cmp.Or(opts.PerPage, 100),
+		maxPages:        maxPages,
+		readOnly:        opts.ReadOnly,
+		dryRun:          opts.DryRun,
+		scope:           newRepoScope(opts.Allow, opts.Deny),
+		useGraphQL:      opts.GraphQL,
+		scratchDir:      cmp.Or(opts.ScratchDir, filepath.Join(os.TempDir(), "magnet")),
+		scratchMaxBytes: cmp.Or(opts.ScratchMaxBytes, 4<<30),
+		cloneDir:        cmp.Or(opts.CloneDir, defaultCloneDir()),
+		cloneMaxBytes:   cmp.Or(opts.CloneMaxBytes, 2<<30),
+	}
+	if c.auth == nil && opts.Token != "" {
+		c.auth = staticToken(opts.Token)
+	}
+	c.profiles = map[string]ClientProfile{}
+	for name, p := range opts.Profiles {
+		p.BaseURL = strings.TrimSuffix(cmp.Or(p.BaseURL, githubAPIURL), "/")
+		c.profiles[name] = p
+	}
+	var transport http.RoundTripper = newETagTransport(rt, opts.ETagStore)
+	if opts.CacheTTL > 0 {
+		c.cache = newResponseCache(transport, opts.CacheTTL, cmp.Or(opts.CacheSize, 500))
+		transport = c.cache
+	}
+	if opts.Metrics != nil {
+		opts.Metrics.cache = c.cache
+	}
+	c.httpClient = &http.Client{Transport: transport}
+	return c
+}
+
+// CommonArgs holds the arguments accepted by every tool. It is embedded in
+// each tool's argument struct.
+type CommonArgs struct {
+	// BaseURL overrides the GitHub API base URL for a single call.
+	BaseURL string `json:"base_url,omitempty"`
+	// NoCache makes the call skip the response cache.
+	NoCache bool `json:"no_cache,omitempty"`
+	// Profile selects the credentials profile used for the call.
+	Profile string `json:"profile,omitempty"`
+	// DryRun makes the call report the changes it would send to GitHub
+	// instead of sending them. Only the tools that change data offer it.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Provider selects the git hosting provider of the tools that support
+	// several, see Forge.
+	Provider string `json:"provider,omitempty"`
+}
+
+func (a CommonArgs) common() CommonArgs { return a }
+
+type commonArgsKey struct{}
+
+// commonArgsFrom returns the CommonArgs of the tool call ctx belongs to.
+func commonArgsFrom(ctx context.Context) CommonArgs {
+	args, _ := ctx.Value(commonArgsKey{}).(CommonArgs)
+	return args
+}
+
+type dryRunKey struct{}
+
+type listingNotesKey struct{}
+
+// listingNotes collects what the listings of a tool call have to say about
+// the completeness of its result. Handlers run listings concurrently, so it
+// is guarded by a mutex.
+type listingNotes struct {
+	mu sync.Mutex
+	// truncated is the most pages a listing stopped at with more left.
+	truncated int
+	// interrupted is the first listing cut short by the end of the call.
+	interrupted *partialError
+}
+
+// noteTruncated records that a listing of the tool call of ctx stopped
+// after pages pages while there were more, so that its result says so.
+func noteTruncated(ctx context.Context, pages int) {
+	if notes, ok := ctx.Value(listingNotesKey{}).(*listingNotes); ok {
+		notes.mu.Lock()
+		notes.truncated = max(notes.truncated, pages)
+		notes.mu.Unlock()
+	}
+}
+
+// interrupted returns the error of a listing cut short by the end of ctx.
+// Within a tool call that already fetched some pages it returns nil
+// instead, so the handler goes on with the items fetched so far, and the
+// result notes that they are partial.
+func interrupted(ctx context.Context, err *partialError) error {
+	notes, ok := ctx.Value(listingNotesKey{}).(*listingNotes)
+	if !ok || err.Pages == 0 {
+		return err
+	}
+	notes.mu.Lock()
+	defer notes.mu.Unlock()
+	if notes.interrupted == nil {
+		notes.interrupted = err
+	}
+	return nil
+}
+
+// text returns the note to add to the result of the tool call, if any.
+func (n *listingNotes) text() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var notes []string
+	if n.interrupted != nil {
+		notes = append(notes, fmt.Sprintf("Results are incomplete: the listing was interrupted after %d pages (%d items): %v.", n.interrupted.Pages, n.interrupted.Items, n.interrupted.Err))
+	}
+	if n.truncated > 0 {
+		notes = append(notes, fmt.Sprintf("Results are incomplete: the listing stopped after %d pages, the max_pages limit of the server.", n.truncated))
+	}
+	return strings.Join(notes, "\n")
+}
+
+// dryRunRequest is a request that changes data on GitHub, recorded instead of
+// sent during a dry run.
+type dryRunRequest struct {
+	Method  string
+	URL     string
+	Payload any
+}
+
+// addTool registers a tool whose arguments embed CommonArgs. The common
+// arguments are made available to the HTTP transports through the context
+// so they apply to every request the handler makes.
+//
+// When the handler recorded dry run requests, they replace its result: the
+// responses it worked with were made up, so its own report would be wrong.
+func addTool[In interface{ common() CommonArgs }, Out any](s *mcp.Server, t *mcp.Tool, h mcp.ToolHandlerFor[In, Out]) {
+	mcp.AddTool(s, t, func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, Out, error) {
+		var dryRun []dryRunRequest
+		notes := &listingNotes{}
+		ctx = context.WithValue(ctx, commonArgsKey{}, args.common())
+		ctx = context.WithValue(ctx, dryRunKey{}, &dryRun)
+		ctx = context.WithValue(ctx, listingNotesKey{}, notes)
+		res, out, err := h(withProgress(ctx, req), req, args)
+		if tc, ok := ctx.Value(toolCallKey{}).(*toolCall); ok {
+			tc.err, tc.dryRun = err, len(dryRun) > 0
+		}
+		if len(dryRun) > 0 {
+			var zero Out
+			return dryRunResult(dryRun), zero, nil
+		}
+		if note := notes.text(); note != "" && err == nil && res != nil {
+			res.Content = append(res.Content, &mcp.TextContent{Text: note})
+		}
+		return res, out, err
+	})
+}
+
+func dryRunResult(requests []dryRunRequest) *mcp.CallToolResult {
+	var result strings.Builder
+	fmt.Fprintf(&result, "Dry run, nothing was changed on GitHub. The call would send:\n")
+	for _, r := range requests {
+		fmt.Fprintf(&result, "\n%s %s\n", r.Method, r.URL)
+		if r.Payload != nil {
+			payload, _ := json.MarshalIndent(r.Payload, "", "  ")
+			fmt.Fprintf(&result, "%s\n", payload)
+		}
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}
+}
+
+// baseURLProperty returns the input schema of CommonArgs.BaseURL. The SDK
+// rejects schemas shared between tools, so each tool gets its own copy.
+func baseURLProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "GitHub API base URL to use for this call, for GitHub Enterprise Server (e.g., https://github.mycorp.com/api/v3)",
+	}
+}
+
+func noCacheProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Fetch fresh data from GitHub instead of serving cached responses",
+	}
+}
+
+func dryRunProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "Only report the requests that would change data on GitHub, with their payload, without sending them",
+	}
+}
+
+func profileProperty() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Name of the configured credentials profile to use for this call (defaults to the main credentials)",
+	}
+}
+
+// readOnlyAnnotations marks a tool that only reads from GitHub, so clients
+// can run it without asking for confirmation.
+func readOnlyAnnotations() *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  jsonschema.Ptr(true),
+	}
+}
+
+// writeAnnotations marks a tool that changes data on GitHub. Destructive
+// tools overwrite or delete existing data, idempotent ones can be repeated
+// with the same arguments without further effect.
+func writeAnnotations(destructive, idempotent bool) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		DestructiveHint: jsonschema.Ptr(destructive),
+		IdempotentHint:  idempotent,
+		OpenWorldHint:   jsonschema.Ptr(true),
+	}
+}
+
+// dryRunTool returns a copy of the write tool t for a server in dry-run
+// mode, whose description and annotations tell clients that its calls only
+// report the requests they would send. readOnly notes that the server is
+// read-only as well.
+func dryRunTool(t *mcp.Tool, readOnly bool) *mcp.Tool {
+	mode := "dry-run mode"
+	if readOnly {
+		mode = "read-only, dry-run mode"
+	}
+	dry := *t
+	dry.Description = fmt.Sprintf("%s (%s: only reports the requests it would send, nothing is changed on GitHub)", t.Description, mode)
+	dry.Annotations = &mcp.ToolAnnotations{
+		ReadOnlyHint:    true,
+		DestructiveHint: jsonschema.Ptr(false),
+		IdempotentHint:  true,
+		OpenWorldHint:   jsonschema.Ptr(true),
+	}
+	return &dry
+}
+
+// localAnnotations marks a read-only tool that reports on the server itself
+// rather than talking to GitHub.
+func localAnnotations() *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		ReadOnlyHint:   true,
+		IdempotentHint: true,
+		OpenWorldHint:  jsonschema.Ptr(false),
+	}
+}
+
+// apiURL returns the API root a call should use: the base_url argument when
+// the caller supplied one, the root of the selected profile otherwise.
+func (c *GithubClient) apiURL(args CommonArgs) string {
+	if args.BaseURL != "" {
+		return strings.TrimSuffix(args.BaseURL, "/")
+	}
+	baseURL, _, err := c.profile(args.Profile)
+	if err != nil {
+		// newRequest reports the unknown profile.
+		return c.baseURL
+	}
+	return baseURL
+}
+
+// profile returns the API root and credentials of the named profile, the
+// main ones for "".
+func (c *GithubClient) profile(name string) (string, tokenSource, error) {
+	if name == "" {
+		return c.baseURL, c.auth, nil
+	}
+	p, ok := c.profiles[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown profile %q", name)
+	}
+	return p.BaseURL, p.TokenSource, nil
+}
+
+// newRequest builds a GitHub API request with the standard headers set.
+//
+// The credentials are those of the profile selected by the tool call. The
+// token is only attached to requests for that profile's API host, so a
+// per-call base URL override can never leak it to another server. Requests
+// for the hosts of another provider get that provider's credentials instead.
+func (c *GithubClient) newRequest(ctx context.Context, method, rawURL string, body io.Reader) (*http.Request, error) {
+	baseURL, auth, err := c.profile(commonArgsFrom(ctx).Profile)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.scope.check(req.URL); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if auth != nil && sameHost(baseURL, req.URL) {
+		token, err := auth.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if _, p := c.providerForHost(req.URL.Hostname()); p.authorize != nil {
+		p.authorize(req)
+	}
+	return req, nil
+}
+
+func sameHost(baseURL string, u *url.URL) bool {
+	base, err := url.Parse(baseURL)
+	return err == nil && strings.EqualFold(base.Host, u.Host)
+}
+
+// ValidateToken checks the configured token against the /user endpoint so a
+// bad token is reported at startup rather than on the first tool call. A
+// GitHub App is checked by minting its first installation token instead.
+func (c *GithubClient) ValidateToken(ctx context.Context) error {
+	if c.auth == nil {
+		return nil
+	}
+	if app, ok := c.auth.(*appTokenSource); ok {
+		_, err := app.Token(ctx)
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, c.baseURL+"/user", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("GitHub token is invalid or expired")
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
+
+// apiError is a non-2xx response from the GitHub API.
+type apiError struct {
+	StatusCode int
+	Body       string
+	// TokenScopes and AcceptedScopes are the scopes of the classic token
+	// the request was made with and the ones the endpoint accepts, when
+	// GitHub tells them.
+	TokenScopes    string
+	AcceptedScopes string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// errReadOnly is returned for requests that could change data on GitHub while
+// the server is read-only.
+var errReadOnly = errors.New("the server is read-only, restart it with --read-only=false to change data on GitHub")
+
+// isNotFound reports whether err is a 404 from the GitHub API.
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isGone reports whether err is a 410 from the GitHub API, which expired
+// workflow logs are answered with.
+func isGone(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusGone
+}
+
+// scopeError explains a 403, or the 404 GitHub hides resources the token
+// may not see behind, of an endpoint needing specific permissions: what
+// needs a classic token with scope, or a fine-grained one with permission.
+func scopeError(err error, what, scope, permission string) error {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || (apiErr.StatusCode != http.StatusForbidden && apiErr.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	if strings.Contains(strings.ToLower(apiErr.Body), "rate limit") {
+		return err
+	}
+	scopes := "unknown"
+	if apiErr.TokenScopes != "" {
+		scopes = apiErr.TokenScopes
+	}
+	return fmt.Errorf("%s was refused or not found: it needs a classic token with the %s scope, or a fine-grained token with the %s permission, and access to the repositories (token scopes: %s): %w",
+		what, scope, permission, scopes, err)
+}
+
+// partialError reports a listing that was interrupted, usually because the
+// client cancelled the tool call, after some of its pages were fetched.
+type partialError struct {
+	Pages int
+	Items int
+	Err   error
+}
+
+func (e *partialError) Error() string {
+	return fmt.Sprintf("incomplete results, stopped after %d pages (%d items): %v", e.Pages, e.Items, e.Err)
+}
+
+func (e *partialError) Unwrap() error {
+	return e.Err
+}
+
+// get performs a GET request against url.
+func (c *GithubClient) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// do sends req and returns the response if GitHub answered with a 2xx
+// status. Any other status is turned into an error carrying the response body.
+func (c *GithubClient) do(req *http.Request) (*http.Response, error) {
+	if c.readOnly && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return nil, errReadOnly
+	}
+	return c.send(req)
+}
+
+// send sends req whatever its method, turning error statuses into an
+// apiError.
+func (c *GithubClient) send(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.DebugContext(req.Context(), "GitHub request failed", "method", req.Method, "url", req.URL.Redacted(), "err", err)
+		return nil, err
+	}
+	slog.DebugContext(req.Context(), "GitHub request", "method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "duration", time.Since(start))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &apiError{
+			StatusCode:     resp.StatusCode,
+			Body:           string(body),
+			TokenScopes:    resp.Header.Get("X-OAuth-Scopes"),
+			AcceptedScopes: resp.Header.Get("X-Accepted-OAuth-Scopes"),
+		}
+	}
+	return resp, nil
+}
+
+// getJSON fetches url and decodes the JSON response body into v.
+func (c *GithubClient) getJSON(ctx context.Context, url string, v any) error {
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(resp, v)
+}
+
+// sendJSON sends in, encoded as JSON, to rawURL with method and decodes the
+// response into out. Either can be nil for requests without a body or when
+// the response doesn't matter.
+//
+// During a dry run requests other than GET are only recorded for the tool
+// call and out is left untouched.
+func (c *GithubClient) sendJSON(ctx context.Context, method, rawURL string, in, out any) error {
+	if method != http.MethodGet && (c.dryRun || commonArgsFrom(ctx).DryRun) {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+		if err := c.scope.check(u); err != nil {
+			return err
+		}
+		if requests, ok := ctx.Value(dryRunKey{}).(*[]dryRunRequest); ok {
+			*requests = append(*requests, dryRunRequest{Method: method, URL: rawURL, Payload: in})
+		}
+		return nil
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := c.newRequest(ctx, method, rawURL, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		resp.Body.Close()
+		return nil
+	}
+	return decodeJSON(resp, out)
+}
+
+// decodeJSON decodes the body of resp into v and closes it.
+func decodeJSON(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// getAllPages fetches url and every following page advertised in the Link
+// header, decoding each page as a JSON array and concatenating the results.
+// It stops after c.maxPages pages, noting the truncation in the tool
+// call, and reports every page fetched as progress of the tool call. When
+// ctx is done before the last page, the results so far are returned, see
+// interrupted.
+func getAllPages[T any](ctx context.Context, c *GithubClient, url string) ([]T, error) {
+	progress := progressFrom(ctx)
+	base := progress.value()
+	// Never return nil so empty listings encode as [] in structured output.
+	all := []T{}
+	total := 0.0
+	for page := 0; url != "" && page < c.maxPages; page++ {
+		// Stop between pages as soon as the call is cancelled instead of
+		// only noticing it on the next request.
+		if err := ctx.Err(); err != nil {
+			return all, interrupted(ctx, &partialError{Pages: page, Items: len(all), Err: err})
+		}
+		resp, err := c.get(ctx, url)
+		if err != nil {
+			if ctx.Err() != nil && page > 0 {
+				return all, interrupted(ctx, &partialError{Pages: page, Items: len(all), Err: ctx.Err()})
+			}
+			return nil, err
+		}
+		// Some listings, like the contributors of an empty repository,
+		// answer with 204 No Content.
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			break
+		}
+		var items []T
+		err = json.NewDecoder(resp.Body).Decode(&items)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		all = append(all, items...)
+		url = nextPageURL(resp.Header.Get("Link"))
+		if last := lastPage(resp.Header.Get("Link")); last > 0 {
+			total = base + float64(min(last, c.maxPages))
+		}
+		progress.update(ctx, base+float64(page+1), total, fmt.Sprintf("Fetched page %d", page+1))
+	}
+	if url != "" {
+		noteTruncated(ctx, c.maxPages)
+	}
+	return all, nil
+}
+
+// nextPageURL extracts the rel="next" target from a GitHub Link header, e.g.
+//
+//	<https://api.github.com/organizations/1/repos?page=2>; rel="next", <...>; rel="last"
+//
+// It returns "" when there is no next page.
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		target = strings.TrimSpace(target)
+		return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	}
+	return ""
+}
+
+// githubUser is a user account as the API embeds it in issues, comments and
+// reviews. It is null for deleted accounts.
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+// login returns the login of u, ghost for deleted accounts as GitHub shows
+// them.
+func (u *githubUser) login() string {
+	if u == nil {
+		return "ghost"
+	}
+	return u.Login
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newTestClient returns a client of a fake GitHub API served by h.
+func newTestClient(t *testing.T, h http.Handler, opts GithubClientOptions) *GithubClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	opts.BaseURL = srv.URL
+	opts.Token = "test-token"
+	return NewGithubClient(&opts)
+}
+
+// newForgeTestClient returns a client of a fake GitHub API that serves
+// nothing, and the URL of a server of another provider serving h, which
+// checks every request is sent with the Authorization header wantAuth.
+func newForgeTestClient(t *testing.T, h http.Handler, wantAuth string, opts GithubClientOptions) (*GithubClient, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != wantAuth {
+			t.Errorf("%s sent with %q, want %q", r.URL, got, wantAuth)
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return newTestClient(t, http.NotFoundHandler(), opts), srv.URL
+}
+
+// callTool calls the tool name of a server set up by register through an
+// MCP session, as a client would.
+func callTool(t *testing.T, register func(*mcp.Server), name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0"}, nil)
+	register(server)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0"}, nil)
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// resultText returns the text of the first content of res.
+func resultText(res *mcp.CallToolResult) string {
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+func TestGetAllPagesNotesTruncation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/o/repos?page=2>; rel="next"`, r.Host))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `[{"name":"r%s","full_name":"o/r%s"}]`, page, page)
+	})
+	for maxPages, truncated := range map[int]bool{1: true, 2: false} {
+		gh := newTestClient(t, mux, GithubClientOptions{MaxPages: maxPages})
+		res := callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+			"name": "o", "account_type": "org",
+		})
+		var text strings.Builder
+		for _, c := range res.Content {
+			if tc, ok := c.(*mcp.TextContent); ok {
+				text.WriteString(tc.Text)
+			}
+		}
+		if got := strings.Contains(text.String(), "stopped after"); got != truncated {
+			t.Errorf("max pages %d: truncation noted = %v, want %v:\n%s", maxPages, got, truncated, text.String())
+		}
+	}
+}
+
+func TestNoteTruncatedConcurrently(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?page=2>; rel="next"`, r.Host))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[1]`))
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{MaxPages: 1})
+	notes := &listingNotes{}
+	ctx := context.WithValue(context.Background(), listingNotesKey{}, notes)
+
+	// Handlers run listings side by side, like the repositories of an
+	// organization.
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			if _, err := getAllPages[int](ctx, gh, gh.baseURL+"/items"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+	if !strings.Contains(notes.text(), "stopped after 1 pages") {
+		t.Errorf("note = %q, want the truncation", notes.text())
+	}
+}
+
+func TestInterruptedListingKeepsItems(t *testing.T) {
+	var cancel context.CancelFunc
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			// The call ends while the second page is fetched.
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?page=2>; rel="next"`, r.Host))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[1, 2]`))
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{})
+	list := func(ctx context.Context) ([]int, error) {
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		return getAllPages[int](ctx, gh, gh.baseURL+"/items")
+	}
+
+	items, err := list(context.Background())
+	var partial *partialError
+	if !errors.As(err, &partial) || len(items) != 2 {
+		t.Fatalf("outside a tool call: items %v, error %v, want 2 items and a partialError", items, err)
+	}
+
+	notes := &listingNotes{}
+	items, err = list(context.WithValue(context.Background(), listingNotesKey{}, notes))
+	if err != nil || len(items) != 2 {
+		t.Fatalf("in a tool call: items %v, error %v, want 2 items", items, err)
+	}
+	if !strings.Contains(notes.text(), "interrupted after 1 pages (2 items)") {
+		t.Errorf("note = %q, want the interruption", notes.text())
+	}
+}
+
+func TestDryRunToolDescribesMode(t *testing.T) {
+	tool := dryRunTool(mergePullRequestTool, true)
+	if !strings.Contains(tool.Description, "read-only, dry-run mode") {
+		t.Errorf("description %q doesn't mention the mode", tool.Description)
+	}
+	if !tool.Annotations.ReadOnlyHint || *tool.Annotations.DestructiveHint {
+		t.Errorf("annotations %+v don't mark the tool as read-only", tool.Annotations)
+	}
+	if mergePullRequestTool.Annotations.ReadOnlyHint || strings.Contains(mergePullRequestTool.Description, "dry-run") {
+		t.Error("the registered tool was changed")
+	}
+}
+
+// callForgeTool calls one of the tools going through a Forge, which are the
+// ones working with every provider.
+func callForgeTool(t *testing.T, gh *GithubClient, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	return callTool(t, func(s *mcp.Server) {
+		addTool(s, listRepositoriesTool, gh.ListRepositories)
+		addTool(s, listIssuesTool, gh.ListIssues)
+		addTool(s, listPullRequestsTool, gh.ListPullRequests)
+		addTool(s, getFileContentsTool, gh.GetFileContents)
+	}, name, args)
+}
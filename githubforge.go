@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxMergeableLookups is how many open pull requests of a listing get their
+// mergeability, which takes a request each, looked up. It is "unknown" for
+// the others.
+const maxMergeableLookups = 30
+
+// githubForge is the Forge of GitHub and GitHub Enterprise Server instances,
+// at the API root baseURL.
+type githubForge struct {
+	c       *GithubClient
+	baseURL string
+}
+
+func (f *githubForge) ListRepos(ctx context.Context, owner string, opts RepoListOptions) ([]Repository, error) {
+	if f.c.useGraphQL {
+		return f.c.graphQLRepositories(ctx, f.baseURL, owner, opts)
+	}
+	query := url.Values{}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if opts.Sort != "" {
+		query.Set("sort", opts.Sort)
+	}
+	if opts.Direction != "" {
+		query.Set("direction", opts.Direction)
+	}
+	return f.c.accountRepositories(ctx, f.baseURL, owner, opts.AccountType, query)
+}
+
+func (f *githubForge) GetFile(ctx context.Context, owner, repo, path, ref string) (*File, error) {
+	file, err := f.c.getFileContent(ctx, f.baseURL, owner, repo, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeFileContent(file)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: file.Path, Size: file.Size, Content: data}, nil
+}
+
+func (f *githubForge) ListIssues(ctx context.Context, owner, repo string, opts IssueListOptions) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(f.c.perPage))
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	if len(opts.Labels) > 0 {
+		query.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	if opts.Assignee != "" {
+		query.Set("assignee", opts.Assignee)
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues?%s", f.baseURL, url.PathEscape(owner), url.PathEscape(repo), query.Encode())
+
+	raw, err := getAllPages[issue](ctx, f.c, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	issues := []Issue{}
+	for _, is := range raw {
+		if is.PullRequest != nil {
+			continue
+		}
+		issues = append(issues, Issue{
+			Number: is.Number,
+			Title:  is.Title,
+			State:  is.State,
+			URL:    is.HTMLURL,
+			Labels: is.labelNames(),
+		})
+	}
+	return issues, nil
+}
+
+func (f *githubForge) ListRuns(ctx context.Context, args ListWorkflowRunsArgs) (*WorkflowRunListOutput, error) {
+	return f.c.workflowRuns(ctx, f.baseURL, args)
+}
+
+func (f *githubForge) ListPullRequests(ctx context.Context, owner, repo string, opts PullRequestListOptions) ([]PullRequest, error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(f.c.perPage))
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	if opts.Base != "" {
+		query.Set("base", opts.Base)
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", f.baseURL, url.PathEscape(owner), url.PathEscape(repo))
+
+	raw, err := getAllPages[pullRequest](ctx, f.c, repoURL+"/pulls?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	// The listing doesn't include mergeability, it is looked up on the
+	// first open pull requests, batchWorkers at a time.
+	mergeable := make([]string, len(raw))
+	errs := make([]error, len(raw))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	lookups := 0
+	for i, pr := range raw {
+		mergeable[i] = "unknown"
+		if pr.State != "open" || lookups == maxMergeableLookups {
+			continue
+		}
+		lookups++
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// GitHub computes mergeability lazily, so it may still be
+			// "unknown" the first time a pull request is fetched.
+			var detail pullRequest
+			if err := f.c.getJSON(ctx, fmt.Sprintf("%s/pulls/%d", repoURL, pr.Number), &detail); err != nil {
+				errs[i] = err
+				return
+			}
+			if detail.MergeableState != "" {
+				mergeable[i] = detail.MergeableState
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	pulls := []PullRequest{}
+	for i, pr := range raw {
+		pulls = append(pulls, PullRequest{
+			Number:    pr.Number,
+			Title:     pr.Title,
+			State:     pr.State,
+			Draft:     pr.Draft,
+			Author:    pr.User.Login,
+			Head:      pr.Head.Ref,
+			Base:      pr.Base.Ref,
+			URL:       pr.HTMLURL,
+			Mergeable: mergeable[i],
+		})
+	}
+	return pulls, nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitlabAPIURL is the API root of gitlab.com. Self-managed instances serve
+// it under https://<host>/api/v4.
+const gitlabAPIURL = "https://gitlab.com/api/v4"
+
+// addGitlab makes GitLab available as the "gitlab" provider, at the API root
+// baseURL and authenticated with token, a personal, group or project access
+// token, when it is set.
+func (c *GithubClient) addGitlab(baseURL, token string) {
+	baseURL = strings.TrimSuffix(cmp.Or(baseURL, gitlabAPIURL), "/")
+	var hosts []string
+	var sitePath string
+	if u, err := url.Parse(baseURL); err == nil {
+		hosts = append(hosts, u.Hostname())
+		sitePath = strings.TrimSuffix(u.Path, "/api/v4")
+	}
+	c.addProvider("gitlab", forgeProvider{
+		hosts: hosts,
+		forge: func(override string) Forge {
+			return &gitlabForge{c: c, baseURL: cmp.Or(override, baseURL)}
+		},
+		authorize:    bearerAuth(token),
+		nestedOwners: true,
+		sitePath:     sitePath,
+	})
+}
+
+// gitlabForge is the Forge of GitLab. Groups, including subgroups like
+// gitlab-org/charts, are the owners and projects the repositories; merge
+// requests are listed as pull requests, by their project-level IID.
+type gitlabForge struct {
+	c       *GithubClient
+	baseURL string
+}
+
+type gitlabProject struct {
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	WebURL            string    `json:"web_url"`
+	Visibility        string    `json:"visibility"`
+	Description       string    `json:"description"`
+	StarCount         int       `json:"star_count"`
+	Archived          bool      `json:"archived"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+	ForkedFromProject *struct{} `json:"forked_from_project"`
+}
+
+// projectURL returns the API URL of the project owner/repo, which GitLab
+// identifies by its URL encoded full path.
+func (f *gitlabForge) projectURL(owner, repo string) string {
+	return f.baseURL + "/projects/" + gitlabPathEscape(owner+"/"+repo)
+}
+
+// gitlabPathEscape escapes a slash separated path as a single path segment,
+// the way GitLab expects namespaces, projects and files to be given.
+func gitlabPathEscape(path string) string {
+	return strings.ReplaceAll(url.PathEscape(path), "/", "%2F")
+}
+
+func (f *gitlabForge) ListRepos(ctx context.Context, owner string, opts RepoListOptions) ([]Repository, error) {
+	if err := f.c.scope.checkOwner(owner); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(f.c.perPage))
+	switch opts.Sort {
+	case "created", "updated":
+		query.Set("order_by", opts.Sort+"_at")
+	case "pushed":
+		query.Set("order_by", "last_activity_at")
+	case "full_name":
+		query.Set("order_by", "path")
+	}
+	if opts.Direction != "" {
+		query.Set("sort", opts.Direction)
+	}
+
+	var projects []gitlabProject
+	var err error
+	if opts.AccountType != "user" {
+		query.Set("include_subgroups", "true")
+		projects, err = getAllPages[gitlabProject](ctx, f.c, fmt.Sprintf("%s/groups/%s/projects?%s", f.baseURL, gitlabPathEscape(owner), query.Encode()))
+		query.Del("include_subgroups")
+	}
+	if opts.AccountType == "user" || (opts.AccountType == "" && isNotFound(err)) {
+		projects, err = getAllPages[gitlabProject](ctx, f.c, fmt.Sprintf("%s/users/%s/projects?%s", f.baseURL, url.PathEscape(owner), query.Encode()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	repos := []Repository{}
+	for _, p := range projects {
+		if !f.c.scope.allowsFullName(p.PathWithNamespace) {
+			continue
+		}
+		fork := p.ForkedFromProject != nil
+		if (opts.Type == "forks" && !fork) || (opts.Type == "sources" && fork) {
+			continue
+		}
+		if (opts.Type == "public" && p.Visibility != "public") || (opts.Type == "private" && p.Visibility == "public") {
+			continue
+		}
+		repos = append(repos, Repository{
+			Name:            p.Path,
+			FullName:        p.PathWithNamespace,
+			HTMLURL:         p.WebURL,
+			Private:         p.Visibility != "public",
+			Description:     p.Description,
+			StargazersCount: p.StarCount,
+			Fork:            fork,
+			Archived:        p.Archived,
+			PushedAt:        p.LastActivityAt,
+		})
+	}
+	return repos, nil
+}
+
+func (f *gitlabForge) GetFile(ctx context.Context, owner, repo, path, ref string) (*File, error) {
+	if err := f.c.scope.checkRepo(owner, repo); err != nil {
+		return nil, err
+	}
+	// The raw endpoint reads from the default branch when ref is missing,
+	// unlike the JSON one.
+	apiURL := fmt.Sprintf("%s/repository/files/%s/raw", f.projectURL(owner, repo), gitlabPathEscape(strings.Trim(path, "/")))
+	if ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(ref)
+	}
+	resp, err := f.c.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: path, Size: len(data), Content: data}, nil
+}
+
+type gitlabIssue struct {
+	IID    int      `json:"iid"`
+	Title  string   `json:"title"`
+	State  string   `json:"state"`
+	WebURL string   `json:"web_url"`
+	Labels []string `json:"labels"`
+}
+
+func (f *gitlabForge) ListIssues(ctx context.Context, owner, repo string, opts IssueListOptions) ([]Issue, error) {
+	if err := f.c.scope.checkRepo(owner, repo); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(f.c.perPage))
+	query.Set("state", gitlabState(opts.State))
+	if len(opts.Labels) > 0 {
+		query.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	switch opts.Assignee {
+	case "":
+	case "none":
+		query.Set("assignee_id", "None")
+	case "*":
+		query.Set("assignee_id", "Any")
+	default:
+		query.Set("assignee_username", opts.Assignee)
+	}
+
+	raw, err := getAllPages[gitlabIssue](ctx, f.c, f.projectURL(owner, repo)+"/issues?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	issues := []Issue{}
+	for _, is := range raw {
+		issues = append(issues, Issue{
+			Number: is.IID,
+			Title:  is.Title,
+			State:  githubState(is.State),
+			URL:    is.WebURL,
+			Labels: append([]string{}, is.Labels...),
+		})
+	}
+	return issues, nil
+}
+
+type gitlabMergeRequest struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Draft  bool   `json:"draft"`
+	WebURL string `json:"web_url"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	SourceBranch        string `json:"source_branch"`
+	TargetBranch        string `json:"target_branch"`
+	DetailedMergeStatus string `json:"detailed_merge_status"`
+}
+
+func (f *gitlabForge) ListPullRequests(ctx context.Context, owner, repo string, opts PullRequestListOptions) ([]PullRequest, error) {
+	if err := f.c.scope.checkRepo(owner, repo); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(f.c.perPage))
+	// GitHub counts merged pull requests as closed, GitLab doesn't.
+	state := gitlabState(opts.State)
+	if state == "closed" {
+		query.Set("state", "all")
+	} else {
+		query.Set("state", state)
+	}
+	if opts.Base != "" {
+		query.Set("target_branch", opts.Base)
+	}
+
+	raw, err := getAllPages[gitlabMergeRequest](ctx, f.c, f.projectURL(owner, repo)+"/merge_requests?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	pulls := []PullRequest{}
+	for _, mr := range raw {
+		if state == "closed" && mr.State == "opened" {
+			continue
+		}
+		mergeable := "unknown"
+		if mr.State == "opened" && mr.DetailedMergeStatus != "" {
+			mergeable = mr.DetailedMergeStatus
+		}
+		pulls = append(pulls, PullRequest{
+			Number:    mr.IID,
+			Title:     mr.Title,
+			State:     githubState(mr.State),
+			Draft:     mr.Draft,
+			Author:    mr.Author.Username,
+			Head:      mr.SourceBranch,
+			Base:      mr.TargetBranch,
+			URL:       mr.WebURL,
+			Mergeable: mergeable,
+		})
+	}
+	return pulls, nil
+}
+
+// gitlabState translates the open, closed or all state filter of the tools,
+// open being the default, to GitLab's.
+func gitlabState(state string) string {
+	switch state {
+	case "closed", "all":
+		return state
+	default:
+		return "opened"
+	}
+}
+
+// githubState translates a GitLab state to the tools' vocabulary. Merged
+// and locked are kept as they are.
+func githubState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return state
+}
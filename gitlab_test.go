@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newGitlabClient returns a client whose gitlab provider is served by mux,
+// authenticated with the token glpat-test.
+func newGitlabClient(t *testing.T, mux *http.ServeMux) *GithubClient {
+	t.Helper()
+	gh, url := newForgeTestClient(t, mux, "Bearer glpat-test", GithubClientOptions{})
+	gh.addGitlab(url+"/api/v4", "glpat-test")
+	return gh
+}
+
+func TestGitlabListsSubgroupProjects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/groups/{group}/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("group") != "gitlab-org/charts" || r.URL.Query().Get("include_subgroups") != "true" || r.URL.Query().Get("order_by") != "last_activity_at" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		jsonHandler(`[
+			{"path":"gitlab","path_with_namespace":"gitlab-org/charts/gitlab","web_url":"https://gitlab.com/gitlab-org/charts/gitlab","visibility":"public","star_count":5},
+			{"path":"fork","path_with_namespace":"gitlab-org/charts/fork","visibility":"private","forked_from_project":{}}
+		]`)(w, r)
+	})
+	gh := newGitlabClient(t, mux)
+
+	res := callForgeTool(t, gh, "list-repositories", map[string]any{
+		"name": "gitlab-org/charts", "provider": "gitlab", "sort": "pushed", "type": "sources",
+	})
+	text := resultText(res)
+	if res.IsError || !strings.Contains(text, "Name: gitlab, URL: https://gitlab.com/gitlab-org/charts/gitlab") || strings.Contains(text, "fork") {
+		t.Errorf("list-repositories = %q, want only the gitlab project", text)
+	}
+}
+
+func TestGitlabFallsBackToUserProjects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/groups/{group}/projects", http.NotFound)
+	mux.HandleFunc("GET /api/v4/users/alice/projects", jsonHandler(`[{"path":"dotfiles","path_with_namespace":"alice/dotfiles","visibility":"public"}]`))
+	gh := newGitlabClient(t, mux)
+
+	res := callForgeTool(t, gh, "list-repositories", map[string]any{"name": "alice", "provider": "gitlab"})
+	if text := resultText(res); res.IsError || !strings.Contains(text, "Name: dotfiles") {
+		t.Errorf("list-repositories of a user = %q", text)
+	}
+}
+
+func TestGitlabMergeRequestsAndIssues(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		// Merged merge requests are closed ones too, so all are listed.
+		if r.PathValue("project") != "g/p" || r.URL.Query().Get("state") != "all" || r.URL.Query().Get("target_branch") != "main" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		jsonHandler(`[
+			{"iid":1,"title":"Open","state":"opened","detailed_merge_status":"mergeable"},
+			{"iid":2,"title":"Merged","state":"merged","author":{"username":"bob"},"source_branch":"fix","target_branch":"main","web_url":"https://gitlab.com/g/p/-/merge_requests/2"}
+		]`)(w, r)
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/issues", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("state") != "opened" || q.Get("assignee_id") != "None" || q.Get("labels") != "bug,ui" {
+			t.Errorf("unexpected listing %s", r.URL)
+		}
+		jsonHandler(`[{"iid":3,"title":"Broken","state":"opened","labels":["bug","ui"],"web_url":"https://gitlab.com/g/p/-/issues/3"}]`)(w, r)
+	})
+	gh := newGitlabClient(t, mux)
+
+	res := callForgeTool(t, gh, "list-pull-requests", map[string]any{"owner": "g", "repo": "p", "provider": "gitlab", "state": "closed", "base": "main"})
+	text := resultText(res)
+	if res.IsError || strings.Contains(text, "#1 ") || !strings.Contains(text, "#2 [merged] Merged by bob, fix -> main, mergeable: unknown") {
+		t.Errorf("list-pull-requests = %q, want only the merged one", text)
+	}
+	res = callForgeTool(t, gh, "list-issues", map[string]any{"owner": "g", "repo": "p", "provider": "gitlab", "assignee": "none", "labels": []string{"bug", "ui"}})
+	if text := resultText(res); res.IsError || !strings.Contains(text, "#3 [open] Broken (labels: bug, ui)") {
+		t.Errorf("list-issues = %q", text)
+	}
+}
+
+func TestGitlabGetFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/repository/files/{file}/raw", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("project") != "g/sub/p" || r.PathValue("file") != "docs/README.md" || r.URL.Query().Get("ref") != "v1.0" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte("# Hello"))
+	})
+	gh := newGitlabClient(t, mux)
+
+	res := callForgeTool(t, gh, "get-file-contents", map[string]any{"owner": "g/sub", "repo": "p", "path": "/docs/README.md", "ref": "v1.0", "provider": "gitlab"})
+	if text := resultText(res); res.IsError || text != "# Hello" {
+		t.Errorf("get-file-contents = %q", text)
+	}
+}
@@ -0,0 +1,649 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultLocalLogLimit = 30
+	// maxBlameLines bounds the lines blamed when end_line is omitted.
+	maxBlameLines = 500
+)
+
+var gitLogTool = &mcp.Tool{
+	Name:        "git-log",
+	Description: "A tool to list the commits of a local clone made by clone-repository, optionally only those touching some paths. It reads the history of the clone locally, without using the Github API rate limit",
+	Annotations: localAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"handle": handleProperty(),
+			"paths": {
+				Type:        "array",
+				Description: "Only list commits changing these files or directories",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"author": {
+				Type:        "string",
+				Description: "Only list commits whose author name or email contains this",
+			},
+			"since": {
+				Type:        "string",
+				Description: "Only list commits made after this date (e.g., 2024-01-31)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: fmt.Sprintf("Maximum number of commits to list (defaults to %d)", defaultLocalLogLimit),
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(1000.0),
+			},
+		},
+		Required: []string{"handle"},
+	},
+}
+
+type GitLogArgs struct {
+	CommonArgs
+	Handle string   `json:"handle"`
+	Paths  []string `json:"paths,omitempty"`
+	Author string   `json:"author,omitempty"`
+	Since  string   `json:"since,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+}
+
+type LocalCommit struct {
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+type GitLogOutput struct {
+	Commits []LocalCommit `json:"commits"`
+	// Shallow is set when the clone lacks older history, which may then
+	// hold more matching commits.
+	Shallow bool `json:"shallow"`
+}
+
+func (c *GithubClient) GitLog(ctx context.Context, req *mcp.CallToolRequest, args GitLogArgs) (*mcp.CallToolResult, GitLogOutput, error) {
+	dir, err := c.openClone(args.Handle)
+	if err != nil {
+		return nil, GitLogOutput{}, err
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLocalLogLimit
+	}
+	var since time.Time
+	if args.Since != "" {
+		if since, err = time.Parse(time.DateOnly, args.Since); err != nil {
+			return nil, GitLogOutput{}, fmt.Errorf("since must be a date like 2024-01-31, got %q", args.Since)
+		}
+	}
+
+	clone, err := openLocalClone(dir)
+	if err != nil {
+		return nil, GitLogOutput{}, err
+	}
+	head, err := clone.head()
+	if err != nil {
+		return nil, GitLogOutput{}, err
+	}
+	out := GitLogOutput{Commits: []LocalCommit{}, Shallow: len(clone.shallow) > 0}
+	// Newest commits first, like git log.
+	commits := object.NewCommitIterCTime(head, nil, nil)
+	defer commits.Close()
+	err = commits.ForEach(func(cm *object.Commit) error {
+		if len(out.Commits) == limit {
+			return storer.ErrStop
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if cm.Committer.When.Before(since) {
+			return nil
+		}
+		if args.Author != "" && !strings.Contains(cm.Author.String(), args.Author) {
+			return nil
+		}
+		if len(args.Paths) > 0 {
+			touched, err := touchesPaths(cm, args.Paths)
+			if err != nil || !touched {
+				return err
+			}
+		}
+		out.Commits = append(out.Commits, LocalCommit{
+			SHA:     cm.Hash.String(),
+			Author:  cm.Author.Name,
+			Email:   cm.Author.Email,
+			Date:    cm.Author.When,
+			Subject: commitSubject(cm.Message),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, GitLogOutput{}, fmt.Errorf("failed to read the history of %s: %w", args.Handle, err)
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d commits of %s", len(out.Commits), args.Handle)
+	if len(args.Paths) > 0 {
+		fmt.Fprintf(&result, " touching %s", strings.Join(args.Paths, ", "))
+	}
+	result.WriteString(":\n")
+	for _, cm := range out.Commits {
+		fmt.Fprintf(&result, "%s %s %s: %s\n", cm.SHA[:min(len(cm.SHA), 12)], cm.Date.Format(time.DateOnly), cm.Author, cm.Subject)
+	}
+	if out.Shallow && len(out.Commits) < limit {
+		fmt.Fprintf(&result, "The clone is shallow, clone it again with a larger depth to see older commits\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var gitBlameTool = &mcp.Tool{
+	Name:        "git-blame",
+	Description: "A tool to show which commit last changed each line of a range of a file in a local clone made by clone-repository. It blames locally, without using the Github API rate limit",
+	Annotations: localAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"handle": handleProperty(),
+			"path": {
+				Type:        "string",
+				Description: "Path of the file in the repository",
+			},
+			"start_line": {
+				Type:        "integer",
+				Description: "First line to blame (defaults to 1)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"end_line": {
+				Type:        "integer",
+				Description: fmt.Sprintf("Last line to blame (defaults to %d lines after start_line, or the end of the file)", maxBlameLines-1),
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+		},
+		Required: []string{"handle", "path"},
+	},
+}
+
+type GitBlameArgs struct {
+	CommonArgs
+	Handle    string `json:"handle"`
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+type BlameLine struct {
+	Line    int       `json:"line"`
+	Commit  string    `json:"commit"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Summary string    `json:"summary"`
+	// Boundary is set when the commit is the oldest of a shallow clone,
+	// which may not be the one that last changed the line.
+	Boundary bool   `json:"boundary"`
+	Content  string `json:"content"`
+}
+
+type GitBlameOutput struct {
+	Lines []BlameLine `json:"lines"`
+}
+
+func (c *GithubClient) GitBlame(ctx context.Context, req *mcp.CallToolRequest, args GitBlameArgs) (*mcp.CallToolResult, GitBlameOutput, error) {
+	dir, err := c.openClone(args.Handle)
+	if err != nil {
+		return nil, GitBlameOutput{}, err
+	}
+	if args.Path == "" {
+		return nil, GitBlameOutput{}, fmt.Errorf("path is required")
+	}
+	if !filepath.IsLocal(filepath.FromSlash(args.Path)) {
+		return nil, GitBlameOutput{}, fmt.Errorf("path %s is outside of the repository", args.Path)
+	}
+	clone, err := openLocalClone(dir)
+	if err != nil {
+		return nil, GitBlameOutput{}, err
+	}
+	head, err := clone.head()
+	if err != nil {
+		return nil, GitBlameOutput{}, err
+	}
+	file, err := head.File(args.Path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return nil, GitBlameOutput{}, fmt.Errorf("%s isn't a file of %s", args.Path, args.Handle)
+	}
+	if err != nil {
+		return nil, GitBlameOutput{}, err
+	}
+	data, err := file.Contents()
+	if err != nil {
+		return nil, GitBlameOutput{}, err
+	}
+	lines := strings.Count(data, "\n")
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+	start := max(args.StartLine, 1)
+	end := args.EndLine
+	if end <= 0 {
+		end = start + maxBlameLines - 1
+	}
+	end = min(end, lines)
+	if start > end {
+		return nil, GitBlameOutput{}, fmt.Errorf("%s has %d lines, no line in %d-%d", args.Path, lines, start, max(args.EndLine, start))
+	}
+
+	blame, err := git.Blame(head, args.Path)
+	if err != nil {
+		return nil, GitBlameOutput{}, fmt.Errorf("failed to blame %s: %w", args.Path, err)
+	}
+	out := GitBlameOutput{Lines: []BlameLine{}}
+	summaries := map[plumbing.Hash]string{}
+	for i := start; i <= min(end, len(blame.Lines)); i++ {
+		l := blame.Lines[i-1]
+		summary, ok := summaries[l.Hash]
+		if !ok {
+			cm, err := clone.commit(l.Hash)
+			if err != nil {
+				return nil, GitBlameOutput{}, err
+			}
+			summary = commitSubject(cm.Message)
+			summaries[l.Hash] = summary
+		}
+		out.Lines = append(out.Lines, BlameLine{
+			Line:     i,
+			Commit:   l.Hash.String(),
+			Author:   l.AuthorName,
+			Date:     l.Date.UTC(),
+			Summary:  summary,
+			Boundary: clone.shallow[l.Hash],
+			Content:  l.Text,
+		})
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Blame of %s:%d-%d in %s:\n", args.Path, start, end, args.Handle)
+	prev := ""
+	for _, l := range out.Lines {
+		if l.Commit != prev {
+			boundary := ""
+			if l.Boundary {
+				boundary = " (oldest commit of the shallow clone)"
+			}
+			fmt.Fprintf(&result, "%s %s %s: %s%s\n", l.Commit[:min(len(l.Commit), 12)], l.Date.Format(time.DateOnly), l.Author, l.Summary, boundary)
+			prev = l.Commit
+		}
+		fmt.Fprintf(&result, "%6d| %s\n", l.Line, l.Content)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var gitDiffTool = &mcp.Tool{
+	Name:        "git-diff",
+	Description: "A tool to read the unified diff between two refs of a local clone made by clone-repository, optionally of some paths only. It diffs locally, fetching the refs the clone lacks. Long diffs are returned in chunks",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"handle": handleProperty(),
+			"base": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to diff from",
+			},
+			"head": {
+				Type:        "string",
+				Description: "Branch, tag or commit SHA to diff to (defaults to the commit checked out in the clone)",
+			},
+			"paths": {
+				Type:        "array",
+				Description: "Only diff these files or directories",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Byte offset to start reading at, to continue a truncated diff",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"max_bytes": {
+				Type:        "integer",
+				Description: "Maximum number of bytes to return (defaults to 102400)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"handle", "base"},
+	},
+}
+
+type GitDiffArgs struct {
+	CommonArgs
+	Handle   string   `json:"handle"`
+	Base     string   `json:"base"`
+	Head     string   `json:"head,omitempty"`
+	Paths    []string `json:"paths,omitempty"`
+	Offset   int      `json:"offset,omitempty"`
+	MaxBytes int      `json:"max_bytes,omitempty"`
+}
+
+func (c *GithubClient) GitDiff(ctx context.Context, req *mcp.CallToolRequest, args GitDiffArgs) (*mcp.CallToolResult, any, error) {
+	dir, err := c.openClone(args.Handle)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Base == "" {
+		return nil, nil, fmt.Errorf("base is required")
+	}
+	base, err := c.resolveRef(ctx, args.CommonArgs, dir, args.Base)
+	if err != nil {
+		return nil, nil, err
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open clone: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, nil, err
+	}
+	headHash := head.Hash()
+	if args.Head != "" {
+		if headHash, err = c.resolveRef(ctx, args.CommonArgs, dir, args.Head); err != nil {
+			return nil, nil, err
+		}
+	}
+	diff, err := diffCommits(ctx, repo, base, headHash, args.Paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(diff) == 0 {
+		diff = fmt.Appendf(nil, "No differences between %s and %s\n", args.Base, cmp.Or(args.Head, "HEAD"))
+	}
+
+	maxBytes := args.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxFileBytes
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: chunk(diff, args.Offset, maxBytes)},
+		},
+	}, nil, nil
+}
+
+// resolveRef returns the commit of ref in the clone at dir, fetching it
+// when the clone doesn't have it, like a branch other than the cloned one.
+// Shallow clones only fetch the commit, full clones its whole history.
+func (c *GithubClient) resolveRef(ctx context.Context, args CommonArgs, dir, ref string) (plumbing.Hash, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to open clone: %w", err)
+	}
+	if h, err := repo.ResolveRevision(plumbing.Revision(ref)); err == nil {
+		if cm, err := peelCommit(repo.Storer, *h); err == nil {
+			return cm.Hash, nil
+		}
+	}
+	// The fetched ref and the shallow commits are shared by the fetches
+	// into the clone.
+	c.clonesMu.Lock()
+	defer c.clonesMu.Unlock()
+	before, err := repo.Storer.Shallow()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	depth := 0
+	if len(before) > 0 {
+		depth = 1
+	}
+	// The fetch goes to the host the clone was made from, whichever
+	// base_url this call passes.
+	origin, err := repo.Remote("origin")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	auth, err := c.gitAuth(ctx, args.Profile, origin.Config().URLs[0])
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	cm, err := fetchRef(ctx, repo, auth, ref, depth)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := restoreShallow(repo.Storer, before); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return cm.Hash, nil
+}
+
+// restoreShallow undoes the history a shallow fetch cut from the clone: the
+// fetch marks every commit it reached at its depth as shallow, including
+// commits of the clone whose parents are there.
+func restoreShallow(s storage.Storer, before []plumbing.Hash) error {
+	after, err := s.Shallow()
+	if err != nil {
+		return err
+	}
+	keep := []plumbing.Hash{}
+	for _, h := range after {
+		if !slices.Contains(before, h) {
+			cm, err := object.GetCommit(s, h)
+			if err != nil {
+				return err
+			}
+			if hasParents(s, cm) {
+				continue
+			}
+		}
+		keep = append(keep, h)
+	}
+	return s.SetShallow(keep)
+}
+
+// hasParents reports whether the parents of cm are all in s, a root commit
+// having none.
+func hasParents(s storer.EncodedObjectStorer, cm *object.Commit) bool {
+	for _, parent := range cm.ParentHashes {
+		if s.HasEncodedObject(parent) != nil {
+			return false
+		}
+	}
+	return cm.NumParents() > 0
+}
+
+// diffCommits returns the unified diff from the commit base to head,
+// limited to paths when there are any, with renames detected as git does.
+func diffCommits(ctx context.Context, repo *git.Repository, base, head plumbing.Hash, paths []string) ([]byte, error) {
+	var trees [2]*object.Tree
+	for i, h := range []plumbing.Hash{base, head} {
+		cm, err := peelCommit(repo.Storer, h)
+		if err != nil {
+			return nil, err
+		}
+		if trees[i], err = cm.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, trees[0], trees[1], object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", err)
+	}
+	if len(paths) > 0 {
+		changes = slices.DeleteFunc(changes, func(ch *object.Change) bool {
+			return !underPaths(ch.From.Name, paths) && !underPaths(ch.To.Name, paths)
+		})
+	}
+	patch, err := changes.PatchContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := patch.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// underPaths reports whether the file name is one of paths or in one of
+// them.
+func underPaths(name string, paths []string) bool {
+	if name == "" {
+		return false
+	}
+	for _, p := range paths {
+		p = strings.Trim(path.Clean("/"+p), "/")
+		if p == "" || name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// touchesPaths reports whether the commit cm changes any of paths compared
+// to its parents, as in git log -- paths: a merge changes them when it
+// differs from all of its parents.
+func touchesPaths(cm *object.Commit, paths []string) (bool, error) {
+	tree, err := cm.Tree()
+	if err != nil {
+		return false, err
+	}
+	if cm.NumParents() == 0 {
+		return changesPaths(nil, tree, paths)
+	}
+	parents := cm.Parents()
+	defer parents.Close()
+	touched := true
+	err = parents.ForEach(func(parent *object.Commit) error {
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return err
+		}
+		if touched, err = changesPaths(parentTree, tree, paths); err == nil && !touched {
+			return storer.ErrStop
+		}
+		return err
+	})
+	return touched, err
+}
+
+func changesPaths(from, to *object.Tree, paths []string) (bool, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return false, err
+	}
+	for _, ch := range changes {
+		if underPaths(ch.From.Name, paths) || underPaths(ch.To.Name, paths) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// commitSubject is the first paragraph of a commit message on one line, as
+// git log prints it.
+func commitSubject(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n\n")
+	return strings.Join(strings.Fields(subject), " ")
+}
+
+// localClone is a clone opened to read its history, which ends at the
+// shallow commits of a shallow clone: they are read without the parents the
+// clone lacks, as git does.
+type localClone struct {
+	repo    *git.Repository
+	objects storer.EncodedObjectStorer
+	shallow map[plumbing.Hash]bool
+}
+
+func openLocalClone(dir string) (*localClone, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open clone: %w", err)
+	}
+	hashes, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+	clone := &localClone{repo: repo, shallow: map[plumbing.Hash]bool{}}
+	for _, h := range hashes {
+		clone.shallow[h] = true
+	}
+	clone.objects = &shallowObjects{EncodedObjectStorer: repo.Storer, shallow: clone.shallow}
+	return clone, nil
+}
+
+// head returns the commit checked out in the clone.
+func (l *localClone) head() (*object.Commit, error) {
+	ref, err := l.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the commit of the clone: %w", err)
+	}
+	return l.commit(ref.Hash())
+}
+
+func (l *localClone) commit(h plumbing.Hash) (*object.Commit, error) {
+	return object.GetCommit(l.objects, h)
+}
+
+// shallowObjects reads the shallow commits of a clone as root commits.
+type shallowObjects struct {
+	storer.EncodedObjectStorer
+	shallow map[plumbing.Hash]bool
+}
+
+func (s *shallowObjects) EncodedObject(t plumbing.ObjectType, h plumbing.Hash) (plumbing.EncodedObject, error) {
+	obj, err := s.EncodedObjectStorer.EncodedObject(t, h)
+	if err != nil || !s.shallow[h] || obj.Type() != plumbing.CommitObject {
+		return obj, err
+	}
+	var cm object.Commit
+	if err := cm.Decode(obj); err != nil {
+		return nil, err
+	}
+	cm.ParentHashes = nil
+	root := &plumbing.MemoryObject{}
+	if err := cm.Encode(root); err != nil {
+		return nil, err
+	}
+	return rootCommit{root, h}, nil
+}
+
+// rootCommit is a commit encoded again without its parents, which keeps its
+// hash.
+type rootCommit struct {
+	plumbing.EncodedObject
+	hash plumbing.Hash
+}
+
+func (c rootCommit) Hash() plumbing.Hash { return c.hash }
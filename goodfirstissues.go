@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultContributionLabels are the labels find-good-first-issues looks for
+// when none are given.
+var defaultContributionLabels = []string{"good first issue", "help wanted"}
+
+var findGoodFirstIssuesTool = &mcp.Tool{
+	Name:        "find-good-first-issues",
+	Description: "A tool to find open issues for newcomers, labeled good first issue or help wanted, across the repositories of a Github organization or user, with the language and recent activity of their repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Organization or user to search (e.g., kubernetes)",
+			},
+			"labels": {
+				Type:        "array",
+				Description: "Issues with any of these labels are returned (defaults to \"good first issue\" and \"help wanted\")",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"language": {
+				Type:        "string",
+				Description: "Only return issues of repositories in this language (e.g., go)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of issues to return, most recently updated first (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type FindGoodFirstIssuesArgs struct {
+	CommonArgs
+	Owner    string   `json:"owner"`
+	Labels   []string `json:"labels,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Limit    int      `json:"limit,omitempty"`
+}
+
+type GoodFirstIssue struct {
+	Repository string    `json:"repository"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Labels     []string  `json:"labels"`
+	Comments   int       `json:"comments"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	HTMLURL    string    `json:"html_url"`
+	// The repository's language, stars and last push tell how active a
+	// project the issue belongs to is.
+	Language string    `json:"language"`
+	Stars    int       `json:"stars"`
+	LastPush time.Time `json:"last_push"`
+	Topics   []string  `json:"topics"`
+}
+
+type GoodFirstIssuesOutput struct {
+	Query      string           `json:"query"`
+	TotalCount int              `json:"total_count"`
+	Issues     []GoodFirstIssue `json:"issues"`
+}
+
+func (c *GithubClient) FindGoodFirstIssues(ctx context.Context, req *mcp.CallToolRequest, args FindGoodFirstIssuesArgs) (*mcp.CallToolResult, GoodFirstIssuesOutput, error) {
+	if err := elicitMissing(ctx, req, "Which organization or user should be searched?", ownerField(&args.Owner)); err != nil {
+		return nil, GoodFirstIssuesOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, GoodFirstIssuesOutput{}, fmt.Errorf("owner is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	accountType, err := c.accountType(ctx, baseURL, args.Owner)
+	if err != nil {
+		return nil, GoodFirstIssuesOutput{}, err
+	}
+	labels := args.Labels
+	if len(labels) == 0 {
+		labels = defaultContributionLabels
+	}
+	// Comma separated labels match any of them.
+	quoted := make([]string, len(labels))
+	for i, l := range labels {
+		quoted[i] = fmt.Sprintf("%q", l)
+	}
+	terms := []string{map[string]string{"org": "org:", "user": "user:"}[accountType] + args.Owner, "is:issue", "is:open", "no:assignee", "archived:false", "label:" + strings.Join(quoted, ",")}
+	if args.Language != "" {
+		terms = append(terms, "language:"+args.Language)
+	}
+	query := strings.Join(terms, " ")
+
+	var found struct {
+		TotalCount int               `json:"total_count"`
+		Items      []issueSearchItem `json:"items"`
+	}
+	if err := c.search(ctx, baseURL, "issues", searchQuery(query, "updated", "desc", args.Limit), "", &found); err != nil {
+		return nil, GoodFirstIssuesOutput{}, err
+	}
+
+	// Each repository is looked up once, batchWorkers at a time.
+	var names []string
+	for _, item := range found.Items {
+		if name := repoFromAPIURL(item.RepositoryURL); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	repos := map[string]*repositoryDetails{}
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for _, name := range names {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			owner, repo, _ := strings.Cut(name, "/")
+			details, err := c.repository(ctx, baseURL, owner, repo)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("getting %s: %w", name, err))
+				return
+			}
+			repos[name] = details
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, GoodFirstIssuesOutput{}, err
+	}
+
+	out := GoodFirstIssuesOutput{Query: query, TotalCount: found.TotalCount, Issues: []GoodFirstIssue{}}
+	for _, item := range found.Items {
+		name := repoFromAPIURL(item.RepositoryURL)
+		repo := repos[name]
+		out.Issues = append(out.Issues, GoodFirstIssue{
+			Repository: name,
+			Number:     item.Number,
+			Title:      item.Title,
+			Labels:     item.labelNames(),
+			Comments:   item.Comments,
+			CreatedAt:  item.CreatedAt,
+			UpdatedAt:  item.UpdatedAt,
+			HTMLURL:    item.HTMLURL,
+			Language:   repo.Language,
+			Stars:      repo.StargazersCount,
+			LastPush:   repo.PushedAt,
+			Topics:     repo.Topics,
+		})
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d issues for newcomers in %s, showing %d:\n", out.TotalCount, args.Owner, len(out.Issues))
+	for _, is := range out.Issues {
+		fmt.Fprintf(&result, "%s#%d %s (labels: %s, %d comments, updated %s) %s\n",
+			is.Repository, is.Number, is.Title, strings.Join(is.Labels, ", "), is.Comments, is.UpdatedAt.Format(time.DateOnly), is.HTMLURL)
+		fmt.Fprintf(&result, "  Repository: %s, %d stars, last push %s\n", is.Language, is.Stars, is.LastPush.Format(time.DateOnly))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// graphQLURL returns the GraphQL endpoint of the REST API root baseURL:
+// https://api.github.com/graphql, or https://<host>/api/graphql on GitHub
+// Enterprise Server.
+func graphQLURL(baseURL string) string {
+	if root, ok := strings.CutSuffix(baseURL, "/api/v3"); ok {
+		return root + "/api/graphql"
+	}
+	return baseURL + "/graphql"
+}
+
+// graphQL runs the GraphQL query with variables against the API of baseURL
+// and decodes its data into out. Queries only read, so they are sent even
+// when the server is read-only or in a dry run.
+func (c *GithubClient) graphQL(ctx context.Context, baseURL, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, graphQLURL(baseURL), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := decodeJSON(resp, &result); err != nil {
+		return err
+	}
+	// Errors come with a 200 status, and possibly partial data.
+	if len(result.Errors) > 0 {
+		var messages []string
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		status := http.StatusBadRequest
+		if result.Errors[0].Type == "NOT_FOUND" {
+			status = http.StatusNotFound
+		}
+		return &apiError{StatusCode: status, Body: strings.Join(messages, "; ")}
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// repositoriesQuery lists a page of the repositories of an organization or
+// user with their topics and latest release, which the REST API would need
+// a request per repository for.
+const repositoriesQuery = `query($owner: String!, $first: Int!, $after: String, $privacy: RepositoryPrivacy, $isFork: Boolean, $affiliations: [RepositoryAffiliation], $orderBy: RepositoryOrder) {
+  repositoryOwner(login: $owner) {
+    repositories(first: $first, after: $after, privacy: $privacy, isFork: $isFork, ownerAffiliations: $affiliations, orderBy: $orderBy) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        nameWithOwner
+        url
+        isPrivate
+        description
+        primaryLanguage { name }
+        stargazerCount
+        isFork
+        isArchived
+        pushedAt
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+        latestRelease { tagName }
+      }
+    }
+  }
+}`
+
+type graphQLRepository struct {
+	Name            string `json:"name"`
+	NameWithOwner   string `json:"nameWithOwner"`
+	URL             string `json:"url"`
+	IsPrivate       bool   `json:"isPrivate"`
+	Description     string `json:"description"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	StargazerCount   int       `json:"stargazerCount"`
+	IsFork           bool      `json:"isFork"`
+	IsArchived       bool      `json:"isArchived"`
+	PushedAt         time.Time `json:"pushedAt"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	LatestRelease *struct {
+		TagName string `json:"tagName"`
+	} `json:"latestRelease"`
+}
+
+// graphQLRepositories is the GraphQL counterpart of accountRepositories,
+// taking the same filters. The owner is looked up by login whatever its
+// type, so accountType only matters to the type filter.
+func (c *GithubClient) graphQLRepositories(ctx context.Context, baseURL, owner string, opts RepoListOptions) ([]Repository, error) {
+	if err := c.scope.checkOwner(owner); err != nil {
+		return nil, err
+	}
+	first := min(c.perPage, 100)
+	variables := map[string]any{"owner": owner, "first": first}
+	switch opts.Type {
+	case "public":
+		variables["privacy"] = "PUBLIC"
+	case "private":
+		variables["privacy"] = "PRIVATE"
+	case "forks":
+		variables["isFork"] = true
+	case "sources":
+		variables["isFork"] = false
+	case "member":
+		variables["affiliations"] = []string{"COLLABORATOR", "ORGANIZATION_MEMBER"}
+	case "all":
+		if opts.AccountType == "user" {
+			variables["affiliations"] = []string{"OWNER", "COLLABORATOR", "ORGANIZATION_MEMBER"}
+		}
+	}
+	if variables["affiliations"] == nil {
+		variables["affiliations"] = []string{"OWNER"}
+	}
+	if field := map[string]string{"created": "CREATED_AT", "updated": "UPDATED_AT", "pushed": "PUSHED_AT", "full_name": "NAME"}[opts.Sort]; field != "" {
+		// The REST API sorts names ascending and dates descending by
+		// default.
+		direction := strings.ToUpper(opts.Direction)
+		if direction == "" {
+			direction = "DESC"
+			if opts.Sort == "full_name" {
+				direction = "ASC"
+			}
+		}
+		variables["orderBy"] = map[string]string{"field": field, "direction": direction}
+	}
+
+	progress := progressFrom(ctx)
+	base := progress.value()
+	repos := []Repository{}
+	for page := 0; page < c.maxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return repos, interrupted(ctx, &partialError{Pages: page, Items: len(repos), Err: err})
+		}
+		var data struct {
+			RepositoryOwner *struct {
+				Repositories struct {
+					TotalCount int `json:"totalCount"`
+					PageInfo   struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []graphQLRepository `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"repositoryOwner"`
+		}
+		if err := c.graphQL(ctx, baseURL, repositoriesQuery, variables, &data); err != nil {
+			if ctx.Err() != nil && page > 0 {
+				return repos, interrupted(ctx, &partialError{Pages: page, Items: len(repos), Err: ctx.Err()})
+			}
+			return nil, err
+		}
+		if data.RepositoryOwner == nil {
+			return nil, &apiError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no organization or user %s", owner)}
+		}
+		list := data.RepositoryOwner.Repositories
+		for _, r := range list.Nodes {
+			// The GraphQL endpoint isn't checked by path like the REST
+			// API, so leave out the repositories out of scope here.
+			if !c.scope.allowsFullName(r.NameWithOwner) {
+				continue
+			}
+			repo := Repository{
+				Name:            r.Name,
+				FullName:        r.NameWithOwner,
+				HTMLURL:         r.URL,
+				Private:         r.IsPrivate,
+				Description:     r.Description,
+				StargazersCount: r.StargazerCount,
+				Fork:            r.IsFork,
+				Archived:        r.IsArchived,
+				PushedAt:        r.PushedAt,
+			}
+			if r.PrimaryLanguage != nil {
+				repo.Language = r.PrimaryLanguage.Name
+			}
+			for _, t := range r.RepositoryTopics.Nodes {
+				repo.Topics = append(repo.Topics, t.Topic.Name)
+			}
+			if r.LatestRelease != nil {
+				repo.LatestRelease = r.LatestRelease.TagName
+			}
+			repos = append(repos, repo)
+		}
+		pages := (list.TotalCount + first - 1) / first
+		progress.update(ctx, base+float64(page+1), base+float64(min(pages, c.maxPages)), fmt.Sprintf("Fetched page %d", page+1))
+		if !list.PageInfo.HasNextPage {
+			break
+		}
+		if page+1 == c.maxPages {
+			noteTruncated(ctx, c.maxPages)
+		}
+		variables["after"] = list.PageInfo.EndCursor
+	}
+	return repos, nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultGrepContext = 2
+	defaultGrepLimit   = 100
+	// maxGrepLineBytes truncates the long lines of minified files.
+	maxGrepLineBytes = 500
+)
+
+var grepRepositoryTool = &mcp.Tool{
+	Name:        "grep-repository",
+	Description: "A tool to search the files of a local clone made by clone-repository for a regular expression, returning the file, line number and matching line with the lines around it. Without a clone it falls back to the Github code search, which matches words rather than regular expressions",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"handle": handleProperty(),
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes), searched with the code search without handle",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl), searched with the code search without handle",
+			},
+			"pattern": {
+				Type:        "string",
+				Description: "Regular expression to search for, in Go syntax (e.g., func New\\w+Client)",
+			},
+			"paths": {
+				Type:        "array",
+				Description: "Only search these directories, or files matching these glob patterns (e.g., *.go)",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"ignore_case": {
+				Type:        "boolean",
+				Description: "Match regardless of case",
+			},
+			"context": {
+				Type:        "integer",
+				Description: fmt.Sprintf("Number of lines to show before and after each match (defaults to %d)", defaultGrepContext),
+				Minimum:     jsonschema.Ptr(0.0),
+				Maximum:     jsonschema.Ptr(20.0),
+			},
+			"limit": {
+				Type:        "integer",
+				Description: fmt.Sprintf("Maximum number of matches to return (defaults to %d)", defaultGrepLimit),
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(1000.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"pattern"},
+	},
+}
+
+type GrepRepositoryArgs struct {
+	CommonArgs
+	Handle     string   `json:"handle,omitempty"`
+	Owner      string   `json:"owner,omitempty"`
+	Repo       string   `json:"repo,omitempty"`
+	Pattern    string   `json:"pattern"`
+	Paths      []string `json:"paths,omitempty"`
+	IgnoreCase bool     `json:"ignore_case,omitempty"`
+	Context    *int     `json:"context,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+}
+
+type GrepMatch struct {
+	Path string `json:"path"`
+	// Line is zero for code search results, which only come with a
+	// fragment of the file.
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+type GrepOutput struct {
+	// Source is clone or code_search.
+	Source  string      `json:"source"`
+	Matches []GrepMatch `json:"matches"`
+	// Truncated is set when the search stopped at the limit.
+	Truncated bool `json:"truncated"`
+}
+
+func (c *GithubClient) GrepRepository(ctx context.Context, req *mcp.CallToolRequest, args GrepRepositoryArgs) (*mcp.CallToolResult, GrepOutput, error) {
+	if args.Pattern == "" {
+		return nil, GrepOutput{}, fmt.Errorf("pattern is required")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultGrepLimit
+	}
+	contextLines := defaultGrepContext
+	if args.Context != nil {
+		contextLines = max(*args.Context, 0)
+	}
+
+	var out GrepOutput
+	var result strings.Builder
+	if args.Handle != "" {
+		dir, err := c.openClone(args.Handle)
+		if err != nil {
+			return nil, GrepOutput{}, err
+		}
+		expr := args.Pattern
+		if args.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, GrepOutput{}, fmt.Errorf("invalid pattern: %w", err)
+		}
+		if out, err = grepClone(ctx, dir, re, args.Paths, contextLines, limit); err != nil {
+			return nil, GrepOutput{}, err
+		}
+		fmt.Fprintf(&result, "%d matches of %s in %s:\n", len(out.Matches), args.Pattern, args.Handle)
+	} else {
+		if err := elicitMissing(ctx, req, "Which repository should be searched?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+			return nil, GrepOutput{}, err
+		}
+		if args.Owner == "" || args.Repo == "" {
+			return nil, GrepOutput{}, fmt.Errorf("handle, or owner and repo, are required")
+		}
+		q := qualify(args.Pattern, "repo", args.Owner+"/"+args.Repo)
+		var found codeSearchResult
+		if err := c.search(ctx, c.apiURL(args.CommonArgs), "code", searchQuery(q, "", "", min(limit, 100)), "application/vnd.github.text-match+json", &found); err != nil {
+			return nil, GrepOutput{}, err
+		}
+		out = GrepOutput{Source: "code_search", Matches: []GrepMatch{}, Truncated: found.TotalCount > len(found.Items)}
+		for _, item := range found.Items {
+			if !matchesPaths(item.Path, args.Paths) {
+				continue
+			}
+			for _, m := range item.TextMatches {
+				out.Matches = append(out.Matches, GrepMatch{Path: item.Path, Text: m.Fragment, Before: []string{}, After: []string{}})
+			}
+		}
+		fmt.Fprintf(&result, "%d code search matches of %q in %s/%s, clone it with clone-repository to search for a regular expression with line numbers:\n", len(out.Matches), args.Pattern, args.Owner, args.Repo)
+	}
+
+	for _, m := range out.Matches {
+		if m.Line == 0 {
+			fmt.Fprintf(&result, "--\n%s:\n%s\n", m.Path, m.Text)
+			continue
+		}
+		result.WriteString("--\n")
+		for i, line := range m.Before {
+			fmt.Fprintf(&result, "%s-%d-%s\n", m.Path, m.Line-len(m.Before)+i, line)
+		}
+		fmt.Fprintf(&result, "%s:%d:%s\n", m.Path, m.Line, m.Text)
+		for i, line := range m.After {
+			fmt.Fprintf(&result, "%s-%d-%s\n", m.Path, m.Line+1+i, line)
+		}
+	}
+	if out.Truncated {
+		fmt.Fprintf(&result, "--\n[... more matches not shown, narrow the search with paths or raise the limit ...]\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// grepClone searches the text files of the clone at dir for re, stopping
+// after limit matches.
+func grepClone(ctx context.Context, dir string, re *regexp.Regexp, paths []string, contextLines, limit int) (GrepOutput, error) {
+	out := GrepOutput{Source: "clone", Matches: []GrepMatch{}}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !matchesPaths(rel, paths) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			return nil
+		}
+		var lines []string
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data)+1)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		for i, line := range lines {
+			if !re.MatchString(line) {
+				continue
+			}
+			if len(out.Matches) == limit {
+				out.Truncated = true
+				return filepath.SkipAll
+			}
+			m := GrepMatch{Path: rel, Line: i + 1, Text: clipLine(line), Before: []string{}, After: []string{}}
+			for _, l := range lines[max(i-contextLines, 0):i] {
+				m.Before = append(m.Before, clipLine(l))
+			}
+			for _, l := range lines[i+1 : min(i+1+contextLines, len(lines))] {
+				m.After = append(m.After, clipLine(l))
+			}
+			out.Matches = append(out.Matches, m)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// matchesPaths reports whether the file at rel is in one of the directories,
+// or matches one of the glob patterns, of paths. Patterns without a slash
+// are matched against the file name.
+func matchesPaths(rel string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+		if rel == p || strings.HasPrefix(rel, p+"/") {
+			return true
+		}
+		name := rel
+		if !strings.Contains(p, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func clipLine(line string) string {
+	if len(line) <= maxGrepLineBytes {
+		return line
+	}
+	return strings.ToValidUTF8(line[:maxGrepLineBytes], "") + " [...]"
+}
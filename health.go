@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// readyTimeout bounds the GitHub requests of a readiness probe.
+const readyTimeout = 5 * time.Second
+
+// addProbes adds the Kubernetes style probes to mux: /healthz answers as
+// long as the process serves requests, /readyz once GitHub can be used.
+func addProbes(mux *http.ServeMux, gh *GithubClient) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		if err := gh.Ready(ctx); err != nil {
+			slog.Warn("Not ready", "err", err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// Ready reports why the client can't serve the tools: the server is still
+// starting, GitHub can't be reached, the token is invalid or the core rate
+// limit is exhausted. It checks the rate limit, which uses no quota, and
+// only falls back to ValidateToken when rate limiting is disabled on the
+// server.
+func (c *GithubClient) Ready(ctx context.Context) error {
+	if !c.started.Load() {
+		return fmt.Errorf("the server is still starting")
+	}
+	ctx = context.WithValue(ctx, commonArgsKey{}, CommonArgs{NoCache: true})
+	var limits struct {
+		Resources struct {
+			Core struct {
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	err := c.getJSON(ctx, c.baseURL+"/rate_limit", &limits)
+	switch {
+	case isNotFound(err):
+		if err := c.ValidateToken(ctx); err != nil {
+			return fmt.Errorf("checking the GitHub token: %w", err)
+		}
+		return nil
+	case errorClass(err) == "unauthorized":
+		return fmt.Errorf("GitHub token is invalid or expired")
+	case err != nil:
+		return fmt.Errorf("checking the GitHub rate limit: %w", err)
+	}
+	if core := limits.Resources.Core; core.Remaining == 0 {
+		if reset := time.Unix(core.Reset, 0); reset.After(time.Now()) {
+			return fmt.Errorf("GitHub rate limit exhausted until %s", reset.UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbes(t *testing.T) {
+	// rateLimit is how GitHub answers /rate_limit, an HTTP status or the
+	// remaining core requests.
+	var rateLimit string
+	gh := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/user":
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		case r.URL.Path != "/rate_limit":
+			http.NotFound(w, r)
+		case rateLimit == "401" || rateLimit == "404" || rateLimit == "502":
+			var status int
+			fmt.Sscan(rateLimit, &status)
+			http.Error(w, `{"message":"failed"}`, status)
+		default:
+			fmt.Fprintf(w, `{"resources":{"core":{"remaining":%s,"reset":%d}}}`, rateLimit, time.Now().Add(time.Hour).Unix())
+		}
+	}), GithubClientOptions{})
+	mux := http.NewServeMux()
+	addProbes(mux, gh)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	probe := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// Until the server is set up, it is alive but not ready.
+	rateLimit = "5000"
+	if status, body := probe("/healthz"); status != http.StatusOK {
+		t.Errorf("/healthz while starting = %d %q", status, body)
+	}
+	if status, body := probe("/readyz"); status != http.StatusServiceUnavailable || !strings.Contains(body, "still starting") {
+		t.Errorf("/readyz while starting = %d %q", status, body)
+	}
+	gh.started.Store(true)
+
+	for _, tt := range []struct {
+		rateLimit  string
+		wantStatus int
+		wantBody   string
+	}{
+		{"5000", http.StatusOK, "ok"},
+		{"0", http.StatusServiceUnavailable, "rate limit exhausted until"},
+		{"401", http.StatusServiceUnavailable, "token is invalid or expired"},
+		{"502", http.StatusServiceUnavailable, "checking the GitHub rate limit"},
+		// GitHub Enterprise Server without rate limiting checks the token.
+		{"404", http.StatusOK, "ok"},
+	} {
+		rateLimit = tt.rateLimit
+		if status, body := probe("/readyz"); status != tt.wantStatus || !strings.Contains(body, tt.wantBody) {
+			t.Errorf("/readyz with /rate_limit %s = %d %q, want %d %q", tt.rateLimit, status, body, tt.wantStatus, tt.wantBody)
+		}
+		if status, _ := probe("/healthz"); status != http.StatusOK {
+			t.Errorf("/healthz with /rate_limit %s = %d, want it alive", tt.rateLimit, status)
+		}
+	}
+}
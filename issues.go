@@ -0,0 +1,469 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listIssuesTool = &mcp.Tool{
+	Name:        "list-issues",
+	Description: "A tool to list the issues of a Github repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"state": {
+				Type:        "string",
+				Description: "Only return issues in this state (defaults to open)",
+				Enum:        []any{"open", "closed", "all"},
+			},
+			"labels": {
+				Type:        "array",
+				Description: "Only return issues carrying all of these labels",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"assignee": {
+				Type:        "string",
+				Description: "Only return issues assigned to this user, \"none\" for unassigned or \"*\" for any",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"provider": providerProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListIssuesArgs struct {
+	CommonArgs
+	Owner    string   `json:"owner"`
+	Repo     string   `json:"repo"`
+	State    string   `json:"state,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	Assignee string   `json:"assignee,omitempty"`
+}
+
+type issue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	// PullRequest is set when the issue is actually a pull request; the
+	// issues endpoint returns both.
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (is *issue) labelNames() []string {
+	labels := []string{}
+	for _, l := range is.Labels {
+		labels = append(labels, l.Name)
+	}
+	return labels
+}
+
+func (c *GithubClient) ListIssues(ctx context.Context, req *mcp.CallToolRequest, args ListIssuesArgs) (*mcp.CallToolResult, any, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, nil, fmt.Errorf("owner and repo are required")
+	}
+	forge, err := c.forge(args.CommonArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	issues, err := forge.ListIssues(ctx, args.Owner, args.Repo, IssueListOptions{
+		State:    args.State,
+		Labels:   args.Labels,
+		Assignee: args.Assignee,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var result strings.Builder
+	fmt.Fprintf(&result, "Issues for repository %s/%s:\n", args.Owner, args.Repo)
+	for _, is := range issues {
+		fmt.Fprintf(&result, "#%d [%s] %s (labels: %s) %s\n", is.Number, is.State, is.Title, strings.Join(is.Labels, ", "), is.URL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, nil, nil
+}
+
+var createIssueTool = &mcp.Tool{
+	Name:        "create-issue",
+	Description: "A tool to open a new issue in a Github repository",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"title": {
+				Type:        "string",
+				Description: "Title of the issue",
+			},
+			"body": {
+				Type:        "string",
+				Description: "Description of the issue, in Markdown",
+			},
+			"labels": {
+				Type:        "array",
+				Description: "Labels to apply to the issue",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"assignees": {
+				Type:        "array",
+				Description: "GitHub logins to assign the issue to",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "title"},
+	},
+}
+
+type CreateIssueArgs struct {
+	CommonArgs
+	Owner     string   `json:"owner"`
+	Repo      string   `json:"repo"`
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+type CreatedIssue struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (c *GithubClient) CreateIssue(ctx context.Context, req *mcp.CallToolRequest, args CreateIssueArgs) (*mcp.CallToolResult, CreatedIssue, error) {
+	if args.Owner == "" || args.Repo == "" || args.Title == "" {
+		return nil, CreatedIssue{}, fmt.Errorf("owner, repo and title are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	payload := map[string]any{"title": args.Title}
+	if args.Body != "" {
+		payload["body"] = args.Body
+	}
+	if len(args.Labels) > 0 {
+		payload["labels"] = args.Labels
+	}
+	if len(args.Assignees) > 0 {
+		payload["assignees"] = args.Assignees
+	}
+
+	var created CreatedIssue
+	if err := c.sendJSON(ctx, http.MethodPost, apiURL, payload, &created); err != nil {
+		return nil, CreatedIssue{}, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Created issue #%d in %s/%s: %s\n", created.Number, args.Owner, args.Repo, created.HTMLURL)},
+		},
+	}, created, nil
+}
+
+var commentOnIssueTool = &mcp.Tool{
+	Name:        "comment-on-issue-or-pr",
+	Description: "A tool to post a comment on an issue or pull request of a Github repository",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the issue or pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"body": {
+				Type:        "string",
+				Description: "Text of the comment, in Markdown",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "number", "body"},
+	},
+}
+
+type CommentArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Body   string `json:"body"`
+}
+
+type Comment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (c *GithubClient) CommentOnIssue(ctx context.Context, req *mcp.CallToolRequest, args CommentArgs) (*mcp.CallToolResult, Comment, error) {
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 || args.Body == "" {
+		return nil, Comment{}, fmt.Errorf("owner, repo, number and body are required")
+	}
+	// Pull requests are issues too, so both are commented on through the
+	// issues endpoint.
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.Number)
+
+	var comment Comment
+	if err := c.sendJSON(ctx, http.MethodPost, apiURL, map[string]string{"body": args.Body}, &comment); err != nil {
+		return nil, Comment{}, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Commented on %s/%s#%d: %s\n\n%s\n", args.Owner, args.Repo, args.Number, comment.HTMLURL, comment.Body)},
+		},
+	}, comment, nil
+}
+
+var getIssueTool = &mcp.Tool{
+	Name:        "get-issue",
+	Description: "A tool to read an issue or pull request of a Github repository with its full description and comment thread. Long threads are returned in pages",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the issue or pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Number of comments to skip, to continue a truncated thread",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of comments to return (defaults to 50)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type GetIssueArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+type IssueComment struct {
+	ID     int64  `json:"id"`
+	Author string `json:"author"`
+	// AuthorAssociation tells how the author relates to the repository,
+	// like MEMBER, CONTRIBUTOR or NONE.
+	AuthorAssociation string    `json:"author_association"`
+	Body              string    `json:"body"`
+	CreatedAt         time.Time `json:"created_at"`
+	HTMLURL           string    `json:"html_url"`
+}
+
+type IssueDetail struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	// StateReason tells why a closed issue was closed, like completed or
+	// not_planned.
+	StateReason   string         `json:"state_reason,omitempty"`
+	PullRequest   bool           `json:"pull_request"`
+	Author        string         `json:"author"`
+	Labels        []string       `json:"labels"`
+	Assignees     []string       `json:"assignees"`
+	Milestone     string         `json:"milestone,omitempty"`
+	Body          string         `json:"body"`
+	CreatedAt     time.Time      `json:"created_at"`
+	ClosedAt      *time.Time     `json:"closed_at,omitempty"`
+	HTMLURL       string         `json:"html_url"`
+	TotalComments int            `json:"total_comments"`
+	Comments      []IssueComment `json:"comments"`
+	// NextOffset is the offset to read the rest of the thread from, 0 when
+	// all comments were returned.
+	NextOffset int `json:"next_offset,omitempty"`
+}
+
+func (c *GithubClient) GetIssue(ctx context.Context, req *mcp.CallToolRequest, args GetIssueArgs) (*mcp.CallToolResult, IssueDetail, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, IssueDetail{}, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, IssueDetail{}, fmt.Errorf("owner, repo and number are required")
+	}
+	issueURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.Number)
+	var is struct {
+		issue
+		StateReason string       `json:"state_reason"`
+		Body        string       `json:"body"`
+		Comments    int          `json:"comments"`
+		CreatedAt   time.Time    `json:"created_at"`
+		ClosedAt    *time.Time   `json:"closed_at"`
+		User        *githubUser  `json:"user"`
+		Assignees   []githubUser `json:"assignees"`
+		Milestone   *struct {
+			Title string `json:"title"`
+		} `json:"milestone"`
+	}
+	if err := c.getJSON(ctx, issueURL, &is); err != nil {
+		return nil, IssueDetail{}, err
+	}
+	out := IssueDetail{
+		Number:        is.Number,
+		Title:         is.Title,
+		State:         is.State,
+		StateReason:   is.StateReason,
+		PullRequest:   is.PullRequest != nil,
+		Author:        is.User.login(),
+		Labels:        is.labelNames(),
+		Assignees:     []string{},
+		Body:          is.Body,
+		CreatedAt:     is.CreatedAt,
+		ClosedAt:      is.ClosedAt,
+		HTMLURL:       is.HTMLURL,
+		TotalComments: is.Comments,
+		Comments:      []IssueComment{},
+	}
+	for _, a := range is.Assignees {
+		out.Assignees = append(out.Assignees, a.Login)
+	}
+	if is.Milestone != nil {
+		out.Milestone = is.Milestone.Title
+	}
+
+	// Only the pages holding the requested comments are read.
+	limit := cmp.Or(args.Limit, 50)
+	const perPage = 100
+	for page := args.Offset/perPage + 1; args.Offset < is.Comments && len(out.Comments) < limit; page++ {
+		var comments []struct {
+			ID                int64       `json:"id"`
+			Body              string      `json:"body"`
+			AuthorAssociation string      `json:"author_association"`
+			CreatedAt         time.Time   `json:"created_at"`
+			HTMLURL           string      `json:"html_url"`
+			User              *githubUser `json:"user"`
+		}
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/comments?per_page=%d&page=%d", issueURL, perPage, page), &comments); err != nil {
+			return nil, IssueDetail{}, err
+		}
+		for i, cm := range comments {
+			if (page-1)*perPage+i < args.Offset || len(out.Comments) == limit {
+				continue
+			}
+			out.Comments = append(out.Comments, IssueComment{
+				ID:                cm.ID,
+				Author:            cm.User.login(),
+				AuthorAssociation: cm.AuthorAssociation,
+				Body:              cm.Body,
+				CreatedAt:         cm.CreatedAt,
+				HTMLURL:           cm.HTMLURL,
+			})
+		}
+		if len(comments) < perPage {
+			break
+		}
+	}
+	if next := args.Offset + len(out.Comments); next < is.Comments {
+		out.NextOffset = next
+	}
+
+	var result strings.Builder
+	kind := "Issue"
+	if out.PullRequest {
+		kind = "Pull request"
+	}
+	state := out.State
+	if out.StateReason != "" && out.State == "closed" {
+		state += " as " + out.StateReason
+	}
+	fmt.Fprintf(&result, "%s %s/%s#%d: %s [%s]\n", kind, args.Owner, args.Repo, out.Number, out.Title, state)
+	fmt.Fprintf(&result, "Opened by %s on %s %s\n", out.Author, out.CreatedAt.Format(time.DateOnly), out.HTMLURL)
+	if len(out.Labels) > 0 {
+		fmt.Fprintf(&result, "Labels: %s\n", strings.Join(out.Labels, ", "))
+	}
+	if len(out.Assignees) > 0 {
+		fmt.Fprintf(&result, "Assignees: %s\n", strings.Join(out.Assignees, ", "))
+	}
+	if out.Milestone != "" {
+		fmt.Fprintf(&result, "Milestone: %s\n", out.Milestone)
+	}
+	fmt.Fprintf(&result, "\n%s\n", cmp.Or(out.Body, "No description provided."))
+	if len(out.Comments) > 0 {
+		fmt.Fprintf(&result, "\nComments %d-%d of %d:\n", args.Offset+1, args.Offset+len(out.Comments), out.TotalComments)
+	}
+	for _, cm := range out.Comments {
+		fmt.Fprintf(&result, "\n----- %s (%s), %s -----\n%s\n", cm.Author, strings.ToLower(cm.AuthorAssociation), cm.CreatedAt.Format(time.RFC3339), cm.Body)
+	}
+	if out.NextOffset != 0 {
+		fmt.Fprintf(&result, "\n[... %d more comments, call again with offset=%d for more ...]\n", out.TotalComments-out.NextOffset, out.NextOffset)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
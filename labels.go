@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// labelsInputSchema returns the input schema shared by the label tools, which
+// only differ in what they do with the labels.
+func labelsInputSchema(labelsDescription string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the issue or pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"labels": {
+				Type:        "array",
+				Description: labelsDescription,
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "number", "labels"},
+	}
+}
+
+var addLabelsTool = &mcp.Tool{
+	Name:        "add-labels",
+	Description: "A tool to add labels to an issue or pull request of a Github repository, keeping the ones it already has",
+	Annotations: writeAnnotations(false, true),
+	InputSchema: labelsInputSchema("Labels to add, missing ones are created in the repository"),
+}
+
+var removeLabelsTool = &mcp.Tool{
+	Name:        "remove-labels",
+	Description: "A tool to remove labels from an issue or pull request of a Github repository",
+	Annotations: writeAnnotations(true, true),
+	InputSchema: labelsInputSchema("Labels to remove"),
+}
+
+var setLabelsTool = &mcp.Tool{
+	Name:        "set-labels",
+	Description: "A tool to replace all the labels of an issue or pull request of a Github repository",
+	Annotations: writeAnnotations(true, true),
+	InputSchema: labelsInputSchema("The labels the issue should have, an empty list removes them all"),
+}
+
+type LabelsArgs struct {
+	CommonArgs
+	Owner  string   `json:"owner"`
+	Repo   string   `json:"repo"`
+	Number int      `json:"number"`
+	Labels []string `json:"labels"`
+}
+
+// IssueLabels are the labels an issue has after a change.
+type IssueLabels struct {
+	Number int      `json:"number"`
+	Labels []string `json:"labels"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+func (c *GithubClient) AddLabels(ctx context.Context, req *mcp.CallToolRequest, args LabelsArgs) (*mcp.CallToolResult, IssueLabels, error) {
+	return c.changeLabels(ctx, args, http.MethodPost)
+}
+
+func (c *GithubClient) SetLabels(ctx context.Context, req *mcp.CallToolRequest, args LabelsArgs) (*mcp.CallToolResult, IssueLabels, error) {
+	return c.changeLabels(ctx, args, http.MethodPut)
+}
+
+func (c *GithubClient) RemoveLabels(ctx context.Context, req *mcp.CallToolRequest, args LabelsArgs) (*mcp.CallToolResult, IssueLabels, error) {
+	return c.changeLabels(ctx, args, http.MethodDelete)
+}
+
+// changeLabels adds (POST), replaces (PUT) or removes (DELETE) the labels of
+// an issue.
+func (c *GithubClient) changeLabels(ctx context.Context, args LabelsArgs, method string) (*mcp.CallToolResult, IssueLabels, error) {
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, IssueLabels{}, fmt.Errorf("owner, repo and number are required")
+	}
+	if len(args.Labels) == 0 && method != http.MethodPut {
+		return nil, IssueLabels{}, fmt.Errorf("labels is required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/labels", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.Number)
+
+	var labels []label
+	if method == http.MethodDelete {
+		// Labels can only be removed one at a time, each response listing
+		// the remaining ones. Removing a label the issue doesn't have is
+		// not an error, the outcome is the same.
+		for _, name := range args.Labels {
+			err := c.sendJSON(ctx, method, apiURL+"/"+url.PathEscape(name), nil, &labels)
+			if isNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, IssueLabels{}, err
+			}
+		}
+		if labels == nil {
+			if err := c.getJSON(ctx, apiURL, &labels); err != nil {
+				return nil, IssueLabels{}, err
+			}
+		}
+	} else {
+		// Send [] rather than null so set-labels can clear the labels.
+		payload := map[string][]string{"labels": append([]string{}, args.Labels...)}
+		if err := c.sendJSON(ctx, method, apiURL, payload, &labels); err != nil {
+			return nil, IssueLabels{}, err
+		}
+	}
+	out := IssueLabels{Number: args.Number, Labels: []string{}}
+	for _, l := range labels {
+		out.Labels = append(out.Labels, l.Name)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Labels of %s/%s#%d: %s\n", args.Owner, args.Repo, args.Number, strings.Join(out.Labels, ", "))},
+		},
+	}, out, nil
+}
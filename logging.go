@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// newLogHandler returns the handler writing logs to w at the level and in
+// the format of the configuration, both validated by loadConfig.
+func newLogHandler(w io.Writer, level, format string) slog.Handler {
+	lvl, _ := parseLogLevel(level)
+	opts := &slog.HandlerOptions{Level: lvl}
+	if format == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unknown log_level %q, expected debug, info, warn or error", level)
+	}
+	return lvl, nil
+}
+
+// redactions are the credentials kept out of the wire log: Authorization
+// headers, in the JSON of logged HTTP requests or as header lines, and
+// anything looking like a GitHub token.
+var redactions = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)("(?:proxy-)?authorization"\s*:\s*\[?\s*")[^"]*(")`), "${1}[REDACTED]${2}"},
+	{regexp.MustCompile(`(?im)^((?:proxy-)?authorization:[ \t]*)\S.*$`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`), "[REDACTED]"},
+}
+
+// redactingWriter writes the wire log, one frame per Write, with the
+// credentials it holds replaced.
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	out := p
+	for _, red := range redactions {
+		out = red.re.ReplaceAll(out, []byte(red.repl))
+	}
+	if _, err := r.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rotatingFile is a log file renamed to path.1, and so on up to backups
+// older files, once it grows past maxBytes.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	backups  int
+	f        *os.File
+	size     int64
+}
+
+func openRotatingFile(path string, maxBytes int64, backups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	if r.backups == 0 {
+		if err := os.Remove(r.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return r.open()
+	}
+	for i := r.backups - 1; i > 0; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+// wireLogHandler logs the requests reaching next, with their headers, and
+// the responses it streams back, as LoggingTransport does for stdio.
+func wireLogHandler(next http.Handler, w io.Writer) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		headers, _ := json.Marshal(r.Header)
+		fmt.Fprintf(w, "read: %s %s %s %s\n", r.Method, r.URL.RequestURI(), headers, bytes.TrimSpace(body))
+		next.ServeHTTP(&wireLogResponseWriter{ResponseWriter: rw, w: w}, r)
+	})
+}
+
+type wireLogResponseWriter struct {
+	http.ResponseWriter
+	w io.Writer
+}
+
+func (rw *wireLogResponseWriter) Write(p []byte) (int, error) {
+	if data := bytes.TrimSpace(p); len(data) > 0 {
+		fmt.Fprintf(rw.w, "write: %s\n", data)
+	}
+	return rw.ResponseWriter.Write(p)
+}
+
+// Flush keeps the event streams of the Streamable HTTP transport flowing.
+func (rw *wireLogResponseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rw *wireLogResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
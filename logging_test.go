@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	token := "ghp_" + strings.Repeat("a", 36)
+	tests := []struct {
+		name, in, want string
+	}{
+		{"json header", `{"Authorization":["Bearer secret"],"Accept":["*/*"]}`, `{"Authorization":["[REDACTED]"],"Accept":["*/*"]}`},
+		{"header line", "GET / HTTP/1.1\nproxy-authorization: Basic c2VjcmV0\nAccept: */*", "GET / HTTP/1.1\nproxy-authorization: [REDACTED]\nAccept: */*"},
+		{"token", `{"token":"` + token + `"}`, `{"token":"[REDACTED]"}`},
+		{"fine-grained token", "github_pat_" + strings.Repeat("B", 30) + " used", "[REDACTED] used"},
+		{"nothing to hide", `{"method":"tools/call","params":{"name":"list-issues"}}`, `{"method":"tools/call","params":{"name":"list-issues"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := (&redactingWriter{w: &buf}).Write([]byte(tt.in))
+			if err != nil || n != len(tt.in) {
+				t.Fatalf("Write = %d, %v, want %d, nil", n, err, len(tt.in))
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wire.log")
+	r, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A line longer than the limit still goes to a file of its own.
+	for _, line := range []string{"first\n", "second\n", "third line\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"": "fourth\n", ".1": "third line\n", ".2": "second\n"} {
+		data, err := os.ReadFile(path + name)
+		if err != nil || string(data) != want {
+			t.Errorf("wire.log%s = %q, %v, want %q", name, data, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("more backups than asked for: %v", err)
+	}
+
+	// Reopening appends and counts what is already there.
+	r, err = openRotatingFile(path, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := r.Write([]byte("fifth\n")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "fifth\n" {
+		t.Errorf("without backups wire.log = %q, want it truncated", data)
+	}
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "third line\n" {
+		t.Errorf("without backups wire.log.1 = %q, want it untouched", data)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zalando/go-keyring"
+	"gopkg.in/yaml.v3"
+)
+
+// login runs the GitHub OAuth device flow: it prints a code for the user to
+// enter in their browser, waits for them to authorize the app and stores the
+// resulting token for later runs. When a token is already configured it is
+// stored as is.
+func login(ctx context.Context, cfg *Config) error {
+	host := githubHost(cfg.GithubBaseURL)
+	if cfg.GithubToken != "" {
+		// An existing token only needs to be stored.
+		if err := storeToken(host, cfg.GithubToken); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Stored the token for %s\n", host)
+		return nil
+	}
+	if cfg.OAuthClientID == "" {
+		return fmt.Errorf("oauth_client_id is required to log in, register an OAuth App with device flow enabled")
+	}
+	webURL, err := githubWebURL(cfg.GithubBaseURL)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	var code struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	err = postForm(ctx, client, webURL+"/login/device/code", url.Values{
+		"client_id": {cfg.OAuthClientID},
+		"scope":     {cfg.OAuthScopes},
+	}, &code)
+	if err != nil {
+		return fmt.Errorf("requesting device code: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Open %s and enter the code %s\n", code.VerificationURI, code.UserCode)
+
+	interval := time.Duration(max(code.Interval, 5)) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		var token struct {
+			AccessToken      string `json:"access_token"`
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+			Interval         int    `json:"interval"`
+		}
+		err := postForm(ctx, client, webURL+"/login/oauth/access_token", url.Values{
+			"client_id":   {cfg.OAuthClientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		}, &token)
+		if err != nil {
+			return fmt.Errorf("polling for token: %w", err)
+		}
+		switch token.Error {
+		case "":
+			if err := storeToken(host, token.AccessToken); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Logged in to %s\n", host)
+			return nil
+		case "authorization_pending":
+		case "slow_down":
+			interval = slowDown(interval, token.Interval)
+		default:
+			return fmt.Errorf("login failed: %s", cmp.Or(token.ErrorDescription, token.Error))
+		}
+	}
+	return fmt.Errorf("login failed: the code expired before it was entered")
+}
+
+// slowDown returns the polling interval after a slow_down error, which
+// RFC 8628 makes 5 seconds longer, or the interval the server asked for
+// when it is longer still.
+func slowDown(interval time.Duration, requested int) time.Duration {
+	return max(interval+5*time.Second, time.Duration(requested)*time.Second)
+}
+
+// postForm posts form to rawURL and decodes the JSON response into v.
+func postForm(ctx context.Context, client *http.Client, rawURL string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// githubHost returns the host name of the GitHub instance whose API is served
+// at baseURL, e.g. github.com for https://api.github.com.
+func githubHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+	if u.Host == "api.github.com" {
+		return "github.com"
+	}
+	return u.Host
+}
+
+// githubWebURL returns the web root of the GitHub instance whose API is
+// served at baseURL.
+func githubWebURL(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid GitHub base URL %q", baseURL)
+	}
+	return u.Scheme + "://" + githubHost(baseURL), nil
+}
+
+// keyringService is the name tokens are stored under in the system keyring.
+const keyringService = "magnet"
+
+// storedCredentials is the file tokens obtained with --login are kept in
+// when the system keyring is unavailable.
+type storedCredentials struct {
+	Hosts map[string]hostCredentials `yaml:"hosts"`
+}
+
+type hostCredentials struct {
+	Token string `yaml:"token"`
+}
+
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "magnet", "credentials.yaml"), nil
+}
+
+func readCredentials() (*storedCredentials, error) {
+	creds := &storedCredentials{}
+	path, err := credentialsPath()
+	if err != nil {
+		return creds, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return creds, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials: %w", err)
+	}
+	if err := yaml.Unmarshal(data, creds); err != nil {
+		return nil, fmt.Errorf("parsing credentials %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// storedToken returns the token saved by --login for host, or "". The
+// system keyring is tried before the credentials file.
+func storedToken(host string) (string, error) {
+	if token, err := keyring.Get(keyringService, host); err == nil {
+		return token, nil
+	}
+	creds, err := readCredentials()
+	if err != nil {
+		return "", err
+	}
+	return creds.Hosts[host].Token, nil
+}
+
+// storeToken saves token for host in the system keyring (macOS Keychain,
+// Windows Credential Manager or the Secret Service on Linux). Without one it
+// falls back to the credentials file, readable only by the current user.
+func storeToken(host, token string) error {
+	err := keyring.Set(keyringService, host, token)
+	if err == nil {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "System keyring unavailable (%v), storing the token in a file instead\n", err)
+
+	creds, err := readCredentials()
+	if err != nil {
+		return err
+	}
+	if creds.Hosts == nil {
+		creds.Hosts = map[string]hostCredentials{}
+	}
+	entry := creds.Hosts[host]
+	entry.Token = token
+	creds.Hosts[host] = entry
+
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+	return nil
+}
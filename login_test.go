@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowDown(t *testing.T) {
+	for _, tt := range []struct {
+		interval  time.Duration
+		requested int
+		want      time.Duration
+	}{
+		{5 * time.Second, 0, 10 * time.Second},
+		{5 * time.Second, 7, 10 * time.Second},
+		{5 * time.Second, 30, 30 * time.Second},
+		{10 * time.Second, 0, 15 * time.Second},
+	} {
+		if got := slowDown(tt.interval, tt.requested); got != tt.want {
+			t.Errorf("slowDown(%v, %d) = %v, want %v", tt.interval, tt.requested, got, tt.want)
+		}
+	}
+}
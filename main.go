@@ -1,129 +1,539 @@
 package main
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func run() error {
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		return err
+	}
+	// Logs never go to stdout, which carries the stdio transport.
+	logOutput := io.Writer(os.Stderr)
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("opening log file: %w", err)
+		}
+		defer f.Close()
+		logOutput = f
+	}
+	slog.SetDefault(slog.New(newLogHandler(logOutput, cfg.LogLevel, cfg.LogFormat)))
+	var wireLog io.Writer
+	if cfg.WireLog {
+		wireLog = logOutput
+		if cfg.WireLogFile != "" {
+			f, err := openRotatingFile(cfg.WireLogFile, int64(cfg.WireLogMaxBytes), cfg.WireLogBackups)
+			if err != nil {
+				return fmt.Errorf("opening wire log file: %w", err)
+			}
+			defer f.Close()
+			wireLog = f
+		}
+		wireLog = &redactingWriter{w: wireLog}
+	}
+	var audit *auditLog
+	if cfg.AuditLogFile != "" {
+		if audit, err = openAuditLog(cfg.AuditLogFile); err != nil {
+			return fmt.Errorf("opening audit log: %w", err)
+		}
+		defer audit.Close()
+	}
+
+	if cfg.Login {
+		return login(context.Background(), cfg)
+	}
+
+	var etags etagStore
+	if cfg.CacheFile != "" {
+		store, err := openDiskETagStore(cfg.CacheFile, int64(cfg.CacheMaxBytes), cfg.CacheEviction)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		etags = store
+	}
+
+	var auth tokenSource
+	if cfg.GithubAppID != 0 {
+		key, err := os.ReadFile(cfg.GithubAppPrivateKeyFile)
+		if err != nil {
+			return fmt.Errorf("reading GitHub App private key: %w", err)
+		}
+		auth, err = newAppTokenSource(int64(cfg.GithubAppID), int64(cfg.GithubAppInstallationID), key, cfg.GithubBaseURL)
+		if err != nil {
+			return err
+		}
+	} else if auth, err = tokenFor(cfg.GithubToken, cfg.GithubBaseURL); err != nil {
+		return err
+	}
+	profiles := map[string]ClientProfile{}
+	for name, p := range cfg.Profiles {
+		baseURL := cmp.Or(p.GithubBaseURL, githubAPIURL)
+		profileAuth, err := tokenFor(p.GithubToken, baseURL)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		profiles[name] = ClientProfile{BaseURL: baseURL, TokenSource: profileAuth}
+	}
+
+	metrics := newServerMetrics()
+	tracer, err := newTracerFromEnv()
+	if err != nil {
+		return err
+	}
+	defer tracer.shutdown()
+	gh := NewGithubClient(&GithubClientOptions{
+		BaseURL:         cfg.GithubBaseURL,
+		TokenSource:     auth,
+		Timeout:         cfg.Timeout,
+		PerPage:         cfg.PerPage,
+		MaxPages:        cfg.MaxPages,
+		MaxRetries:      cfg.MaxRetries,
+		RetryBaseDelay:  cfg.RetryBaseDelay,
+		RetryMaxDelay:   cfg.RetryMaxDelay,
+		CacheTTL:        cfg.CacheTTL,
+		CacheSize:       cfg.CacheSize,
+		ETagStore:       etags,
+		Profiles:        profiles,
+		ReadOnly:        cfg.ReadOnly,
+		DryRun:          cfg.DryRun,
+		Allow:           cfg.Allow,
+		Deny:            cfg.Deny,
+		GraphQL:         cfg.GithubAPI == "graphql",
+		ScratchDir:      cfg.ScratchDir,
+		ScratchMaxBytes: int64(cfg.ScratchMaxBytes),
+		CloneDir:        cfg.CloneDir,
+		CloneMaxBytes:   int64(cfg.CloneMaxBytes),
+		Metrics:         metrics,
+		Tracer:          tracer,
+	})
+	gh.addGitlab(cfg.GitlabBaseURL, cfg.GitlabToken)
+	gh.addBitbucket(cfg.BitbucketUsername, cfg.BitbucketToken)
+	gh.addGitea(cfg.GiteaBaseURL, cfg.GiteaToken)
+	gh.addAzureDevOps(cfg.AzureDevOpsBaseURL, cfg.AzureDevOpsToken)
+	// The admin endpoint answers the probes while the server starts, not
+	// ready until it is set up.
+	if cfg.AdminAddr != "" {
+		go serveAdmin(cfg.AdminAddr, metrics, cfg.AdminDebug, gh)
+	}
+	if err := gh.ValidateToken(context.Background()); err != nil {
+		return fmt.Errorf("validating GitHub token: %w", err)
+	}
+	if auth == nil {
+		slog.Warn("No GitHub token configured, only public data is available and rate limits are low")
+	}
+
+	opts := &mcp.ServerOptions{}
+	if cfg.SubscriptionPollInterval > 0 {
+		opts = gh.subscriptionOptions()
+		go gh.watchSubscriptions(context.Background(), cfg.SubscriptionPollInterval)
+	}
+	switch {
+	case cfg.DryRun:
+		opts.Instructions = "This server runs in dry-run mode: the tools that would create or change data on GitHub only report the requests they would send."
+	case cfg.ReadOnly:
+		opts.Instructions = "This server is read-only: its tools can read data from GitHub but not create or change any."
+	}
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "demo-github-mcp",
 		Title:   "A demo github mcp server",
 		Version: "0.0.1",
-	}, nil)
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list-repositories",
-		Description: "A tool to list all repositories in a Github org",
-		InputSchema: &jsonschema.Schema{
-			Type: "object",
-			Properties: map[string]*jsonschema.Schema{
-				"name": {
-					Type:        "string",
-					Description: "GitHub organization name (e.g., kubernetes)",
-				},
-				"url": {
-					Type:        "string",
-					Description: "GitHub organization URL (e.g., https://github.com/kubernetes)",
-				},
-			},
-		},
-	}, ListRepositories)
-	t := mcp.NewLoggingTransport(mcp.NewStdioTransport(), os.Stderr)
-	log.Println("🚀 MCP server starting up...")
+	}, opts)
+	server.AddReceivingMiddleware(metrics.middleware, tracer.middleware, audit.middleware)
+	addTool(server, listRepositoriesTool, gh.ListRepositories)
+	addTool(server, listIssuesTool, gh.ListIssues)
+	addTool(server, listPullRequestsTool, gh.ListPullRequests)
+	addTool(server, getRepositoryTool, gh.GetRepository)
+	addTool(server, getMultipleRepositoriesTool, gh.GetMultipleRepositories)
+	addTool(server, getFileContentsTool, gh.GetFileContents)
+	addTool(server, searchCodeTool, gh.SearchCode)
+	addTool(server, findDependencyUsageTool, gh.FindDependencyUsage)
+	addTool(server, listBranchesTool, gh.ListBranches)
+	addTool(server, getBranchProtectionTool, gh.GetBranchProtection)
+	addTool(server, getTrafficTool, gh.GetTraffic)
+	addTool(server, listCommitsTool, gh.ListCommits)
+	addTool(server, compareRefsTool, gh.CompareRefs)
+	addTool(server, listReleasesTool, gh.ListReleases)
+	addTool(server, getLatestReleaseTool, gh.GetLatestRelease)
+	addTool(server, changelogTool, gh.Changelog)
+	addTool(server, listContributorsTool, gh.ListContributors)
+	addTool(server, orgSummaryTool, gh.OrgSummary)
+	addTool(server, auditCommunityProfilesTool, gh.AuditCommunityProfiles)
+	addTool(server, contributorActivityTool, gh.ContributorActivity)
+	addTool(server, listWorkflowRunsTool, gh.ListWorkflowRuns)
+	addTool(server, getWorkflowRunLogsTool, gh.GetWorkflowRunLogs)
+	addTool(server, analyzeCIFailuresTool, gh.AnalyzeCIFailures)
+	addTool(server, searchRepositoriesTool, gh.SearchRepositories)
+	addTool(server, searchIssuesTool, gh.SearchIssues)
+	addTool(server, findStaleTool, gh.FindStale)
+	addTool(server, findGoodFirstIssuesTool, gh.FindGoodFirstIssues)
+	addTool(server, reviewQueueTool, gh.ReviewQueue)
+	addTool(server, suggestReviewersTool, gh.SuggestReviewers)
+	addTool(server, exportSBOMTool, gh.ExportSBOM)
+	addTool(server, listDependabotAlertsTool, gh.ListDependabotAlerts)
+	addTool(server, listCodeScanningAlertsTool, gh.ListCodeScanningAlerts)
+	addTool(server, listSecretScanningAlertsTool, gh.ListSecretScanningAlerts)
+	addTool(server, getReadmeTool, gh.GetReadme)
+	addTool(server, listDirectoryTool, gh.ListDirectory)
+	if cfg.DryRun {
+		slog.Info("Dry-run mode, the tools that change data on GitHub only report what they would do")
+	}
+	if cfg.ReadOnly && !cfg.DryRun {
+		slog.Info("Read-only mode, the tools that change data on GitHub are disabled")
+	} else {
+		writeTool := func(t *mcp.Tool) *mcp.Tool { return t }
+		if cfg.DryRun {
+			writeTool = func(t *mcp.Tool) *mcp.Tool { return dryRunTool(t, cfg.ReadOnly) }
+		}
+		addTool(server, writeTool(createIssueTool), gh.CreateIssue)
+		addTool(server, writeTool(commentOnIssueTool), gh.CommentOnIssue)
+		addTool(server, writeTool(createPullRequestTool), gh.CreatePullRequest)
+		addTool(server, writeTool(mergePullRequestTool), gh.MergePullRequest)
+		addTool(server, writeTool(createOrUpdateFileTool), gh.CreateOrUpdateFile)
+		addTool(server, writeTool(createReleaseTool), gh.CreateRelease)
+		addTool(server, writeTool(addLabelsTool), gh.AddLabels)
+		addTool(server, writeTool(removeLabelsTool), gh.RemoveLabels)
+		addTool(server, writeTool(setLabelsTool), gh.SetLabels)
+		addTool(server, writeTool(dispatchWorkflowTool), gh.DispatchWorkflow)
+		addTool(server, writeTool(rerunWorkflowRunTool), gh.RerunWorkflowRun)
+		addTool(server, writeTool(markNotificationsReadTool), gh.MarkNotificationsRead)
+		addTool(server, writeTool(createGistTool), gh.CreateGist)
+	}
+	mcp.AddTool(server, cacheStatsTool, gh.CacheStats)
+	addTool(server, rateLimitStatusTool, gh.RateLimitStatus)
+	addTool(server, whoamiTool, gh.Whoami)
+	addTool(server, listNotificationsTool, gh.ListNotifications)
+	addTool(server, listGistsTool, gh.ListGists)
+	addTool(server, getGistTool, gh.GetGist)
+	addTool(server, listOrgMembersTool, gh.ListOrgMembers)
+	addTool(server, listTeamsTool, gh.ListTeams)
+	addTool(server, listTeamReposTool, gh.ListTeamRepos)
+	addTool(server, getCommitChecksTool, gh.GetCommitChecks)
+	addTool(server, listPullRequestFilesTool, gh.ListPullRequestFiles)
+	addTool(server, getPullRequestDiffTool, gh.GetPullRequestDiff)
+	addTool(server, getPullRequestReviewsTool, gh.GetPullRequestReviews)
+	addTool(server, getIssueTool, gh.GetIssue)
+	addTool(server, listDiscussionCategoriesTool, gh.ListDiscussionCategories)
+	addTool(server, listDiscussionsTool, gh.ListDiscussions)
+	addTool(server, getDiscussionTool, gh.GetDiscussion)
+	addTool(server, listMilestonesTool, gh.ListMilestones)
+	addTool(server, listProjectsTool, gh.ListProjects)
+	addTool(server, getProjectItemsTool, gh.GetProjectItems)
+	addTool(server, downloadArchiveTool, gh.DownloadArchive)
+	addTool(server, cloneRepositoryTool, gh.CloneRepository)
+	addTool(server, gitLogTool, gh.GitLog)
+	addTool(server, gitBlameTool, gh.GitBlame)
+	addTool(server, gitDiffTool, gh.GitDiff)
+	addTool(server, getCodeStatsTool, gh.GetCodeStats)
+	addTool(server, grepRepositoryTool, gh.GrepRepository)
+	gh.registerResources(server)
+	gh.registerPrompts(server)
+	gh.started.Store(true)
+	if cfg.Transport == "http" {
+		var adminHandler http.Handler
+		if cfg.AdminAddr == "" {
+			adminHandler = metrics
+		}
+		return serveHTTP(server, gh, cfg.HTTPAddr, wireLog, adminHandler)
+	}
+	var t mcp.Transport = &mcp.StdioTransport{}
+	if wireLog != nil {
+		t = &mcp.LoggingTransport{Transport: t, Writer: wireLog}
+	}
+	slog.Info("MCP server starting up", "transport", "stdio")
 	if err := server.Run(context.Background(), t); err != nil {
-		log.Printf("Server failed: %v", err)
+		slog.Error("Server failed", "err", err)
 	}
-	log.Println("🚀 MCP server shutting down...")
+	slog.Info("MCP server shutting down")
 	return nil
 }
 
-// User can pass in either the name of the org (example: kubernetes), or its URL (example: https://github.com/kubernetes)
-type GithubOrgArgs struct {
-	Name string
-	URL  string
+// tokenFor returns the credentials for the GitHub API at baseURL: token when
+// it is set, otherwise the token stored by --login or the gh CLI for that
+// host. It returns nil when there are none.
+func tokenFor(token, baseURL string) (tokenSource, error) {
+	if token != "" {
+		return staticToken(token), nil
+	}
+	host := githubHost(baseURL)
+	token, err := storedToken(host)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		if token, err = ghCLIToken(host); err != nil {
+			return nil, err
+		}
+		if token != "" {
+			slog.Info("Using the gh CLI credentials", "host", host)
+		}
+	}
+	if token == "" {
+		return nil, nil
+	}
+	return staticToken(token), nil
 }
 
-func ListRepositories(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GithubOrgArgs]) (*mcp.CallToolResultFor[struct{}], error) {
-	if params == nil {
-		return nil, fmt.Errorf("empty params")
+// serveHTTP serves the MCP Streamable HTTP transport on addr. Every client
+// session is handled by the same server, so they all share its tools. The
+// exchanged messages are logged to wireLog unless it is nil. metrics, when
+// set, is served at /metrics, and the probes of gh at /healthz and /readyz.
+func serveHTTP(server *mcp.Server, gh *GithubClient, addr string, wireLog io.Writer, metrics http.Handler) error {
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return server
+	}, nil)
+	if wireLog != nil {
+		handler = wireLogHandler(handler, wireLog)
 	}
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	if metrics != nil {
+		mux.Handle("GET /metrics", metrics)
+	}
+	addProbes(mux, gh)
+	slog.Info("MCP server listening", "transport", "http", "url", "http://"+addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+var listRepositoriesTool = &mcp.Tool{
+	Name:        "list-repositories",
+	Description: "A tool to list all repositories of a Github organization or user",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"name": {
+				Type:        "string",
+				Description: "GitHub organization or user name (e.g., kubernetes)",
+			},
+			"url": {
+				Type:        "string",
+				Description: "Organization, group or user URL, on GitHub or another provider (e.g., https://github.com/kubernetes or https://gitlab.com/gitlab-org)",
+			},
+			"account_type": {
+				Type:        "string",
+				Description: "Whether the account is an organization or a user (detected when omitted)",
+				Enum:        []any{"org", "user"},
+			},
+			"type": {
+				Type:        "string",
+				Description: "Only return repositories of this type (defaults to all). owner is only valid for users; public, private, forks and sources only for organizations",
+				Enum:        []any{"all", "owner", "public", "private", "forks", "sources", "member"},
+			},
+			"sort": {
+				Type:        "string",
+				Description: "Sort field (defaults to created)",
+				Enum:        []any{"created", "updated", "pushed", "full_name"},
+			},
+			"direction": {
+				Type:        "string",
+				Description: "Sort direction (defaults to asc for full_name, desc otherwise)",
+				Enum:        []any{"asc", "desc"},
+			},
+			"name_pattern": {
+				Type:        "string",
+				Description: "Only return repositories whose name matches this case-insensitive glob pattern (e.g., kube*)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"provider": providerProperty(),
+		},
+	},
+}
 
-	args := params.Arguments
+// User can pass in either the name of the org or user (example: kubernetes), or its URL (example: https://github.com/kubernetes)
+type GithubOrgArgs struct {
+	CommonArgs
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url,omitempty"`
+	AccountType string `json:"account_type,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Sort        string `json:"sort,omitempty"`
+	Direction   string `json:"direction,omitempty"`
+	NamePattern string `json:"name_pattern,omitempty"`
+}
+
+// Repository is a single entry of the list-repositories output.
+type Repository struct {
+	Name            string    `json:"name"`
+	FullName        string    `json:"full_name"`
+	HTMLURL         string    `json:"html_url"`
+	Private         bool      `json:"private"`
+	Description     string    `json:"description"`
+	Language        string    `json:"language"`
+	StargazersCount int       `json:"stargazers_count"`
+	Fork            bool      `json:"fork"`
+	Archived        bool      `json:"archived"`
+	PushedAt        time.Time `json:"pushed_at"`
+	Topics          []string  `json:"topics,omitempty"`
+	// LatestRelease is the tag of the latest release, only looked up with
+	// the GraphQL API.
+	LatestRelease string `json:"latest_release,omitempty"`
+}
+
+// RepoListOutput is the structured result of list-repositories. Its output
+// schema is inferred by the SDK when the tool is registered.
+type RepoListOutput struct {
+	Organization string       `json:"organization"`
+	Repositories []Repository `json:"repositories"`
+}
+
+func (c *GithubClient) ListRepositories(ctx context.Context, req *mcp.CallToolRequest, args GithubOrgArgs) (*mcp.CallToolResult, RepoListOutput, error) {
+	if args.URL == "" {
+		err := elicitMissing(ctx, req, "Which GitHub organization or user should the repositories be listed for?",
+			elicitField{name: "name", description: "GitHub organization or user name (e.g., kubernetes)", value: &args.Name})
+		if err != nil {
+			return nil, RepoListOutput{}, err
+		}
+	}
 	if args.Name == "" && args.URL == "" {
-		return nil, fmt.Errorf("empty args")
+		return nil, RepoListOutput{}, fmt.Errorf("empty args")
 	}
-	var apiURL string
-	var organization string
+	organization := args.Name
 	if args.URL != "" {
-		// If URL is provided, extract org name and build API URL
-		url := strings.TrimPrefix(args.URL, "https://")
-		url = strings.TrimPrefix(url, "http://")
-		url = strings.TrimPrefix(url, "github.com/")
-		url = strings.TrimSuffix(url, "/")
-
-		orgName := strings.Split(url, "/")[0]
-		apiURL = fmt.Sprintf("https://api.github.com/orgs/%s/repos", orgName)
-		organization = orgName
-	} else {
-		// Use the provided organization name
-		apiURL = fmt.Sprintf("https://api.github.com/orgs/%s/repos", args.Name)
-		organization = args.Name
-	}
-	// apiURL = fmt.Sprintf("%s%s", apiURL, "?per_page=100")
-	apiURL = apiURL + "?per_page=100"
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, err
+		// If URL is provided, extract the account from it, and the provider
+		// from its host
+		var err error
+		organization, err = c.accountFromURL(args.URL, &args.CommonArgs)
+		if err != nil {
+			return nil, RepoListOutput{}, err
+		}
 	}
 
-	req.Header.Add("Accept", "application/vnd.github.v3+json")
-
-	resp, err := client.Do(req)
+	pattern := strings.ToLower(args.NamePattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, RepoListOutput{}, fmt.Errorf("invalid name_pattern: %w", err)
+	}
+	forge, err := c.forge(args.CommonArgs)
 	if err != nil {
-		return nil, err
+		return nil, RepoListOutput{}, err
 	}
-
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	repositories, err := forge.ListRepos(ctx, organization, RepoListOptions{
+		AccountType: args.AccountType,
+		Type:        args.Type,
+		Sort:        args.Sort,
+		Direction:   args.Direction,
+	})
+	if err != nil {
+		return nil, RepoListOutput{}, err
 	}
-	type repository struct {
-		Name     string `json:"name"`
-		FullName string `json:"full_name"`
-		HTMLURL  string `json:"html_url"`
-		Private  bool   `json:"private"`
+	if pattern != "" {
+		repositories = slices.DeleteFunc(repositories, func(r Repository) bool {
+			ok, _ := path.Match(pattern, strings.ToLower(r.Name))
+			return !ok
+		})
 	}
-
-	// Parse the JSON response
-	var repositories []repository
-	if err := json.NewDecoder(resp.Body).Decode(&repositories); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	// The repository resources are read from GitHub.
+	if _, ok := forge.(*githubForge); ok {
+		discovered := map[string]string{}
+		for _, repo := range repositories {
+			discovered[repo.FullName] = repo.Description
+		}
+		c.addRepositoryResources(args.CommonArgs, discovered)
 	}
+
 	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Repositories for organization %s:", organization))
+	fmt.Fprintf(&result, "Repositories for %s:\n", organization)
 	for _, repo := range repositories {
-		result.WriteString(fmt.Sprintf("Name: %s, URL: %s", repo.Name, repo.HTMLURL))
+		fmt.Fprintf(&result, "Name: %s, URL: %s, Language: %s, Stars: %d, Last push: %s%s\n",
+			repo.Name, repo.HTMLURL, repo.Language, repo.StargazersCount, repo.PushedAt.Format(time.DateOnly), repositoryFlags(repo))
+		if repo.Description != "" {
+			fmt.Fprintf(&result, "  %s\n", repo.Description)
+		}
+		if len(repo.Topics) > 0 {
+			fmt.Fprintf(&result, "  Topics: %s\n", strings.Join(repo.Topics, ", "))
+		}
+		if repo.LatestRelease != "" {
+			fmt.Fprintf(&result, "  Latest release: %s\n", repo.LatestRelease)
+		}
 	}
 
-	return &mcp.CallToolResultFor[struct{}]{
+	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: result.String()},
 		},
+	}, RepoListOutput{
+		Organization: organization,
+		Repositories: repositories,
 	}, nil
 }
 
+func repositoryFlags(r Repository) string {
+	var flags []string
+	if r.Private {
+		flags = append(flags, "private")
+	}
+	if r.Fork {
+		flags = append(flags, "fork")
+	}
+	if r.Archived {
+		flags = append(flags, "archived")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(flags, ", ") + ")"
+}
+
+// accountRepositories lists every repository of an organization or user.
+// accountType is either "org" or "user"; when empty it is looked up. query
+// holds optional filters understood by the API, it may be nil.
+func (c *GithubClient) accountRepositories(ctx context.Context, baseURL, account, accountType string, query url.Values) ([]Repository, error) {
+	if accountType == "" {
+		var err error
+		if accountType, err = c.accountType(ctx, baseURL, account); err != nil {
+			return nil, err
+		}
+	}
+	collection := "orgs"
+	if accountType == "user" {
+		collection = "users"
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("per_page", strconv.Itoa(c.perPage))
+	apiURL := fmt.Sprintf("%s/%s/%s/repos?%s", baseURL, collection, url.PathEscape(account), q.Encode())
+	repos, err := getAllPages[Repository](ctx, c, apiURL)
+	return c.scope.filterRepositories(repos), err
+}
+
+// accountType reports whether account is an "org" or a "user".
+func (c *GithubClient) accountType(ctx context.Context, baseURL, account string) (string, error) {
+	var user struct {
+		Type string `json:"type"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/users/%s", baseURL, url.PathEscape(account)), &user); err != nil {
+		return "", err
+	}
+	if user.Type == "Organization" {
+		return "org", nil
+	}
+	return "user", nil
+}
+
 func main() {
-	log.Fatal(run())
+	if err := run(); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// unknownTool is the tool label of the calls of tools the server doesn't
+// have, so clients can't add series by calling made-up names.
+const unknownTool = "unknown"
+
+// latencyBuckets are the upper bounds, in seconds, of the latency
+// histograms.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// serverMetrics collects the metrics of the server and serves them in the
+// Prometheus text format.
+type serverMetrics struct {
+	mu sync.Mutex
+	// toolCalls counts the calls by tool and status, ok or error, and
+	// errors the failed ones by error class.
+	toolCalls    map[[2]string]float64
+	toolDuration map[string]*histogram
+	errors       map[string]float64
+	// githubRequests counts the requests sent to GitHub, retries included,
+	// by method and status code.
+	githubRequests     map[[2]string]float64
+	githubDuration     *histogram
+	rateLimitRemaining map[string]float64
+	// tools are the names of the server's tools, as of the last call of a
+	// tool missing from them.
+	tools map[string]bool
+	// cache is nil when the response cache is disabled.
+	cache *responseCache
+}
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		toolCalls:          map[[2]string]float64{},
+		toolDuration:       map[string]*histogram{},
+		errors:             map[string]float64{},
+		githubRequests:     map[[2]string]float64{},
+		githubDuration:     newHistogram(),
+		rateLimitRemaining: map[string]float64{},
+		tools:              map[string]bool{},
+	}
+}
+
+// toolCallKey is the context key of the toolCall of a tools/call request.
+type toolCallKey struct{}
+
+// toolCall is filled in by the tool handler for the middlewares observing
+// the call. The handler's error only reaches them as text in the result.
+type toolCall struct {
+	err error
+	// dryRun is set when the call only reported the requests it would
+	// send.
+	dryRun bool
+}
+
+// withToolCall returns the toolCall of ctx, adding one unless another
+// middleware already did.
+func withToolCall(ctx context.Context) (context.Context, *toolCall) {
+	if tc, ok := ctx.Value(toolCallKey{}).(*toolCall); ok {
+		return ctx, tc
+	}
+	tc := &toolCall{}
+	return context.WithValue(ctx, toolCallKey{}, tc), tc
+}
+
+// middleware records the calls of every tool, whichever way it is
+// registered.
+func (m *serverMetrics) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		tool := m.toolLabel(ctx, next, call)
+		ctx, tc := withToolCall(ctx)
+		start := time.Now()
+		res, err := next(ctx, method, req)
+		status, class := "ok", ""
+		if err != nil {
+			status, class = "error", errorClass(err)
+		} else if r, ok := res.(*mcp.CallToolResult); ok && r.IsError {
+			status, class = "error", errorClass(tc.err)
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.toolCalls[[2]string{tool, status}]++
+		if m.toolDuration[tool] == nil {
+			m.toolDuration[tool] = newHistogram()
+		}
+		m.toolDuration[tool].observe(time.Since(start).Seconds())
+		if class != "" {
+			m.errors[class]++
+		}
+		return res, err
+	}
+}
+
+// toolLabel returns the tool label of call: the name of the tool when the
+// server has it, unknownTool otherwise. Names not seen before are looked up
+// in the tool list of the server, through next.
+func (m *serverMetrics) toolLabel(ctx context.Context, next mcp.MethodHandler, call *mcp.CallToolRequest) string {
+	name := call.Params.Name
+	m.mu.Lock()
+	known := m.tools[name]
+	m.mu.Unlock()
+	if known {
+		return name
+	}
+
+	tools := map[string]bool{}
+	params := &mcp.ListToolsParams{}
+	for {
+		res, err := next(ctx, "tools/list", &mcp.ListToolsRequest{Session: call.Session, Params: params})
+		list, ok := res.(*mcp.ListToolsResult)
+		if err != nil || !ok {
+			return unknownTool
+		}
+		for _, t := range list.Tools {
+			tools[t.Name] = true
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		params = &mcp.ListToolsParams{Cursor: list.NextCursor}
+	}
+	m.mu.Lock()
+	m.tools = tools
+	m.mu.Unlock()
+	if !tools[name] {
+		return unknownTool
+	}
+	return name
+}
+
+// errorClass sorts the errors of tool calls into a few kinds worth alerting
+// on differently.
+func errorClass(err error) string {
+	var apiErr *apiError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		// The tool reported the failure itself, like a missing argument.
+		return "tool"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, errReadOnly):
+		return "read_only"
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests,
+			apiErr.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(apiErr.Body), "rate limit"):
+			return "rate_limited"
+		case apiErr.StatusCode == http.StatusUnauthorized:
+			return "unauthorized"
+		case apiErr.StatusCode == http.StatusForbidden:
+			return "forbidden"
+		case apiErr.StatusCode == http.StatusNotFound:
+			return "not_found"
+		case apiErr.StatusCode >= 500:
+			return "github_unavailable"
+		}
+		return "github_error"
+	case errors.As(err, &netErr):
+		return "network"
+	}
+	return "other"
+}
+
+// transport records the requests sent to GitHub through base and the rate
+// limit left after them.
+func (m *serverMetrics) transport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := base.RoundTrip(req)
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.githubRequests[[2]string{req.Method, status}]++
+		m.githubDuration.observe(time.Since(start).Seconds())
+		if err == nil {
+			if remaining, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Remaining"), 64); err == nil {
+				m.rateLimitRemaining[cmp.Or(resp.Header.Get("X-RateLimit-Resource"), "core")] = remaining
+			}
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func (m *serverMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.writeTo(w)
+}
+
+func (m *serverMetrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	header(w, "magnet_tool_calls_total", "counter", "Tool calls by tool and status.")
+	for _, k := range sortedKeys(m.toolCalls) {
+		fmt.Fprintf(w, "magnet_tool_calls_total{tool=%s,status=%s} %g\n", quote(k[0]), quote(k[1]), m.toolCalls[k])
+	}
+	header(w, "magnet_tool_call_duration_seconds", "histogram", "Duration of the tool calls.")
+	for _, tool := range sortedKeys(m.toolDuration) {
+		m.toolDuration[tool].writeTo(w, "magnet_tool_call_duration_seconds", "tool="+quote(tool)+",")
+	}
+	header(w, "magnet_tool_errors_total", "counter", "Failed tool calls by error class.")
+	for _, class := range sortedKeys(m.errors) {
+		fmt.Fprintf(w, "magnet_tool_errors_total{class=%s} %g\n", quote(class), m.errors[class])
+	}
+	header(w, "magnet_github_requests_total", "counter", "Requests sent to the GitHub API by method and status code, retries included.")
+	for _, k := range sortedKeys(m.githubRequests) {
+		fmt.Fprintf(w, "magnet_github_requests_total{method=%s,status=%s} %g\n", quote(k[0]), quote(k[1]), m.githubRequests[k])
+	}
+	header(w, "magnet_github_request_duration_seconds", "histogram", "Duration of the requests sent to the GitHub API.")
+	m.githubDuration.writeTo(w, "magnet_github_request_duration_seconds", "")
+	header(w, "magnet_github_rate_limit_remaining", "gauge", "Requests left in the current GitHub rate limit window, by resource.")
+	for _, resource := range sortedKeys(m.rateLimitRemaining) {
+		fmt.Fprintf(w, "magnet_github_rate_limit_remaining{resource=%s} %g\n", quote(resource), m.rateLimitRemaining[resource])
+	}
+	if m.cache != nil {
+		stats := m.cache.Stats()
+		header(w, "magnet_cache_hits_total", "counter", "GitHub responses served from the response cache.")
+		fmt.Fprintf(w, "magnet_cache_hits_total %d\n", stats.Hits)
+		header(w, "magnet_cache_misses_total", "counter", "GitHub responses missing from the response cache.")
+		fmt.Fprintf(w, "magnet_cache_misses_total %d\n", stats.Misses)
+		header(w, "magnet_cache_hit_ratio", "gauge", "Share of the cacheable GitHub responses served from the response cache.")
+		ratio := 0.0
+		if total := stats.Hits + stats.Misses; total > 0 {
+			ratio = float64(stats.Hits) / float64(total)
+		}
+		fmt.Fprintf(w, "magnet_cache_hit_ratio %g\n", ratio)
+	}
+}
+
+type histogram struct {
+	counts []uint64 // per bucket of latencyBuckets, not cumulative
+	sum    float64
+	count  uint64
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make([]uint64, len(latencyBuckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	if i, _ := slices.BinarySearch(latencyBuckets, v); i < len(latencyBuckets) {
+		h.counts[i]++
+	}
+	h.sum += v
+	h.count++
+}
+
+// writeTo writes the series of the histogram, labels being the other labels
+// of the series followed by a comma.
+func (h *histogram) writeTo(w io.Writer, name, labels string) {
+	var cumulative uint64
+	for i, le := range latencyBuckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", name, labels, le, cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, labels, h.count)
+	labels = strings.TrimSuffix(labels, ",")
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s_sum%s %g\n", name, labels, h.sum)
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quote returns a label value escaped as the Prometheus text format wants.
+func quote(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+func sortedKeys[K interface{ string | [2]string }, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b K) int { return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)) })
+	return keys
+}
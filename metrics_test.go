@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// scrape returns the exposition of m as served at /metrics.
+func scrape(t *testing.T, m *serverMetrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func TestMetricsToolCalls(t *testing.T) {
+	m := newServerMetrics()
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0"}, nil)
+	server.AddReceivingMiddleware(m.middleware)
+	type echoArgs struct {
+		Fail bool `json:"fail,omitempty"`
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(ctx context.Context, req *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		if args.Fail {
+			return nil, nil, errors.New("asked to fail")
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil, nil
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	for _, params := range []*mcp.CallToolParams{
+		{Name: "echo"},
+		{Name: "echo"},
+		{Name: "echo", Arguments: map[string]any{"fail": true}},
+		{Name: "made-up-1"},
+		{Name: "made-up-2"},
+	} {
+		cs.CallTool(ctx, params)
+	}
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		"# TYPE magnet_tool_calls_total counter\n",
+		`magnet_tool_calls_total{tool="echo",status="ok"} 2` + "\n",
+		`magnet_tool_calls_total{tool="echo",status="error"} 1` + "\n",
+		`magnet_tool_calls_total{tool="unknown",status="error"} 2` + "\n",
+		"# TYPE magnet_tool_call_duration_seconds histogram\n",
+		`magnet_tool_call_duration_seconds_bucket{tool="echo",le="60"} 3` + "\n",
+		`magnet_tool_call_duration_seconds_bucket{tool="echo",le="+Inf"} 3` + "\n",
+		`magnet_tool_call_duration_seconds_count{tool="echo"} 3` + "\n",
+		`magnet_tool_call_duration_seconds_count{tool="unknown"} 2` + "\n",
+		`magnet_tool_errors_total{class="tool"} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics lack %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "made-up") {
+		t.Errorf("metrics have series of tools the server lacks:\n%s", out)
+	}
+}
+
+func TestMetricsHistogramBuckets(t *testing.T) {
+	m := newServerMetrics()
+	for _, v := range []float64{0.003, 0.005, 0.2, 100} {
+		m.githubDuration.observe(v)
+	}
+	out := scrape(t, m)
+	for _, want := range []string{
+		`magnet_github_request_duration_seconds_bucket{le="0.005"} 2`,
+		`magnet_github_request_duration_seconds_bucket{le="0.1"} 2`,
+		`magnet_github_request_duration_seconds_bucket{le="0.25"} 3`,
+		`magnet_github_request_duration_seconds_bucket{le="60"} 3`,
+		`magnet_github_request_duration_seconds_bucket{le="+Inf"} 4`,
+		`magnet_github_request_duration_seconds_sum 100.208`,
+		`magnet_github_request_duration_seconds_count 4`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics lack %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsGithubRequests(t *testing.T) {
+	m := newServerMetrics()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Resource", "search")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: m.transport(http.DefaultTransport)}
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+		req, _ := http.NewRequest(method, srv.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`magnet_github_requests_total{method="GET",status="200"} 2`,
+		`magnet_github_requests_total{method="POST",status="201"} 1`,
+		`magnet_github_request_duration_seconds_count 3`,
+		`magnet_github_rate_limit_remaining{resource="search"} 4999`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics lack %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listMilestonesTool = &mcp.Tool{
+	Name:        "list-milestones",
+	Description: "A tool to list the milestones of a Github repository with their due dates and how many of their issues are open and closed",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"state": {
+				Type:        "string",
+				Description: "Only return milestones in this state (defaults to open)",
+				Enum:        []any{"open", "closed", "all"},
+			},
+			"sort": {
+				Type:        "string",
+				Description: "Order of the milestones: due_on for the nearest due date first, completeness for the least complete first (defaults to due_on)",
+				Enum:        []any{"due_on", "completeness"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListMilestonesArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	State string `json:"state,omitempty"`
+	Sort  string `json:"sort,omitempty"`
+}
+
+type Milestone struct {
+	Number       int        `json:"number"`
+	Title        string     `json:"title"`
+	State        string     `json:"state"`
+	Description  string     `json:"description"`
+	OpenIssues   int        `json:"open_issues"`
+	ClosedIssues int        `json:"closed_issues"`
+	DueOn        *time.Time `json:"due_on,omitempty"`
+	// Overdue is set on open milestones past their due date.
+	Overdue  bool       `json:"overdue"`
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+	HTMLURL  string     `json:"html_url"`
+}
+
+type MilestonesOutput struct {
+	Milestones []Milestone `json:"milestones"`
+}
+
+func (c *GithubClient) ListMilestones(ctx context.Context, req *mcp.CallToolRequest, args ListMilestonesArgs) (*mcp.CallToolResult, MilestonesOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, MilestonesOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, MilestonesOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	query := url.Values{}
+	query.Set("state", cmp.Or(args.State, "open"))
+	query.Set("sort", cmp.Or(args.Sort, "due_on"))
+	query.Set("per_page", fmt.Sprint(c.perPage))
+	milestones, err := getAllPages[Milestone](ctx, c, fmt.Sprintf("%s/repos/%s/%s/milestones?%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), query.Encode()))
+	if err != nil {
+		return nil, MilestonesOutput{}, err
+	}
+
+	now := time.Now()
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d milestones of %s/%s:\n", len(milestones), args.Owner, args.Repo)
+	for i, m := range milestones {
+		m.Overdue = m.State == "open" && m.DueOn != nil && m.DueOn.Before(now)
+		milestones[i] = m
+		due := "no due date"
+		if m.DueOn != nil {
+			due = "due " + m.DueOn.Format(time.DateOnly)
+		}
+		if m.Overdue {
+			due += ", overdue"
+		}
+		progress := 0
+		if total := m.OpenIssues + m.ClosedIssues; total > 0 {
+			progress = m.ClosedIssues * 100 / total
+		}
+		fmt.Fprintf(&result, "%s [%s] %s, %d open, %d closed issues (%d%% complete) %s\n",
+			m.Title, m.State, due, m.OpenIssues, m.ClosedIssues, progress, m.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, MilestonesOutput{Milestones: milestones}, nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listNotificationsTool = &mcp.Tool{
+	Name:        "list-notifications",
+	Description: "A tool to list the unread GitHub notifications of the authenticated user, most recent first, with why they were received and what they are about",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Only list the notifications of a repository of this owner, given with repo (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Only list the notifications of this repository (e.g., kubectl)",
+			},
+			"reasons": {
+				Type:        "array",
+				Description: "Only list notifications received for these reasons (e.g., review_requested, mention, assign, author, ci_activity)",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"participating": {
+				Type:        "boolean",
+				Description: "Only list the notifications of threads the user participates in or is mentioned in",
+			},
+			"all": {
+				Type:        "boolean",
+				Description: "Also list the notifications already read",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of notifications to return (defaults to 50)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type ListNotificationsArgs struct {
+	CommonArgs
+	Owner         string   `json:"owner,omitempty"`
+	Repo          string   `json:"repo,omitempty"`
+	Reasons       []string `json:"reasons,omitempty"`
+	Participating bool     `json:"participating,omitempty"`
+	All           bool     `json:"all,omitempty"`
+	Limit         int      `json:"limit,omitempty"`
+}
+
+type Notification struct {
+	// ThreadID identifies the notification thread for mark-notifications-read.
+	ThreadID   string    `json:"thread_id"`
+	Reason     string    `json:"reason"`
+	Unread     bool      `json:"unread"`
+	Repository string    `json:"repository"`
+	Title      string    `json:"title"`
+	Type       string    `json:"type"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	HTMLURL    string    `json:"html_url"`
+}
+
+type NotificationsOutput struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+type notificationThread struct {
+	ID        string    `json:"id"`
+	Reason    string    `json:"reason"`
+	Unread    bool      `json:"unread"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Subject   struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		Type  string `json:"type"`
+	} `json:"subject"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+func (c *GithubClient) ListNotifications(ctx context.Context, req *mcp.CallToolRequest, args ListNotificationsArgs) (*mcp.CallToolResult, NotificationsOutput, error) {
+	if (args.Owner == "") != (args.Repo == "") {
+		return nil, NotificationsOutput{}, fmt.Errorf("owner and repo must be given together")
+	}
+	// Notifications are read as they come, never from the cache.
+	common := args.CommonArgs
+	common.NoCache = true
+	ctx = context.WithValue(ctx, commonArgsKey{}, common)
+	limit := cmp.Or(args.Limit, 50)
+	query := url.Values{}
+	query.Set("per_page", fmt.Sprint(min(c.perPage, 50)))
+	if args.All {
+		query.Set("all", "true")
+	}
+	if args.Participating {
+		query.Set("participating", "true")
+	}
+	apiURL := fmt.Sprintf("%s/notifications?%s", c.apiURL(args.CommonArgs), query.Encode())
+	if args.Repo != "" {
+		apiURL = fmt.Sprintf("%s/repos/%s/%s/notifications?%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), query.Encode())
+	}
+
+	// The reasons are filtered here, the API can't, so pages are read until
+	// there are enough notifications left.
+	out := NotificationsOutput{Notifications: []Notification{}}
+	for page := 1; page <= c.maxPages && len(out.Notifications) < limit; page++ {
+		var threads []notificationThread
+		if err := c.getJSON(ctx, fmt.Sprintf("%s&page=%d", apiURL, page), &threads); err != nil {
+			return nil, NotificationsOutput{}, err
+		}
+		for _, t := range threads {
+			if len(args.Reasons) > 0 && !slices.Contains(args.Reasons, t.Reason) || !c.scope.allowsFullName(t.Repository.FullName) {
+				continue
+			}
+			out.Notifications = append(out.Notifications, Notification{
+				ThreadID:   t.ID,
+				Reason:     t.Reason,
+				Unread:     t.Unread,
+				Repository: t.Repository.FullName,
+				Title:      t.Subject.Title,
+				Type:       t.Subject.Type,
+				UpdatedAt:  t.UpdatedAt,
+				HTMLURL:    notificationURL(t.Repository.HTMLURL, t.Subject.URL),
+			})
+		}
+		if len(threads) < min(c.perPage, 50) {
+			break
+		}
+	}
+	out.Notifications = out.Notifications[:min(len(out.Notifications), limit)]
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d notifications:\n", len(out.Notifications))
+	for _, n := range out.Notifications {
+		read := ""
+		if !n.Unread {
+			read = " (read)"
+		}
+		fmt.Fprintf(&result, "[%s] %s %s: %s%s, %s, thread %s %s\n",
+			n.Reason, n.Repository, n.Type, n.Title, read, n.UpdatedAt.Format(time.RFC3339), n.ThreadID, n.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// notificationURL turns the API URL of the subject of a notification into
+// the page showing it, falling back to the repository for subjects without
+// one, like check suites.
+func notificationURL(repoHTMLURL, subjectURL string) string {
+	_, rest, ok := strings.Cut(subjectURL, "/repos/")
+	if !ok {
+		return repoHTMLURL
+	}
+	// rest is owner/repo/kind/id.
+	parts := strings.SplitN(rest, "/", 4)
+	if len(parts) < 4 {
+		return repoHTMLURL
+	}
+	switch parts[2] {
+	case "issues":
+		return repoHTMLURL + "/issues/" + parts[3]
+	case "pulls":
+		return repoHTMLURL + "/pull/" + parts[3]
+	case "commits":
+		return repoHTMLURL + "/commit/" + parts[3]
+	case "releases":
+		return repoHTMLURL + "/releases"
+	case "discussions":
+		return repoHTMLURL + "/discussions/" + parts[3]
+	}
+	return repoHTMLURL
+}
+
+var markNotificationsReadTool = &mcp.Tool{
+	Name:        "mark-notifications-read",
+	Description: "A tool to mark GitHub notifications of the authenticated user as read: a single thread, every notification of a repository, or all of them",
+	Annotations: writeAnnotations(false, true),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"thread_id": {
+				Type:        "string",
+				Description: "ID of the notification thread to mark as read, as listed by list-notifications",
+			},
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository whose notifications are all marked as read (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository whose notifications are all marked as read (e.g., kubectl)",
+			},
+			"all": {
+				Type:        "boolean",
+				Description: "Mark every notification as read",
+			},
+			"base_url": baseURLProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+	},
+}
+
+type MarkNotificationsReadArgs struct {
+	CommonArgs
+	ThreadID string `json:"thread_id,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+	Repo     string `json:"repo,omitempty"`
+	All      bool   `json:"all,omitempty"`
+}
+
+type MarkedNotifications struct {
+	// Marked is the thread, owner/repo, or "all".
+	Marked string `json:"marked"`
+}
+
+func (c *GithubClient) MarkNotificationsRead(ctx context.Context, req *mcp.CallToolRequest, args MarkNotificationsReadArgs) (*mcp.CallToolResult, MarkedNotifications, error) {
+	baseURL := c.apiURL(args.CommonArgs)
+	var method, apiURL string
+	var payload any
+	var out MarkedNotifications
+	switch {
+	case args.ThreadID != "" && args.Repo == "" && !args.All:
+		method, apiURL = http.MethodPatch, fmt.Sprintf("%s/notifications/threads/%s", baseURL, url.PathEscape(args.ThreadID))
+		out.Marked = "thread " + args.ThreadID
+	case args.Owner != "" && args.Repo != "" && args.ThreadID == "" && !args.All:
+		// Only the notifications received until now, not those arriving
+		// while the request is processed.
+		method, apiURL = http.MethodPut, fmt.Sprintf("%s/repos/%s/%s/notifications", baseURL, url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+		payload = map[string]string{"last_read_at": time.Now().UTC().Format(time.RFC3339)}
+		out.Marked = args.Owner + "/" + args.Repo
+	case args.All && args.ThreadID == "" && args.Repo == "":
+		method, apiURL = http.MethodPut, baseURL+"/notifications"
+		payload = map[string]string{"last_read_at": time.Now().UTC().Format(time.RFC3339)}
+		out.Marked = "all"
+	default:
+		return nil, MarkedNotifications{}, fmt.Errorf("give exactly one of thread_id, owner and repo, or all")
+	}
+	if err := c.sendJSON(ctx, method, apiURL, payload, nil); err != nil {
+		return nil, MarkedNotifications{}, err
+	}
+
+	text := fmt.Sprintf("Marked the notifications of %s as read", out.Marked)
+	if out.Marked == "all" {
+		text = "Marked all notifications as read"
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, out, nil
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// The most active repositories are found by counting the commits of the
+// activeCandidates most recently pushed ones, of which the topActive with
+// the most commits are reported.
+const (
+	activeCandidates = 30
+	topActive        = 10
+)
+
+var orgSummaryTool = &mcp.Tool{
+	Name:        "org-summary",
+	Description: "A tool to summarize a Github organization or user: repositories by language, total stars, most active repositories recently and open issue and pull request totals",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Organization or user to summarize (e.g., kubernetes)",
+			},
+			"days": {
+				Type:        "integer",
+				Description: "Number of days of activity the most active repositories are ranked on (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(365.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type OrgSummaryArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Days  int    `json:"days,omitempty"`
+}
+
+type LanguageCount struct {
+	Language     string `json:"language"`
+	Repositories int    `json:"repositories"`
+}
+
+type ActiveRepository struct {
+	Name     string    `json:"name"`
+	HTMLURL  string    `json:"html_url"`
+	PushedAt time.Time `json:"pushed_at"`
+	// Commits are the commits on the default branch since the start of the
+	// window.
+	Commits int `json:"commits"`
+}
+
+type OrgSummaryOutput struct {
+	Owner            string             `json:"owner"`
+	Since            time.Time          `json:"since"`
+	Repositories     int                `json:"repositories"`
+	Archived         int                `json:"archived"`
+	Forks            int                `json:"forks"`
+	Stars            int                `json:"stars"`
+	Languages        []LanguageCount    `json:"languages"`
+	OpenIssues       int                `json:"open_issues"`
+	OpenPullRequests int                `json:"open_pull_requests"`
+	MostActive       []ActiveRepository `json:"most_active"`
+}
+
+func (c *GithubClient) OrgSummary(ctx context.Context, req *mcp.CallToolRequest, args OrgSummaryArgs) (*mcp.CallToolResult, OrgSummaryOutput, error) {
+	if err := elicitMissing(ctx, req, "Which organization or user should be summarized?", ownerField(&args.Owner)); err != nil {
+		return nil, OrgSummaryOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, OrgSummaryOutput{}, fmt.Errorf("owner is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	since := time.Now().UTC().AddDate(0, 0, -cmp.Or(args.Days, 30)).Truncate(time.Hour)
+	accountType, err := c.accountType(ctx, baseURL, args.Owner)
+	if err != nil {
+		return nil, OrgSummaryOutput{}, err
+	}
+	qualifier := "org:"
+	if accountType == "user" {
+		qualifier = "user:"
+	}
+
+	// The repositories are paged through while the search API counts the
+	// open issues and pull requests.
+	var repos []Repository
+	var openIssues, openPulls int
+	var reposErr, issuesErr, pullsErr error
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		repos, reposErr = c.accountRepositories(ctx, baseURL, args.Owner, accountType, nil)
+	})
+	wg.Go(func() {
+		openIssues, issuesErr = c.searchCount(ctx, baseURL, qualifier+args.Owner+" is:issue is:open")
+	})
+	wg.Go(func() {
+		openPulls, pullsErr = c.searchCount(ctx, baseURL, qualifier+args.Owner+" is:pr is:open")
+	})
+	wg.Wait()
+	if err := errors.Join(reposErr, issuesErr, pullsErr); err != nil {
+		return nil, OrgSummaryOutput{}, err
+	}
+
+	out := OrgSummaryOutput{
+		Owner:            args.Owner,
+		Since:            since,
+		Repositories:     len(repos),
+		Languages:        []LanguageCount{},
+		OpenIssues:       openIssues,
+		OpenPullRequests: openPulls,
+	}
+	byLanguage := map[string]int{}
+	var recent []Repository
+	for _, r := range repos {
+		out.Stars += r.StargazersCount
+		if r.Archived {
+			out.Archived++
+		}
+		if r.Fork {
+			out.Forks++
+		}
+		byLanguage[cmp.Or(r.Language, "unknown")]++
+		if r.PushedAt.After(since) {
+			recent = append(recent, r)
+		}
+	}
+	for language, n := range byLanguage {
+		out.Languages = append(out.Languages, LanguageCount{Language: language, Repositories: n})
+	}
+	slices.SortFunc(out.Languages, func(a, b LanguageCount) int {
+		return cmp.Or(cmp.Compare(b.Repositories, a.Repositories), strings.Compare(a.Language, b.Language))
+	})
+
+	slices.SortFunc(recent, func(a, b Repository) int { return b.PushedAt.Compare(a.PushedAt) })
+	recent = recent[:min(len(recent), activeCandidates)]
+	out.MostActive, err = c.mostActive(ctx, baseURL, args.Owner, recent, since)
+	if err != nil {
+		return nil, OrgSummaryOutput{}, err
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Summary of %s:\n", args.Owner)
+	fmt.Fprintf(&result, "Repositories: %d (%d archived, %d forks), Stars: %d\n", out.Repositories, out.Archived, out.Forks, out.Stars)
+	fmt.Fprintf(&result, "Open issues: %d, Open pull requests: %d\n", out.OpenIssues, out.OpenPullRequests)
+	result.WriteString("Languages:\n")
+	for _, l := range out.Languages {
+		fmt.Fprintf(&result, "  %s: %d\n", l.Language, l.Repositories)
+	}
+	fmt.Fprintf(&result, "Most active since %s:\n", since.Format(time.DateOnly))
+	if len(out.MostActive) == 0 {
+		result.WriteString("  none\n")
+	}
+	for _, r := range out.MostActive {
+		fmt.Fprintf(&result, "  %s: %d commits, last push %s\n", r.Name, r.Commits, r.PushedAt.Format(time.DateOnly))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// mostActive counts the commits since since of repos, batchWorkers at a
+// time, and returns the topActive repositories with the most.
+func (c *GithubClient) mostActive(ctx context.Context, baseURL, owner string, repos []Repository, since time.Time) ([]ActiveRepository, error) {
+	active := make([]ActiveRepository, len(repos))
+	errs := make([]error, len(repos))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, r := range repos {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			active[i] = ActiveRepository{Name: r.Name, HTMLURL: r.HTMLURL, PushedAt: r.PushedAt}
+			active[i].Commits, errs[i] = c.commitCount(ctx, baseURL, owner, r.Name, since)
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	active = slices.DeleteFunc(active, func(r ActiveRepository) bool { return r.Commits == 0 })
+	slices.SortStableFunc(active, func(a, b ActiveRepository) int { return cmp.Compare(b.Commits, a.Commits) })
+	return active[:min(len(active), topActive)], nil
+}
+
+// commitCount returns how many commits the default branch of owner/repo got
+// since since. It asks for a single commit per page, so the last page number
+// is the count.
+func (c *GithubClient) commitCount(ctx context.Context, baseURL, owner, repo string, since time.Time) (int, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/commits?per_page=1&since=%s", baseURL, url.PathEscape(owner), url.PathEscape(repo), since.Format(time.RFC3339))
+	resp, err := c.get(ctx, apiURL)
+	if err != nil {
+		// Empty repositories have no commits to list.
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("counting commits of %s/%s: %w", owner, repo, err)
+	}
+	if last := lastPage(resp.Header.Get("Link")); last > 0 {
+		resp.Body.Close()
+		return last, nil
+	}
+	var commits []struct{}
+	if err := decodeJSON(resp, &commits); err != nil {
+		return 0, err
+	}
+	return len(commits), nil
+}
+
+// searchCount returns how many issues and pull requests match query.
+func (c *GithubClient) searchCount(ctx context.Context, baseURL, query string) (int, error) {
+	var result struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := c.search(ctx, baseURL, "issues", url.Values{"q": {query}, "per_page": {"1"}}, "", &result); err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
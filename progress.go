@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressReportBytes is how much of a download is read between two progress
+// notifications.
+const progressReportBytes = 256 << 10
+
+// progressReporter sends MCP progress notifications for the tool call that
+// asked for them with a progress token. A nil reporter discards the updates,
+// so code reporting progress doesn't need to check.
+type progressReporter struct {
+	session *mcp.ServerSession
+	token   any
+
+	mu       sync.Mutex
+	progress float64
+}
+
+type progressKey struct{}
+
+// withProgress attaches a progressReporter for the tool call req to ctx,
+// when the client asked for progress notifications.
+func withProgress(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return ctx
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{session: req.Session, token: token})
+}
+
+// progressFrom returns the progressReporter of the tool call ctx belongs to,
+// or nil.
+func progressFrom(ctx context.Context) *progressReporter {
+	p, _ := ctx.Value(progressKey{}).(*progressReporter)
+	return p
+}
+
+// value returns the progress reported so far. A tool call can do several
+// paginated fetches, each continues from where the previous one stopped
+// because progress must keep increasing.
+func (p *progressReporter) value() float64 {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress
+}
+
+// update notifies the client of the progress, out of total when it is known.
+// Updates that don't move the progress forward are dropped.
+func (p *progressReporter) update(ctx context.Context, progress, total float64, message string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if progress <= p.progress {
+		p.mu.Unlock()
+		return
+	}
+	p.progress = progress
+	p.mu.Unlock()
+	if total > 0 {
+		total = max(total, progress)
+	}
+	// Progress is best effort, a failure to send it must not fail the call.
+	_ = p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
+
+// progressReader reports the bytes read from a download of size bytes, or of
+// unknown size when it is negative.
+type progressReader struct {
+	ctx      context.Context
+	r        io.Reader
+	progress *progressReporter
+	base     float64
+	size     int64
+	read     int64
+	reported int64
+}
+
+func newProgressReader(ctx context.Context, r io.Reader, size int64) io.Reader {
+	p := progressFrom(ctx)
+	if p == nil {
+		return r
+	}
+	return &progressReader{ctx: ctx, r: r, progress: p, base: p.value(), size: size}
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.read += int64(n)
+	if r.read-r.reported >= progressReportBytes || (err == io.EOF && r.read > r.reported) {
+		r.reported = r.read
+		total := 0.0
+		if r.size > 0 {
+			total = r.base + float64(r.size)
+		}
+		r.progress.update(r.ctx, r.base+float64(r.read), total, fmt.Sprintf("Downloaded %d bytes", r.read))
+	}
+	return n, err
+}
+
+// lastPage returns the page number of the rel="last" target of a GitHub Link
+// header, or 0 when there is none.
+func lastPage(link string) int {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="last"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return 0
+		}
+		page, _ := strconv.Atoi(u.Query().Get("page"))
+		return page
+	}
+	return 0
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// projectsQuery lists a page of the projects of an organization or user.
+// Projects, the v2 ones, are only served by the GraphQL API.
+const projectsQuery = `query($owner: String!, $first: Int!, $after: String) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectsV2(first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+        totalCount
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          number
+          title
+          shortDescription
+          closed
+          public
+          url
+          updatedAt
+          items { totalCount }
+        }
+      }
+    }
+  }
+}`
+
+var listProjectsTool = &mcp.Tool{
+	Name:        "list-projects",
+	Description: "A tool to list the project boards (Projects) of a Github organization or user, most recently updated first. Requires a GitHub token with the read:project scope",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Organization or user whose projects are listed (e.g., kubernetes)",
+			},
+			"closed": {
+				Type:        "boolean",
+				Description: "Also list the closed projects",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type ListProjectsArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Closed bool   `json:"closed,omitempty"`
+}
+
+type Project struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Closed      bool      `json:"closed"`
+	Public      bool      `json:"public"`
+	Items       int       `json:"items"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+type ProjectsOutput struct {
+	Projects []Project `json:"projects"`
+}
+
+func (c *GithubClient) ListProjects(ctx context.Context, req *mcp.CallToolRequest, args ListProjectsArgs) (*mcp.CallToolResult, ProjectsOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose projects should be listed?", ownerField(&args.Owner)); err != nil {
+		return nil, ProjectsOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, ProjectsOutput{}, fmt.Errorf("owner is required")
+	}
+	// GraphQL requests don't name the owner in their path, so they must be
+	// checked before.
+	if err := c.scope.checkOwner(args.Owner); err != nil {
+		return nil, ProjectsOutput{}, err
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	first := min(c.perPage, 100)
+	variables := map[string]any{"owner": args.Owner, "first": first}
+
+	progress := progressFrom(ctx)
+	base := progress.value()
+	out := ProjectsOutput{Projects: []Project{}}
+	for page := 0; page < c.maxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			if err := interrupted(ctx, &partialError{Pages: page, Items: len(out.Projects), Err: err}); err != nil {
+				return nil, ProjectsOutput{}, err
+			}
+			break
+		}
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectsV2 struct {
+					TotalCount int `json:"totalCount"`
+					PageInfo   struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						Number           int       `json:"number"`
+						Title            string    `json:"title"`
+						ShortDescription string    `json:"shortDescription"`
+						Closed           bool      `json:"closed"`
+						Public           bool      `json:"public"`
+						URL              string    `json:"url"`
+						UpdatedAt        time.Time `json:"updatedAt"`
+						Items            struct {
+							TotalCount int `json:"totalCount"`
+						} `json:"items"`
+					} `json:"nodes"`
+				} `json:"projectsV2"`
+			} `json:"repositoryOwner"`
+		}
+		if err := c.graphQL(ctx, baseURL, projectsQuery, variables, &data); err != nil {
+			if ctx.Err() != nil && page > 0 {
+				if err := interrupted(ctx, &partialError{Pages: page, Items: len(out.Projects), Err: ctx.Err()}); err != nil {
+					return nil, ProjectsOutput{}, err
+				}
+				break
+			}
+			return nil, ProjectsOutput{}, err
+		}
+		if data.RepositoryOwner == nil {
+			return nil, ProjectsOutput{}, &apiError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no organization or user %s", args.Owner)}
+		}
+		list := data.RepositoryOwner.ProjectsV2
+		for _, p := range list.Nodes {
+			if p.Closed && !args.Closed {
+				continue
+			}
+			out.Projects = append(out.Projects, Project{
+				Number:      p.Number,
+				Title:       p.Title,
+				Description: p.ShortDescription,
+				Closed:      p.Closed,
+				Public:      p.Public,
+				Items:       p.Items.TotalCount,
+				UpdatedAt:   p.UpdatedAt,
+				HTMLURL:     p.URL,
+			})
+		}
+		pages := (list.TotalCount + first - 1) / first
+		progress.update(ctx, base+float64(page+1), base+float64(min(pages, c.maxPages)), fmt.Sprintf("Fetched page %d", page+1))
+		if !list.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = list.PageInfo.EndCursor
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d projects of %s:\n", len(out.Projects), args.Owner)
+	for _, p := range out.Projects {
+		var flags []string
+		if !p.Public {
+			flags = append(flags, "private")
+		}
+		if p.Closed {
+			flags = append(flags, "closed")
+		}
+		state := ""
+		if len(flags) > 0 {
+			state = " (" + strings.Join(flags, ", ") + ")"
+		}
+		fmt.Fprintf(&result, "#%d %s%s, %d items, updated %s: %s %s\n",
+			p.Number, p.Title, state, p.Items, p.UpdatedAt.Format(time.DateOnly), cmp.Or(p.Description, "no description"), p.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// projectItemsQuery lists a page of the items of a project with the values
+// of their fields.
+const projectItemsQuery = `query($owner: String!, $number: Int!, $first: Int!, $after: String) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        title
+        url
+        items(first: $first, after: $after) {
+          totalCount
+          pageInfo { hasNextPage endCursor }
+          nodes {
+            type
+            isArchived
+            content {
+              ... on Issue { title number url state repository { nameWithOwner } }
+              ... on PullRequest { title number url state repository { nameWithOwner } }
+              ... on DraftIssue { title }
+            }
+            fieldValues(first: 30) {
+              nodes {
+                ... on ProjectV2ItemFieldSingleSelectValue { name field { ... on ProjectV2FieldCommon { name } } }
+                ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+                ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { name } } }
+                ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+                ... on ProjectV2ItemFieldIterationValue { title field { ... on ProjectV2FieldCommon { name } } }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+var getProjectItemsTool = &mcp.Tool{
+	Name:        "get-project-items",
+	Description: "A tool to list the items of a project board (Projects) of a Github organization or user: the issues, pull requests and drafts on it with their fields, like Status, Iteration or Priority. Requires a GitHub token with the read:project scope",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Organization or user owning the project (e.g., kubernetes)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the project, as listed by list-projects",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"status": {
+				Type:        "string",
+				Description: "Only list the items with this value of the Status field (e.g., In Progress)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of items to return (defaults to 100)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(1000.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "number"},
+	},
+}
+
+type GetProjectItemsArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Number int    `json:"number"`
+	Status string `json:"status,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+type ProjectItem struct {
+	// Type is ISSUE, PULL_REQUEST, DRAFT_ISSUE or REDACTED for items the
+	// token may not see.
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Repository string `json:"repository,omitempty"`
+	Number     int    `json:"number,omitempty"`
+	State      string `json:"state,omitempty"`
+	HTMLURL    string `json:"html_url,omitempty"`
+	// Fields are the values of the project's fields set on the item, by
+	// field name.
+	Fields map[string]string `json:"fields"`
+}
+
+type ProjectItemsOutput struct {
+	Title      string        `json:"title"`
+	HTMLURL    string        `json:"html_url"`
+	TotalItems int           `json:"total_items"`
+	Items      []ProjectItem `json:"items"`
+}
+
+type projectFieldValue struct {
+	Name   string   `json:"name"`
+	Text   string   `json:"text"`
+	Number *float64 `json:"number"`
+	Date   string   `json:"date"`
+	Title  string   `json:"title"`
+	Field  struct {
+		Name string `json:"name"`
+	} `json:"field"`
+}
+
+// value returns the value of v whatever the type of its field.
+func (v projectFieldValue) value() string {
+	if v.Number != nil {
+		return strconv.FormatFloat(*v.Number, 'f', -1, 64)
+	}
+	return cmp.Or(v.Name, v.Text, v.Date, v.Title)
+}
+
+func (c *GithubClient) GetProjectItems(ctx context.Context, req *mcp.CallToolRequest, args GetProjectItemsArgs) (*mcp.CallToolResult, ProjectItemsOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose project should be read?", ownerField(&args.Owner)); err != nil {
+		return nil, ProjectItemsOutput{}, err
+	}
+	if args.Owner == "" || args.Number <= 0 {
+		return nil, ProjectItemsOutput{}, fmt.Errorf("owner and number are required")
+	}
+	if err := c.scope.checkOwner(args.Owner); err != nil {
+		return nil, ProjectItemsOutput{}, err
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	limit := cmp.Or(args.Limit, 100)
+	first := min(c.perPage, 100)
+	variables := map[string]any{"owner": args.Owner, "number": args.Number, "first": first}
+
+	progress := progressFrom(ctx)
+	base := progress.value()
+	out := ProjectItemsOutput{Items: []ProjectItem{}}
+	for page := 0; page < c.maxPages && len(out.Items) < limit; page++ {
+		if err := ctx.Err(); err != nil {
+			if err := interrupted(ctx, &partialError{Pages: page, Items: len(out.Items), Err: err}); err != nil {
+				return nil, ProjectItemsOutput{}, err
+			}
+			break
+		}
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectV2 *struct {
+					Title string `json:"title"`
+					URL   string `json:"url"`
+					Items struct {
+						TotalCount int `json:"totalCount"`
+						PageInfo   struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							Type       string `json:"type"`
+							IsArchived bool   `json:"isArchived"`
+							Content    *struct {
+								Title      string `json:"title"`
+								Number     int    `json:"number"`
+								URL        string `json:"url"`
+								State      string `json:"state"`
+								Repository *struct {
+									NameWithOwner string `json:"nameWithOwner"`
+								} `json:"repository"`
+							} `json:"content"`
+							FieldValues struct {
+								Nodes []projectFieldValue `json:"nodes"`
+							} `json:"fieldValues"`
+						} `json:"nodes"`
+					} `json:"items"`
+				} `json:"projectV2"`
+			} `json:"repositoryOwner"`
+		}
+		if err := c.graphQL(ctx, baseURL, projectItemsQuery, variables, &data); err != nil {
+			if ctx.Err() != nil && page > 0 {
+				if err := interrupted(ctx, &partialError{Pages: page, Items: len(out.Items), Err: ctx.Err()}); err != nil {
+					return nil, ProjectItemsOutput{}, err
+				}
+				break
+			}
+			return nil, ProjectItemsOutput{}, err
+		}
+		if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+			return nil, ProjectItemsOutput{}, &apiError{StatusCode: http.StatusNotFound, Body: fmt.Sprintf("no project #%d of %s", args.Number, args.Owner)}
+		}
+		project := data.RepositoryOwner.ProjectV2
+		out.Title, out.HTMLURL, out.TotalItems = project.Title, project.URL, project.Items.TotalCount
+		for _, it := range project.Items.Nodes {
+			if it.IsArchived {
+				continue
+			}
+			item := ProjectItem{Type: it.Type, Fields: map[string]string{}}
+			if it.Content != nil {
+				item.Title, item.Number, item.State, item.HTMLURL = it.Content.Title, it.Content.Number, it.Content.State, it.Content.URL
+				if it.Content.Repository != nil {
+					item.Repository = it.Content.Repository.NameWithOwner
+				}
+				// A project gathers issues from any repository, leave out
+				// those out of scope.
+				if item.Repository != "" && !c.scope.allowsFullName(item.Repository) {
+					continue
+				}
+			}
+			for _, v := range it.FieldValues.Nodes {
+				// The title is a field too, already reported.
+				if v.Field.Name != "" && v.Field.Name != "Title" {
+					item.Fields[v.Field.Name] = v.value()
+				}
+			}
+			if args.Status != "" && !strings.EqualFold(item.Fields["Status"], args.Status) {
+				continue
+			}
+			out.Items = append(out.Items, item)
+		}
+		pages := (project.Items.TotalCount + first - 1) / first
+		progress.update(ctx, base+float64(page+1), base+float64(min(pages, c.maxPages)), fmt.Sprintf("Fetched page %d", page+1))
+		if !project.Items.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = project.Items.PageInfo.EndCursor
+	}
+	out.Items = out.Items[:min(len(out.Items), limit)]
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Project #%d of %s, %s: %d of %d items %s\n", args.Number, args.Owner, out.Title, len(out.Items), out.TotalItems, out.HTMLURL)
+	for _, it := range out.Items {
+		ref := strings.ToLower(strings.ReplaceAll(it.Type, "_", " "))
+		if it.Number != 0 {
+			ref = fmt.Sprintf("%s#%d", it.Repository, it.Number)
+		}
+		fmt.Fprintf(&result, "[%s] %s: %s", cmp.Or(it.Fields["Status"], "no status"), ref, it.Title)
+		if it.State != "" {
+			fmt.Fprintf(&result, " (%s)", strings.ToLower(it.State))
+		}
+		var fields []string
+		for _, name := range slices.Sorted(maps.Keys(it.Fields)) {
+			if name != "Status" {
+				fields = append(fields, name+": "+it.Fields[name])
+			}
+		}
+		if len(fields) > 0 {
+			fmt.Fprintf(&result, " {%s}", strings.Join(fields, ", "))
+		}
+		if it.HTMLURL != "" {
+			fmt.Fprintf(&result, " %s", it.HTMLURL)
+		}
+		result.WriteString("\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProjectsScope(t *testing.T) {
+	var queries int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repositoryOwner":{"projectV2":{"title":"Roadmap","items":{"totalCount":2,"pageInfo":{"hasNextPage":false},"nodes":[
+			{"type":"ISSUE","content":{"title":"Leak","number":1,"repository":{"nameWithOwner":"o/secrets-db"}}},
+			{"type":"ISSUE","content":{"title":"Fine","number":2,"repository":{"nameWithOwner":"o/public"}}}]}}}}}`))
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{Deny: []string{"denied/*", "*/secrets-*"}})
+	register := func(s *mcp.Server) {
+		addTool(s, listProjectsTool, gh.ListProjects)
+		addTool(s, getProjectItemsTool, gh.GetProjectItems)
+	}
+
+	res := callTool(t, register, "list-projects", map[string]any{"owner": "denied"})
+	if text := resultText(res); !res.IsError || !strings.Contains(text, "outside the owners") {
+		t.Errorf("list-projects of a denied owner = %q, want a scope error", text)
+	}
+	if queries > 0 {
+		t.Errorf("%d GraphQL queries sent about a denied owner", queries)
+	}
+
+	res = callTool(t, register, "get-project-items", map[string]any{"owner": "o", "number": 1})
+	text := resultText(res)
+	if res.IsError {
+		t.Fatalf("get-project-items failed: %s", text)
+	}
+	if !strings.Contains(text, "Fine") || strings.Contains(text, "Leak") {
+		t.Errorf("get-project-items didn't leave out the denied repository:\n%s", text)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// The prompts expand into a single user message that states the task and
+// carries the data the tools would return, so a client can start a useful
+// conversation about a repository in one step. They always use the main
+// credentials.
+
+// maxPromptPatchBytes bounds the diff included in review-this-pr, so a huge
+// pull request doesn't overflow the model's context.
+const maxPromptPatchBytes = 64 << 10
+
+func repoPromptArguments() []*mcp.PromptArgument {
+	return []*mcp.PromptArgument{
+		{Name: "owner", Description: "Owner of the repository (e.g., kubernetes)", Required: true},
+		{Name: "repo", Description: "Name of the repository (e.g., kubectl)", Required: true},
+	}
+}
+
+// registerPrompts adds the prompt library to server.
+func (c *GithubClient) registerPrompts(server *mcp.Server) {
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "summarize-repository",
+		Title:       "Summarize repository",
+		Description: "Summarize what a GitHub repository is, how active it is and how to get started with it",
+		Arguments:   repoPromptArguments(),
+	}, c.summarizeRepositoryPrompt)
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "triage-open-issues",
+		Title:       "Triage open issues",
+		Description: "Group and prioritize the open issues of a GitHub repository",
+		Arguments:   repoPromptArguments(),
+	}, c.triageOpenIssuesPrompt)
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "draft-release-notes",
+		Title:       "Draft release notes",
+		Description: "Draft release notes from the commits made since the latest release",
+		Arguments:   repoPromptArguments(),
+	}, c.draftReleaseNotesPrompt)
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "review-this-pr",
+		Title:       "Review pull request",
+		Description: "Review the changes of a pull request",
+		Arguments: append(repoPromptArguments(), &mcp.PromptArgument{
+			Name:        "number",
+			Description: "Number of the pull request",
+			Required:    true,
+		}),
+	}, c.reviewPullRequestPrompt)
+}
+
+func (c *GithubClient) summarizeRepositoryPrompt(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := RepositoryArgs{Owner: req.Params.Arguments["owner"], Repo: req.Params.Arguments["repo"]}
+	details, err := toolText(c.GetRepository(ctx, nil, args))
+	if err != nil {
+		return nil, err
+	}
+	readme, err := toolText(c.GetReadme(ctx, nil, GetReadmeArgs{Owner: args.Owner, Repo: args.Repo, MaxTokens: 4000}))
+	if isNotFound(err) {
+		readme, err = "The repository has no README.", nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Summarize the GitHub repository %s/%s: what it does, who it is for, how active and mature it is, and how to get started with it.\n\n", args.Owner, args.Repo)
+	fmt.Fprintf(&text, "Repository metadata:\n%s\n", details)
+	fmt.Fprintf(&text, "README:\n%s\n", readme)
+	return promptResult("Summary of "+args.Owner+"/"+args.Repo, text.String()), nil
+}
+
+func (c *GithubClient) triageOpenIssuesPrompt(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := ListIssuesArgs{Owner: req.Params.Arguments["owner"], Repo: req.Params.Arguments["repo"], State: "open"}
+	issues, err := toolText(c.ListIssues(ctx, nil, args))
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Triage the open issues of the GitHub repository %s/%s. Group related issues, point out likely duplicates, suggest labels for unlabeled ones and list the issues that deserve attention first, with a short reason for each.\n\n", args.Owner, args.Repo)
+	text.WriteString(issues)
+	return promptResult("Triage of the open issues of "+args.Owner+"/"+args.Repo, text.String()), nil
+}
+
+func (c *GithubClient) draftReleaseNotesPrompt(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := RepositoryArgs{Owner: req.Params.Arguments["owner"], Repo: req.Params.Arguments["repo"]}
+	// Without a previous release, fall back to the last month of commits.
+	since := "in the last 30 days"
+	commitArgs := ListCommitsArgs{Owner: args.Owner, Repo: args.Repo, Since: time.Now().AddDate(0, 0, -30).Format(time.RFC3339)}
+	_, latest, err := c.GetLatestRelease(ctx, nil, args)
+	switch {
+	case err == nil:
+		since = "since " + latest.TagName
+		commitArgs.Since = latest.PublishedAt.Format(time.RFC3339)
+	case !isNotFound(err):
+		return nil, err
+	}
+	commits, err := toolText(c.ListCommits(ctx, nil, commitArgs))
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Draft release notes for the next release of the GitHub repository %s/%s, covering the commits made %s. Group the changes into features, fixes and other changes, call out breaking changes, and leave out merge commits and purely internal chores.\n\n", args.Owner, args.Repo, since)
+	text.WriteString(commits)
+	return promptResult("Release notes for "+args.Owner+"/"+args.Repo, text.String()), nil
+}
+
+func (c *GithubClient) reviewPullRequestPrompt(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	owner, repo := req.Params.Arguments["owner"], req.Params.Arguments["repo"]
+	number, err := strconv.Atoi(req.Params.Arguments["number"])
+	if owner == "" || repo == "" || err != nil {
+		return nil, fmt.Errorf("owner, repo and a pull request number are required")
+	}
+	prURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.baseURL, url.PathEscape(owner), url.PathEscape(repo), number)
+	var pr struct {
+		pullRequest
+		Body string `json:"body"`
+	}
+	if err := c.getJSON(ctx, prURL, &pr); err != nil {
+		return nil, err
+	}
+	files, err := getAllPages[struct {
+		ChangedFile
+		Patch string `json:"patch"`
+	}](ctx, c, fmt.Sprintf("%s/files?per_page=%d", prURL, c.perPage))
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Review pull request #%d of the GitHub repository %s/%s. Point out bugs, risky changes, missing tests and unclear code, referring to files and lines, and finish with an overall recommendation.\n\n", number, owner, repo)
+	fmt.Fprintf(&text, "Title: %s\nAuthor: %s\nBranches: %s -> %s\n%s\n\n", pr.Title, pr.User.Login, pr.Head.Ref, pr.Base.Ref, pr.HTMLURL)
+	if pr.Body != "" {
+		fmt.Fprintf(&text, "Description:\n%s\n\n", pr.Body)
+	}
+	patchBytes := 0
+	for _, f := range files {
+		fmt.Fprintf(&text, "--- %s [%s] +%d -%d\n", f.Filename, f.Status, f.Additions, f.Deletions)
+		switch {
+		case f.Patch == "":
+			text.WriteString("(no textual diff)\n")
+		case patchBytes+len(f.Patch) > maxPromptPatchBytes:
+			text.WriteString("(diff left out, the pull request is too large)\n")
+		default:
+			patchBytes += len(f.Patch)
+			text.WriteString(f.Patch + "\n")
+		}
+	}
+	return promptResult(fmt.Sprintf("Review of %s/%s#%d", owner, repo, number), text.String()), nil
+}
+
+// toolText joins the text content of a tool result, for use with a tool
+// handler's return values.
+func toolText[Out any](res *mcp.CallToolResult, _ Out, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, content := range res.Content {
+		if t, ok := content.(*mcp.TextContent); ok {
+			text.WriteString(t.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+func promptResult(description, text string) *mcp.GetPromptResult {
+	return &mcp.GetPromptResult{
+		Description: description,
+		Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: text}},
+		},
+	}
+}
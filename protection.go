@@ -0,0 +1,287 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var getBranchProtectionTool = &mcp.Tool{
+	Name:        "get-branch-protection",
+	Description: "A tool to report how the protected branches of a Github repository are protected, by branch protection rules and rulesets: required reviews and status checks, and whether force pushes and deletions are allowed",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"branch": {
+				Type:        "string",
+				Description: "Only report this branch (defaults to every protected branch)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type GetBranchProtectionArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Branch string `json:"branch,omitempty"`
+}
+
+type Ruleset struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Target      string `json:"target"`
+	Enforcement string `json:"enforcement"`
+	// Source is the repository or organization the ruleset is defined in.
+	Source string `json:"source"`
+}
+
+type BranchRule struct {
+	Type    string `json:"type"`
+	Ruleset string `json:"ruleset"`
+}
+
+// BranchProtection combines the branch protection rule of a branch and the
+// rules of the rulesets applying to it into the restrictions they add up to.
+type BranchProtection struct {
+	Branch string `json:"branch"`
+	// ProtectionRule is set when the branch has a branch protection rule,
+	// and ProtectionRuleUnreadable when it couldn't be read, which needs
+	// admin access to the repository.
+	ProtectionRule           bool         `json:"protection_rule"`
+	ProtectionRuleUnreadable bool         `json:"protection_rule_unreadable,omitempty"`
+	Rules                    []BranchRule `json:"rules"`
+	RequiredApprovals        int          `json:"required_approvals"`
+	RequireCodeOwnerReview   bool         `json:"require_code_owner_review"`
+	DismissStaleReviews      bool         `json:"dismiss_stale_reviews"`
+	RequiredChecks           []string     `json:"required_checks"`
+	// StrictChecks requires branches to be up to date before merging.
+	StrictChecks     bool `json:"strict_checks"`
+	AllowForcePushes bool `json:"allow_force_pushes"`
+	AllowDeletions   bool `json:"allow_deletions"`
+	EnforceAdmins    bool `json:"enforce_admins"`
+	LinearHistory    bool `json:"linear_history"`
+	SignedCommits    bool `json:"signed_commits"`
+}
+
+type BranchProtectionOutput struct {
+	Repository string             `json:"repository"`
+	Rulesets   []Ruleset          `json:"rulesets"`
+	Branches   []BranchProtection `json:"branches"`
+}
+
+type enabledSetting struct {
+	Enabled bool `json:"enabled"`
+}
+
+type protectionRule struct {
+	RequiredStatusChecks *struct {
+		Strict   bool     `json:"strict"`
+		Contexts []string `json:"contexts"`
+	} `json:"required_status_checks"`
+	RequiredPullRequestReviews *struct {
+		DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+		RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+	} `json:"required_pull_request_reviews"`
+	EnforceAdmins         enabledSetting `json:"enforce_admins"`
+	RequiredLinearHistory enabledSetting `json:"required_linear_history"`
+	AllowForcePushes      enabledSetting `json:"allow_force_pushes"`
+	AllowDeletions        enabledSetting `json:"allow_deletions"`
+	RequiredSignatures    enabledSetting `json:"required_signatures"`
+}
+
+type branchRule struct {
+	Type       string `json:"type"`
+	RulesetID  int64  `json:"ruleset_id"`
+	Parameters struct {
+		RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+		RequireCodeOwnerReview       bool `json:"require_code_owner_review"`
+		DismissStaleReviewsOnPush    bool `json:"dismiss_stale_reviews_on_push"`
+		RequiredStatusChecks         []struct {
+			Context string `json:"context"`
+		} `json:"required_status_checks"`
+		StrictRequiredStatusChecksPolicy bool `json:"strict_required_status_checks_policy"`
+	} `json:"parameters"`
+}
+
+func (c *GithubClient) GetBranchProtection(ctx context.Context, req *mcp.CallToolRequest, args GetBranchProtectionArgs) (*mcp.CallToolResult, BranchProtectionOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, BranchProtectionOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, BranchProtectionOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+
+	// GitHub Enterprise Server versions older than rulesets answer 404.
+	rulesets, err := getAllPages[Ruleset](ctx, c, fmt.Sprintf("%s/rulesets?includes_parents=true&per_page=%d", repoURL, c.perPage))
+	if isNotFound(err) {
+		rulesets, err = []Ruleset{}, nil
+	}
+	if err != nil {
+		return nil, BranchProtectionOutput{}, err
+	}
+	out := BranchProtectionOutput{Repository: args.Owner + "/" + args.Repo, Rulesets: rulesets}
+	names := map[int64]string{}
+	for _, r := range rulesets {
+		names[r.ID] = r.Name
+	}
+
+	branches := []string{args.Branch}
+	if args.Branch == "" {
+		protected, err := getAllPages[Branch](ctx, c, fmt.Sprintf("%s/branches?protected=true&per_page=%d", repoURL, c.perPage))
+		if err != nil {
+			return nil, BranchProtectionOutput{}, err
+		}
+		branches = branches[:0]
+		for _, b := range protected {
+			branches = append(branches, b.Name)
+		}
+	}
+
+	// Branches are looked at batchWorkers at a time.
+	out.Branches = make([]BranchProtection, len(branches))
+	errs := make([]error, len(branches))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, branch := range branches {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			out.Branches[i], errs[i] = c.branchProtection(ctx, repoURL, branch, names)
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, BranchProtectionOutput{}, err
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Protection of %s\n", out.Repository)
+	if len(out.Rulesets) > 0 {
+		result.WriteString("Rulesets:\n")
+		for _, r := range out.Rulesets {
+			fmt.Fprintf(&result, "  %s (%s, %s, from %s)\n", r.Name, r.Target, r.Enforcement, r.Source)
+		}
+	}
+	if len(out.Branches) == 0 {
+		result.WriteString("No protected branches\n")
+	}
+	for _, b := range out.Branches {
+		fmt.Fprintf(&result, "\nBranch %s\n", b.Branch)
+		switch {
+		case b.ProtectionRuleUnreadable:
+			result.WriteString("  Branch protection rule: can't be read without admin access\n")
+		case b.ProtectionRule:
+			result.WriteString("  Branch protection rule: yes\n")
+		default:
+			result.WriteString("  Branch protection rule: none\n")
+		}
+		for _, r := range b.Rules {
+			fmt.Fprintf(&result, "  Rule %s from ruleset %s\n", r.Type, r.Ruleset)
+		}
+		fmt.Fprintf(&result, "  Required approvals: %d, code owner review: %t, dismiss stale reviews: %t\n", b.RequiredApprovals, b.RequireCodeOwnerReview, b.DismissStaleReviews)
+		fmt.Fprintf(&result, "  Required checks: %s (up to date: %t)\n", cmp.Or(strings.Join(b.RequiredChecks, ", "), "none"), b.StrictChecks)
+		fmt.Fprintf(&result, "  Force pushes allowed: %t, deletions allowed: %t, enforced for admins: %t, linear history: %t, signed commits: %t\n",
+			b.AllowForcePushes, b.AllowDeletions, b.EnforceAdmins, b.LinearHistory, b.SignedCommits)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// branchProtection reads the branch protection rule of branch and the rules
+// applying to it, names giving the names of the rulesets by ID.
+func (c *GithubClient) branchProtection(ctx context.Context, repoURL, branch string, names map[int64]string) (BranchProtection, error) {
+	p := BranchProtection{Branch: branch, Rules: []BranchRule{}, RequiredChecks: []string{}, AllowForcePushes: true, AllowDeletions: true}
+
+	var rule protectionRule
+	err := c.getJSON(ctx, fmt.Sprintf("%s/branches/%s/protection", repoURL, escapePath(branch)), &rule)
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		// The branch has no protection rule.
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden:
+		p.ProtectionRuleUnreadable = true
+	case err != nil:
+		return p, fmt.Errorf("getting the protection of %s: %w", branch, err)
+	default:
+		p.ProtectionRule = true
+		if checks := rule.RequiredStatusChecks; checks != nil {
+			p.RequiredChecks = append(p.RequiredChecks, checks.Contexts...)
+			p.StrictChecks = checks.Strict
+		}
+		if reviews := rule.RequiredPullRequestReviews; reviews != nil {
+			p.RequiredApprovals = reviews.RequiredApprovingReviewCount
+			p.RequireCodeOwnerReview = reviews.RequireCodeOwnerReviews
+			p.DismissStaleReviews = reviews.DismissStaleReviews
+		}
+		p.AllowForcePushes = rule.AllowForcePushes.Enabled
+		p.AllowDeletions = rule.AllowDeletions.Enabled
+		p.EnforceAdmins = rule.EnforceAdmins.Enabled
+		p.LinearHistory = rule.RequiredLinearHistory.Enabled
+		p.SignedCommits = rule.RequiredSignatures.Enabled
+	}
+
+	// The rules of every active ruleset targeting the branch add up with
+	// its protection rule, the strictest setting winning.
+	rules, err := getAllPages[branchRule](ctx, c, fmt.Sprintf("%s/rules/branches/%s?per_page=%d", repoURL, escapePath(branch), c.perPage))
+	if isNotFound(err) {
+		rules, err = nil, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("getting the rules of %s: %w", branch, err)
+	}
+	for _, r := range rules {
+		p.Rules = append(p.Rules, BranchRule{Type: r.Type, Ruleset: cmp.Or(names[r.RulesetID], strconv.FormatInt(r.RulesetID, 10))})
+		switch r.Type {
+		case "pull_request":
+			p.RequiredApprovals = max(p.RequiredApprovals, r.Parameters.RequiredApprovingReviewCount)
+			p.RequireCodeOwnerReview = p.RequireCodeOwnerReview || r.Parameters.RequireCodeOwnerReview
+			p.DismissStaleReviews = p.DismissStaleReviews || r.Parameters.DismissStaleReviewsOnPush
+		case "required_status_checks":
+			for _, check := range r.Parameters.RequiredStatusChecks {
+				if !slices.Contains(p.RequiredChecks, check.Context) {
+					p.RequiredChecks = append(p.RequiredChecks, check.Context)
+				}
+			}
+			p.StrictChecks = p.StrictChecks || r.Parameters.StrictRequiredStatusChecksPolicy
+		case "non_fast_forward":
+			p.AllowForcePushes = false
+		case "deletion":
+			p.AllowDeletions = false
+		case "required_linear_history":
+			p.LinearHistory = true
+		case "required_signatures":
+			p.SignedCommits = true
+		}
+	}
+	return p, nil
+}
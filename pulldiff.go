@@ -0,0 +1,239 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxDiffBytes bounds how much of the diff of a pull request is downloaded.
+const maxDiffBytes = 32 << 20
+
+// maxPullRequestFiles is the number of files GitHub lists for a pull
+// request at most.
+const maxPullRequestFiles = 3000
+
+var listPullRequestFilesTool = &mcp.Tool{
+	Name:        "list-pr-files",
+	Description: "A tool to list the files changed by a pull request on Github, with their status and the number of lines added and deleted",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type ListPullRequestFilesArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+}
+
+type PullRequestFilesOutput struct {
+	Files     []ChangedFile `json:"files"`
+	Additions int           `json:"additions"`
+	Deletions int           `json:"deletions"`
+	// Truncated is set when the pull request changes more files than
+	// GitHub lists.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// pullRequestFile is a file of a pull request with its patch, which GitHub
+// leaves out for binary and very large files.
+type pullRequestFile struct {
+	ChangedFile
+	Patch string `json:"patch"`
+}
+
+func (c *GithubClient) ListPullRequestFiles(ctx context.Context, req *mcp.CallToolRequest, args ListPullRequestFilesArgs) (*mcp.CallToolResult, PullRequestFilesOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, PullRequestFilesOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, PullRequestFilesOutput{}, fmt.Errorf("owner, repo and number are required")
+	}
+	files, err := c.pullRequestFiles(ctx, args.CommonArgs, args.Owner, args.Repo, args.Number)
+	if err != nil {
+		return nil, PullRequestFilesOutput{}, err
+	}
+
+	out := PullRequestFilesOutput{Files: []ChangedFile{}, Truncated: len(files) >= maxPullRequestFiles}
+	for _, f := range files {
+		out.Files = append(out.Files, f.ChangedFile)
+		out.Additions += f.Additions
+		out.Deletions += f.Deletions
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Pull request #%d of %s/%s changes %d files, %d additions, %d deletions:\n", args.Number, args.Owner, args.Repo, len(out.Files), out.Additions, out.Deletions)
+	if out.Truncated {
+		fmt.Fprintf(&result, "GitHub only lists the first %d files\n", maxPullRequestFiles)
+	}
+	for _, f := range out.Files {
+		renamed := ""
+		if f.PreviousFilename != "" {
+			renamed = " from " + f.PreviousFilename
+		}
+		fmt.Fprintf(&result, "%s [%s%s] +%d -%d\n", f.Filename, f.Status, renamed, f.Additions, f.Deletions)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+func (c *GithubClient) pullRequestFiles(ctx context.Context, common CommonArgs, owner, repo string, number int) ([]pullRequestFile, error) {
+	return getAllPages[pullRequestFile](ctx, c, fmt.Sprintf("%s/repos/%s/%s/pulls/%d/files?per_page=%d",
+		c.apiURL(common), url.PathEscape(owner), url.PathEscape(repo), number, c.perPage))
+}
+
+var getPullRequestDiffTool = &mcp.Tool{
+	Name:        "get-pr-diff",
+	Description: "A tool to read the unified diff of a pull request on Github, or of a single file of it. Long diffs are returned in chunks",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"path": {
+				Type:        "string",
+				Description: "Only return the diff of this file, as listed by list-pr-files",
+			},
+			"format": {
+				Type:        "string",
+				Description: "diff for a single unified diff, patch for the diff of each commit with its message (defaults to diff, ignored with path)",
+				Enum:        []any{"diff", "patch"},
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Byte offset to start reading at, to continue a truncated diff",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"max_bytes": {
+				Type:        "integer",
+				Description: "Maximum number of bytes to return (defaults to 102400)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type GetPullRequestDiffArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	Number   int    `json:"number"`
+	Path     string `json:"path,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Offset   int    `json:"offset,omitempty"`
+	MaxBytes int    `json:"max_bytes,omitempty"`
+}
+
+func (c *GithubClient) GetPullRequestDiff(ctx context.Context, req *mcp.CallToolRequest, args GetPullRequestDiffArgs) (*mcp.CallToolResult, any, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, nil, fmt.Errorf("owner, repo and number are required")
+	}
+
+	var diff []byte
+	if args.Path != "" {
+		// The diff of a single file is its patch in the file list.
+		files, err := c.pullRequestFiles(ctx, args.CommonArgs, args.Owner, args.Repo, args.Number)
+		if err != nil {
+			return nil, nil, err
+		}
+		var file *pullRequestFile
+		for i := range files {
+			if files[i].Filename == args.Path {
+				file = &files[i]
+				break
+			}
+		}
+		if file == nil {
+			return nil, nil, fmt.Errorf("pull request #%d doesn't change %s", args.Number, args.Path)
+		}
+		if file.Patch == "" {
+			return nil, nil, fmt.Errorf("GitHub has no diff of %s, which is binary or too large (+%d -%d)", args.Path, file.Additions, file.Deletions)
+		}
+		from := cmp.Or(file.PreviousFilename, file.Filename)
+		oldPath, newPath := "a/"+from, "b/"+file.Filename
+		switch file.Status {
+		case "added":
+			oldPath = "/dev/null"
+		case "removed":
+			newPath = "/dev/null"
+		}
+		diff = fmt.Appendf(nil, "diff --git a/%s b/%s\n--- %s\n+++ %s\n%s\n", from, file.Filename, oldPath, newPath, file.Patch)
+	} else {
+		format := "diff"
+		if args.Format == "patch" {
+			format = "patch"
+		}
+		var err error
+		diff, err = c.downloadMedia(ctx, fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.Number),
+			"application/vnd.github."+format, maxDiffBytes)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotAcceptable {
+			return nil, nil, fmt.Errorf("the diff of pull request #%d is too large for GitHub to generate, read the diff of its files one path at a time: %w", args.Number, err)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	maxBytes := args.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxFileBytes
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: chunk(diff, args.Offset, maxBytes)},
+		},
+	}, nil, nil
+}
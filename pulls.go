@@ -0,0 +1,358 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listPullRequestsTool = &mcp.Tool{
+	Name:        "list-pull-requests",
+	Description: "A tool to list the pull requests of a Github repository, including whether each one can be merged for the first 30 open ones",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"state": {
+				Type:        "string",
+				Description: "Only return pull requests in this state (defaults to open)",
+				Enum:        []any{"open", "closed", "all"},
+			},
+			"base": {
+				Type:        "string",
+				Description: "Only return pull requests targeting this base branch (e.g., main)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"provider": providerProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ListPullRequestsArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	State string `json:"state,omitempty"`
+	Base  string `json:"base,omitempty"`
+}
+
+type pullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Draft   bool   `json:"draft"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	// MergeableState is only populated by the single pull request endpoint,
+	// not by the listing.
+	MergeableState string `json:"mergeable_state"`
+}
+
+func (c *GithubClient) ListPullRequests(ctx context.Context, req *mcp.CallToolRequest, args ListPullRequestsArgs) (*mcp.CallToolResult, any, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, nil, fmt.Errorf("owner and repo are required")
+	}
+	forge, err := c.forge(args.CommonArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	pulls, err := forge.ListPullRequests(ctx, args.Owner, args.Repo, PullRequestListOptions{State: args.State, Base: args.Base})
+	if err != nil {
+		return nil, nil, err
+	}
+	var result strings.Builder
+	fmt.Fprintf(&result, "Pull requests for repository %s/%s:\n", args.Owner, args.Repo)
+	for _, pr := range pulls {
+		draft := ""
+		if pr.Draft {
+			draft = " (draft)"
+		}
+		fmt.Fprintf(&result, "#%d [%s]%s %s by %s, %s -> %s, mergeable: %s %s\n",
+			pr.Number, pr.State, draft, pr.Title, pr.Author, pr.Head, pr.Base, pr.Mergeable, pr.URL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, nil, nil
+}
+
+var createPullRequestTool = &mcp.Tool{
+	Name:        "create-pull-request",
+	Description: "A tool to open a pull request in a Github repository from an existing branch",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"head": {
+				Type:        "string",
+				Description: "Branch with the changes, prefixed with the owner for a fork (e.g., octocat:fix-typo)",
+			},
+			"base": {
+				Type:        "string",
+				Description: "Branch the changes should be merged into (e.g., main)",
+			},
+			"title": {
+				Type:        "string",
+				Description: "Title of the pull request",
+			},
+			"body": {
+				Type:        "string",
+				Description: "Description of the pull request, in Markdown",
+			},
+			"draft": {
+				Type:        "boolean",
+				Description: "Open the pull request as a draft",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "head", "base", "title"},
+	},
+}
+
+type CreatePullRequestArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+type CreatedPullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (c *GithubClient) CreatePullRequest(ctx context.Context, req *mcp.CallToolRequest, args CreatePullRequestArgs) (*mcp.CallToolResult, CreatedPullRequest, error) {
+	if args.Owner == "" || args.Repo == "" || args.Head == "" || args.Base == "" || args.Title == "" {
+		return nil, CreatedPullRequest{}, fmt.Errorf("owner, repo, head, base and title are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/pulls", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	payload := map[string]any{
+		"title": args.Title,
+		"head":  args.Head,
+		"base":  args.Base,
+		"draft": args.Draft,
+	}
+	if args.Body != "" {
+		payload["body"] = args.Body
+	}
+
+	var created CreatedPullRequest
+	if err := c.sendJSON(ctx, http.MethodPost, apiURL, payload, &created); err != nil {
+		return nil, CreatedPullRequest{}, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Opened pull request #%d in %s/%s (%s -> %s): %s\n", created.Number, args.Owner, args.Repo, args.Head, args.Base, created.HTMLURL)},
+		},
+	}, created, nil
+}
+
+var mergePullRequestTool = &mcp.Tool{
+	Name:        "merge-pull-request",
+	Description: "A tool to merge a pull request of a Github repository, after checking that it is mergeable and its required status checks passed",
+	Annotations: writeAnnotations(true, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"method": {
+				Type:        "string",
+				Description: "How to merge the changes (defaults to merge)",
+				Enum:        []any{"merge", "squash", "rebase"},
+			},
+			"commit_title": {
+				Type:        "string",
+				Description: "Title of the merge or squash commit (defaults to GitHub's)",
+			},
+			"commit_message": {
+				Type:        "string",
+				Description: "Message of the merge or squash commit (defaults to GitHub's)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type MergePullRequestArgs struct {
+	CommonArgs
+	Owner         string `json:"owner"`
+	Repo          string `json:"repo"`
+	Number        int    `json:"number"`
+	Method        string `json:"method,omitempty"`
+	CommitTitle   string `json:"commit_title,omitempty"`
+	CommitMessage string `json:"commit_message,omitempty"`
+}
+
+type MergeResult struct {
+	Merged  bool   `json:"merged"`
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+}
+
+func (c *GithubClient) MergePullRequest(ctx context.Context, req *mcp.CallToolRequest, args MergePullRequestArgs) (*mcp.CallToolResult, MergeResult, error) {
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, MergeResult{}, fmt.Errorf("owner, repo and number are required")
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	prURL := fmt.Sprintf("%s/pulls/%d", repoURL, args.Number)
+
+	// Always look at the current state of the pull request, a cached one
+	// could let a merge through after new commits broke the checks.
+	common := args.CommonArgs
+	common.NoCache = true
+	ctx = context.WithValue(ctx, commonArgsKey{}, common)
+	var pr struct {
+		pullRequest
+		Mergeable *bool `json:"mergeable"`
+	}
+	if err := c.getJSON(ctx, prURL, &pr); err != nil {
+		return nil, MergeResult{}, err
+	}
+	switch {
+	case pr.State != "open":
+		return nil, MergeResult{}, fmt.Errorf("pull request #%d is %s", args.Number, pr.State)
+	case pr.Draft:
+		return nil, MergeResult{}, fmt.Errorf("pull request #%d is a draft", args.Number)
+	case pr.Mergeable != nil && !*pr.Mergeable:
+		return nil, MergeResult{}, fmt.Errorf("pull request #%d has conflicts with %s", args.Number, pr.Base.Ref)
+	}
+	failing, err := c.failingRequiredChecks(ctx, repoURL, pr.Base.Ref, pr.Head.SHA)
+	if err != nil {
+		return nil, MergeResult{}, err
+	}
+	if len(failing) > 0 {
+		return nil, MergeResult{}, fmt.Errorf("required status checks of pull request #%d have not passed: %s", args.Number, strings.Join(failing, ", "))
+	}
+	if failing == nil && pr.MergeableState == "blocked" {
+		// The branch protection couldn't be read, but GitHub says something
+		// it requires, checks or reviews, is missing.
+		return nil, MergeResult{}, fmt.Errorf("pull request #%d is blocked by the branch protection of %s", args.Number, pr.Base.Ref)
+	}
+
+	payload := map[string]string{
+		"merge_method": cmp.Or(args.Method, "merge"),
+		// Only merge the commits that were checked above.
+		"sha": pr.Head.SHA,
+	}
+	if args.CommitTitle != "" {
+		payload["commit_title"] = args.CommitTitle
+	}
+	if args.CommitMessage != "" {
+		payload["commit_message"] = args.CommitMessage
+	}
+	var out MergeResult
+	if err := c.sendJSON(ctx, http.MethodPut, prURL+"/merge", payload, &out); err != nil {
+		return nil, MergeResult{}, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Merged pull request #%d of %s/%s into %s as %s: %s\n", args.Number, args.Owner, args.Repo, pr.Base.Ref, out.SHA, out.Message)},
+		},
+	}, out, nil
+}
+
+// failingRequiredChecks returns the status checks required by the branch
+// protection of branch that haven't succeeded on commit sha. It returns nil,
+// rather than an empty slice, when the protection can't be read, which needs
+// admin access.
+func (c *GithubClient) failingRequiredChecks(ctx context.Context, repoURL, branch, sha string) ([]string, error) {
+	var required struct {
+		Contexts []string `json:"contexts"`
+	}
+	err := c.getJSON(ctx, fmt.Sprintf("%s/branches/%s/protection/required_status_checks", repoURL, escapePath(branch)), &required)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusForbidden) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	failing := []string{}
+	if len(required.Contexts) == 0 {
+		return failing, nil
+	}
+
+	// Required checks are either commit statuses or check runs.
+	checks, err := c.commitChecks(ctx, repoURL, sha)
+	if err != nil {
+		return nil, err
+	}
+	passed := map[string]bool{}
+	for _, s := range checks.Statuses {
+		passed[s.Context] = s.State == "success"
+	}
+	for _, r := range checks.CheckRuns {
+		passed[r.Name] = r.Conclusion == "success" || r.Conclusion == "neutral" || r.Conclusion == "skipped"
+	}
+	for _, name := range required.Contexts {
+		if !passed[name] {
+			failing = append(failing, name)
+		}
+	}
+	return failing, nil
+}
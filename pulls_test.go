@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMergePullRequestDryRun(t *testing.T) {
+	var writes []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/o/r/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":1,"state":"open","mergeable":true,"head":{"ref":"feature","sha":"abc123"},"base":{"ref":"main"}}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+		}
+		http.NotFound(w, r)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, mergePullRequestTool, gh.MergePullRequest) }, "merge-pull-request", map[string]any{
+		"owner": "o", "repo": "r", "number": 1, "dry_run": true,
+	})
+	if res.IsError {
+		t.Fatalf("merge-pull-request failed: %s", resultText(res))
+	}
+	if len(writes) > 0 {
+		t.Errorf("dry run sent %v", writes)
+	}
+	if text := resultText(res); !strings.Contains(text, "PUT ") || !strings.Contains(text, "/repos/o/r/pulls/1/merge") {
+		t.Errorf("dry run result doesn't report the merge:\n%s", text)
+	}
+}
+
+func TestListPullRequestsCapsMergeableLookups(t *testing.T) {
+	var mu sync.Mutex
+	lookups, running, maxRunning := 0, 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/o/r/pulls", func(w http.ResponseWriter, r *http.Request) {
+		var pulls []string
+		for n := 1; n <= 40; n++ {
+			pulls = append(pulls, fmt.Sprintf(`{"number":%d,"state":"open","user":{"login":"u"},"head":{"ref":"h"},"base":{"ref":"main"}}`, n))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(pulls, ","))
+	})
+	mux.HandleFunc("GET /repos/o/r/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lookups++
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"mergeable_state":"clean"}`))
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, listPullRequestsTool, gh.ListPullRequests) }, "list-pull-requests", map[string]any{
+		"owner": "o", "repo": "r",
+	})
+	text := resultText(res)
+	if res.IsError {
+		t.Fatalf("list-pull-requests failed: %s", text)
+	}
+	if lookups != maxMergeableLookups || maxRunning > batchWorkers {
+		t.Errorf("%d lookups, %d at once, want %d and at most %d", lookups, maxRunning, maxMergeableLookups, batchWorkers)
+	}
+	if clean, unknown := strings.Count(text, "mergeable: clean"), strings.Count(text, "mergeable: unknown"); clean != 30 || unknown != 10 {
+		t.Errorf("%d clean and %d unknown pull requests, want 30 and 10:\n%s", clean, unknown, text)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var rateLimitStatusTool = &mcp.Tool{
+	Name:        "rate-limit-status",
+	Description: "A tool to report the GitHub API quota left to the credentials in use: the remaining requests of the core, search and GraphQL APIs and when they reset. Checking it doesn't use any quota",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"base_url": baseURLProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type RateLimitStatusArgs struct {
+	CommonArgs
+}
+
+type RateLimit struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+type RateLimitOutput struct {
+	// Resources lists core, search and graphql first, followed by the
+	// other limits GitHub reports, like code_search.
+	Resources []RateLimit `json:"resources"`
+}
+
+func (c *GithubClient) RateLimitStatus(ctx context.Context, req *mcp.CallToolRequest, args RateLimitStatusArgs) (*mcp.CallToolResult, RateLimitOutput, error) {
+	// A cached quota would be useless to plan calls with.
+	common := args.CommonArgs
+	common.NoCache = true
+	ctx = context.WithValue(ctx, commonArgsKey{}, common)
+	var limits struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Used      int   `json:"used"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := c.getJSON(ctx, c.apiURL(args.CommonArgs)+"/rate_limit", &limits); err != nil {
+		if isNotFound(err) {
+			return nil, RateLimitOutput{}, fmt.Errorf("rate limiting is disabled on this GitHub server: %w", err)
+		}
+		return nil, RateLimitOutput{}, err
+	}
+
+	out := RateLimitOutput{Resources: []RateLimit{}}
+	for name, l := range limits.Resources {
+		out.Resources = append(out.Resources, RateLimit{
+			Resource:  name,
+			Limit:     l.Limit,
+			Used:      l.Used,
+			Remaining: l.Remaining,
+			Reset:     time.Unix(l.Reset, 0).UTC(),
+		})
+	}
+	first := []string{"core", "search", "graphql"}
+	rank := func(name string) int {
+		if i := slices.Index(first, name); i >= 0 {
+			return i
+		}
+		return len(first)
+	}
+	slices.SortFunc(out.Resources, func(a, b RateLimit) int {
+		return cmp.Or(cmp.Compare(rank(a.Resource), rank(b.Resource)), strings.Compare(a.Resource, b.Resource))
+	})
+
+	var result strings.Builder
+	now := time.Now()
+	for _, l := range out.Resources {
+		fmt.Fprintf(&result, "%s: %d of %d remaining, resets at %s (in %s)\n",
+			l.Resource, l.Remaining, l.Limit, l.Reset.Format(time.RFC3339), max(l.Reset.Sub(now), 0).Round(time.Second))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
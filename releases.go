@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listReleasesTool = &mcp.Tool{
+	Name:        "list-releases",
+	Description: "A tool to list the releases of a Github repository, newest first",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+var getLatestReleaseTool = &mcp.Tool{
+	Name:        "get-latest-release",
+	Description: "A tool to get the latest published release of a Github repository, including its release notes",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Assets      []string  `json:"assets"`
+	// Body holds the release notes. It is only filled in by get-latest-release
+	// and create-release.
+	Body string `json:"body,omitempty"`
+}
+
+type ReleaseListOutput struct {
+	Repository string    `json:"repository"`
+	Releases   []Release `json:"releases"`
+}
+
+type release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Body        string    `json:"body"`
+	Assets      []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+func (r *release) toRelease() Release {
+	out := Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		PublishedAt: r.PublishedAt,
+		HTMLURL:     r.HTMLURL,
+		Assets:      []string{},
+	}
+	for _, a := range r.Assets {
+		out.Assets = append(out.Assets, a.Name)
+	}
+	return out
+}
+
+func (c *GithubClient) ListReleases(ctx context.Context, req *mcp.CallToolRequest, args RepositoryArgs) (*mcp.CallToolResult, ReleaseListOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, ReleaseListOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, ReleaseListOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), c.perPage)
+
+	raw, err := getAllPages[release](ctx, c, apiURL)
+	if err != nil {
+		return nil, ReleaseListOutput{}, err
+	}
+	releases := make([]Release, 0, len(raw))
+	var result strings.Builder
+	fmt.Fprintf(&result, "Releases for repository %s/%s:\n", args.Owner, args.Repo)
+	for _, r := range raw {
+		rel := r.toRelease()
+		releases = append(releases, rel)
+		fmt.Fprintf(&result, "%s %q%s published %s, assets: %s %s\n",
+			rel.TagName, rel.Name, releaseFlags(rel), rel.PublishedAt.Format(time.DateOnly), strings.Join(rel.Assets, ", "), rel.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, ReleaseListOutput{
+		Repository: args.Owner + "/" + args.Repo,
+		Releases:   releases,
+	}, nil
+}
+
+func (c *GithubClient) GetLatestRelease(ctx context.Context, req *mcp.CallToolRequest, args RepositoryArgs) (*mcp.CallToolResult, Release, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, Release{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, Release{}, fmt.Errorf("owner and repo are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases/latest", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+
+	var raw release
+	if err := c.getJSON(ctx, apiURL, &raw); err != nil {
+		return nil, Release{}, err
+	}
+	rel := raw.toRelease()
+	rel.Body = raw.Body
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Latest release of %s/%s: %s %q%s\n", args.Owner, args.Repo, rel.TagName, rel.Name, releaseFlags(rel))
+	fmt.Fprintf(&result, "Published: %s\n", rel.PublishedAt.Format(time.RFC3339))
+	fmt.Fprintf(&result, "URL: %s\n", rel.HTMLURL)
+	fmt.Fprintf(&result, "Assets: %s\n", strings.Join(rel.Assets, ", "))
+	fmt.Fprintf(&result, "\n%s\n", rel.Body)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, rel, nil
+}
+
+func releaseFlags(r Release) string {
+	var flags []string
+	if r.Draft {
+		flags = append(flags, "draft")
+	}
+	if r.Prerelease {
+		flags = append(flags, "prerelease")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(flags, ", ") + ")"
+}
+
+var createReleaseTool = &mcp.Tool{
+	Name:        "create-release",
+	Description: "A tool to publish a release of a Github repository from a tag, creating the tag if it doesn't exist yet",
+	Annotations: writeAnnotations(false, false),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"tag": {
+				Type:        "string",
+				Description: "Tag of the release (e.g., v1.2.0)",
+			},
+			"target": {
+				Type:        "string",
+				Description: "Branch or commit SHA to create the tag at when it doesn't exist (defaults to the default branch)",
+			},
+			"name": {
+				Type:        "string",
+				Description: "Title of the release (defaults to the tag)",
+			},
+			"body": {
+				Type:        "string",
+				Description: "Release notes, in Markdown",
+			},
+			"draft": {
+				Type:        "boolean",
+				Description: "Create the release as an unpublished draft",
+			},
+			"prerelease": {
+				Type:        "boolean",
+				Description: "Mark the release as a prerelease",
+			},
+			"generate_notes": {
+				Type:        "boolean",
+				Description: "Let GitHub generate the release notes from the merged pull requests, after the body if one is given",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+			"dry_run":  dryRunProperty(),
+		},
+		Required: []string{"owner", "repo", "tag"},
+	},
+}
+
+type CreateReleaseArgs struct {
+	CommonArgs
+	Owner         string `json:"owner"`
+	Repo          string `json:"repo"`
+	Tag           string `json:"tag"`
+	Target        string `json:"target,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Body          string `json:"body,omitempty"`
+	Draft         bool   `json:"draft,omitempty"`
+	Prerelease    bool   `json:"prerelease,omitempty"`
+	GenerateNotes bool   `json:"generate_notes,omitempty"`
+}
+
+func (c *GithubClient) CreateRelease(ctx context.Context, req *mcp.CallToolRequest, args CreateReleaseArgs) (*mcp.CallToolResult, Release, error) {
+	if args.Owner == "" || args.Repo == "" || args.Tag == "" {
+		return nil, Release{}, fmt.Errorf("owner, repo and tag are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/releases", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	payload := map[string]any{
+		"tag_name":               args.Tag,
+		"draft":                  args.Draft,
+		"prerelease":             args.Prerelease,
+		"generate_release_notes": args.GenerateNotes,
+	}
+	if args.Target != "" {
+		payload["target_commitish"] = args.Target
+	}
+	if args.Name != "" {
+		payload["name"] = args.Name
+	}
+	if args.Body != "" {
+		payload["body"] = args.Body
+	}
+
+	var raw release
+	if err := c.sendJSON(ctx, http.MethodPost, apiURL, payload, &raw); err != nil {
+		return nil, Release{}, err
+	}
+	rel := raw.toRelease()
+	rel.Body = raw.Body
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Created release %s %s%s of %s/%s: %s\n", rel.TagName, rel.Name, releaseFlags(rel), args.Owner, args.Repo, rel.HTMLURL)},
+		},
+	}, rel, nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var getRepositoryTool = &mcp.Tool{
+	Name:        "get-repository",
+	Description: "A tool to get the full metadata of a single Github repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type RepositoryArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+}
+
+type repositoryDetails struct {
+	FullName        string    `json:"full_name"`
+	Description     string    `json:"description"`
+	HTMLURL         string    `json:"html_url"`
+	Private         bool      `json:"private"`
+	Fork            bool      `json:"fork"`
+	Archived        bool      `json:"archived"`
+	DefaultBranch   string    `json:"default_branch"`
+	Language        string    `json:"language"`
+	StargazersCount int       `json:"stargazers_count"`
+	ForksCount      int       `json:"forks_count"`
+	OpenIssuesCount int       `json:"open_issues_count"`
+	Topics          []string  `json:"topics"`
+	PushedAt        time.Time `json:"pushed_at"`
+	License         *struct {
+		SPDXID string `json:"spdx_id"`
+		Name   string `json:"name"`
+	} `json:"license"`
+}
+
+func (c *GithubClient) GetRepository(ctx context.Context, req *mcp.CallToolRequest, args RepositoryArgs) (*mcp.CallToolResult, any, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, nil, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, nil, fmt.Errorf("owner and repo are required")
+	}
+	repo, err := c.repository(ctx, c.apiURL(args.CommonArgs), args.Owner, args.Repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	var result strings.Builder
+	writeRepositoryDetails(&result, repo)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, nil, nil
+}
+
+// repository fetches the metadata of owner/repo.
+func (c *GithubClient) repository(ctx context.Context, baseURL, owner, repo string) (*repositoryDetails, error) {
+	var details repositoryDetails
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/repos/%s/%s", baseURL, url.PathEscape(owner), url.PathEscape(repo)), &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+func writeRepositoryDetails(w *strings.Builder, repo *repositoryDetails) {
+	license := "none"
+	if repo.License != nil {
+		license = repo.License.SPDXID
+	}
+	fmt.Fprintf(w, "Repository %s (%s)\n", repo.FullName, repo.HTMLURL)
+	fmt.Fprintf(w, "Description: %s\n", repo.Description)
+	fmt.Fprintf(w, "Language: %s\n", repo.Language)
+	fmt.Fprintf(w, "Stars: %d, Forks: %d, Open issues: %d\n", repo.StargazersCount, repo.ForksCount, repo.OpenIssuesCount)
+	fmt.Fprintf(w, "Default branch: %s\n", repo.DefaultBranch)
+	fmt.Fprintf(w, "License: %s\n", license)
+	fmt.Fprintf(w, "Topics: %s\n", strings.Join(repo.Topics, ", "))
+	fmt.Fprintf(w, "Private: %t, Fork: %t, Archived: %t\n", repo.Private, repo.Fork, repo.Archived)
+	fmt.Fprintf(w, "Last push: %s\n", repo.PushedAt.Format(time.RFC3339))
+}
+
+// maxBatchRepositories caps how many repositories get-multiple-repositories
+// takes, and batchWorkers how many it fetches at once.
+const (
+	maxBatchRepositories = 100
+	batchWorkers         = 8
+)
+
+var getMultipleRepositoriesTool = &mcp.Tool{
+	Name:        "get-multiple-repositories",
+	Description: "A tool to get the metadata of several Github repositories at once, fetched concurrently",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"repositories": {
+				Type:        "array",
+				Description: "Repositories as owner/repo or URLs (e.g., [\"kubernetes/kubectl\", \"https://github.com/golang/go\"])",
+				Items:       &jsonschema.Schema{Type: "string"},
+				MinItems:    jsonschema.Ptr(1),
+				MaxItems:    jsonschema.Ptr(maxBatchRepositories),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"repositories"},
+	},
+}
+
+type MultipleRepositoriesArgs struct {
+	CommonArgs
+	Repositories []string `json:"repositories"`
+}
+
+// RepositoryResult is the metadata of one of the repositories asked for, or
+// why it couldn't be fetched.
+type RepositoryResult struct {
+	Repository string             `json:"repository"`
+	Details    *repositoryDetails `json:"details,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+type MultipleRepositoriesOutput struct {
+	Repositories []RepositoryResult `json:"repositories"`
+}
+
+func (c *GithubClient) GetMultipleRepositories(ctx context.Context, req *mcp.CallToolRequest, args MultipleRepositoriesArgs) (*mcp.CallToolResult, MultipleRepositoriesOutput, error) {
+	if len(args.Repositories) == 0 {
+		return nil, MultipleRepositoriesOutput{}, fmt.Errorf("repositories is required")
+	}
+	if len(args.Repositories) > maxBatchRepositories {
+		return nil, MultipleRepositoriesOutput{}, fmt.Errorf("at most %d repositories can be fetched at once, got %d", maxBatchRepositories, len(args.Repositories))
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+
+	// A repository that can't be fetched is reported in its result rather
+	// than failing the others.
+	results := make([]RepositoryResult, len(args.Repositories))
+	progress := progressFrom(ctx)
+	var mu sync.Mutex
+	done := 0
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, name := range args.Repositories {
+		results[i].Repository = name
+		ref, err := parseRepoURL(name)
+		if err == nil && ref.Repo == "" {
+			err = fmt.Errorf("%q is not an owner/repo", name)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			details, err := c.repository(ctx, baseURL, ref.Owner, ref.Repo)
+			if err != nil {
+				results[i].Error = err.Error()
+			} else {
+				results[i].Details = details
+			}
+			mu.Lock()
+			done++
+			progress.update(ctx, float64(done), float64(len(args.Repositories)), fmt.Sprintf("Fetched %s", name))
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	var result strings.Builder
+	failed := 0
+	for i, r := range results {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(&result, "Repository %s: %s\n", r.Repository, r.Error)
+			continue
+		}
+		writeRepositoryDetails(&result, r.Details)
+	}
+	if failed > 0 {
+		fmt.Fprintf(&result, "\n%d of %d repositories could not be fetched\n", failed, len(results))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, MultipleRepositoriesOutput{Repositories: results}, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Repositories are exposed as MCP resources with github://{owner}/{repo}
+// URIs, whose body is the repository metadata as JSON. Any repository can be
+// read through the resource template, and the ones discovered by the listing
+// and search tools are also added to resources/list so clients can offer them
+// as context.
+
+// Files are read through a second template,
+// github://{owner}/{repo}/contents/{path}, with an optional ?ref= query
+// selecting the branch, tag or commit.
+
+const (
+	repoResourceTemplate = "github://{owner}/{repo}"
+	fileResourceTemplate = "github://{owner}/{repo}/contents/{+path}{?ref}"
+)
+
+// registerResources adds the repository resource template to server and
+// remembers server so discovered repositories can be added to it.
+func (c *GithubClient) registerResources(server *mcp.Server) {
+	c.server = server
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "repository",
+		Title:       "GitHub repository",
+		Description: "Metadata of a GitHub repository",
+		MIMEType:    "application/json",
+		URITemplate: repoResourceTemplate,
+	}, c.readRepositoryResource)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "file",
+		Title:       "GitHub repository file",
+		Description: "A file of a GitHub repository, at the default branch unless ref is given",
+		URITemplate: fileResourceTemplate,
+	}, c.readFileResource)
+}
+
+func repoResourceURI(fullName string) string {
+	return "github://" + fullName
+}
+
+// addRepositoryResources lists the repositories, given as owner/repo, in
+// resources/list. Only repositories found with the main credentials are
+// added, because resources are always read with them.
+func (c *GithubClient) addRepositoryResources(args CommonArgs, repos map[string]string) {
+	if c.server == nil || args.BaseURL != "" || args.Profile != "" {
+		return
+	}
+	for fullName, description := range repos {
+		if fullName == "" || !c.scope.allowsFullName(fullName) {
+			continue
+		}
+		uri := repoResourceURI(fullName)
+		if _, loaded := c.resources.LoadOrStore(uri, true); loaded {
+			continue
+		}
+		c.server.AddResource(&mcp.Resource{
+			Name:        fullName,
+			Description: description,
+			MIMEType:    "application/json",
+			URI:         uri,
+		}, c.readRepositoryResource)
+	}
+}
+
+func (c *GithubClient) readRepositoryResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	owner, repo, ok := parseRepoResourceURI(req.Params.URI)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, url.PathEscape(owner), url.PathEscape(repo))
+	var details repositoryDetails
+	if err := c.getJSON(ctx, apiURL, &details); err != nil {
+		if isNotFound(err) {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		return nil, err
+	}
+	body, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "application/json", Text: string(body)},
+		},
+	}, nil
+}
+
+// parseRepoResourceURI splits a github://{owner}/{repo} URI.
+func parseRepoResourceURI(uri string) (owner, repo string, ok bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "github" || u.Host == "" {
+		return "", "", false
+	}
+	repo = strings.Trim(u.Path, "/")
+	if repo == "" || strings.Contains(repo, "/") {
+		return "", "", false
+	}
+	return u.Host, repo, true
+}
+
+func (c *GithubClient) readFileResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	owner, repo, filePath, ref, ok := parseFileResourceURI(req.Params.URI)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	file, err := c.getFileContent(ctx, c.baseURL, owner, repo, filePath, ref)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		return nil, err
+	}
+	data, err := decodeFileContent(file)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := &mcp.ResourceContents{URI: req.Params.URI, MIMEType: mime.TypeByExtension(path.Ext(filePath))}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		contents.MIMEType = cmp.Or(contents.MIMEType, "application/octet-stream")
+		contents.Blob = data
+	} else {
+		contents.MIMEType = cmp.Or(contents.MIMEType, "text/plain")
+		contents.Text = string(data)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
+
+// parseFileResourceURI splits a github://{owner}/{repo}/contents/{path}?ref=
+// URI.
+func parseFileResourceURI(uri string) (owner, repo, filePath, ref string, ok bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "github" || u.Host == "" {
+		return "", "", "", "", false
+	}
+	repo, rest, found := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/contents/")
+	if !found || repo == "" || strings.Contains(repo, "/") || strings.Trim(rest, "/") == "" {
+		return "", "", "", "", false
+	}
+	return u.Host, repo, rest, u.Query().Get("ref"), true
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryTransport is an http.RoundTripper that retries requests GitHub
+// rejected because of rate limiting, as well as transient failures.
+//
+// Primary rate limits are signalled by a 403 or 429 with
+// X-RateLimit-Remaining: 0 and an X-RateLimit-Reset timestamp, secondary
+// limits by a Retry-After header. When neither header says how long to wait
+// the delay grows exponentially from baseDelay. If GitHub asks for a wait
+// longer than maxDelay the response is returned as is instead of blocking
+// the tool call.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	// timeout bounds each individual attempt until its response headers
+	// arrive, so neither the time spent waiting between attempts nor reading
+	// large bodies, like archives, counts against it.
+	timeout time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, cancel, stop := t.prepare(req)
+		resp, err := t.base.RoundTrip(attemptReq)
+		if stop() {
+			if err == nil {
+				resp.Body.Close()
+			}
+			resp, err = nil, fmt.Errorf("no response from %s within %s: %w", req.URL.Host, t.timeout, context.DeadlineExceeded)
+		}
+		if attempt >= t.maxRetries || !canRetry(req) {
+			return withCancel(resp, err, cancel)
+		}
+		delay, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return withCancel(resp, err, cancel)
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// prepare returns the request to send for one attempt, cancelled when
+// t.timeout passes before its response headers arrive. stop ends the
+// timeout once they did and reports whether it expired first.
+func (t *retryTransport) prepare(req *http.Request) (r *http.Request, cancel context.CancelFunc, stop func() bool) {
+	ctx, cancel := context.WithCancel(req.Context())
+	stop = func() bool { return false }
+	if t.timeout > 0 {
+		timer := time.AfterFunc(t.timeout, cancel)
+		stop = func() bool { return !timer.Stop() }
+	}
+	r = req.Clone(ctx)
+	if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err == nil {
+			r.Body = body
+		}
+	}
+	return r, cancel, stop
+}
+
+// canRetry reports whether req can be sent again: its body, if any, must be
+// replayable.
+func canRetry(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	return req.GetBody != nil
+}
+
+// retryDelay decides whether the outcome of an attempt is worth retrying and
+// how long to wait before doing so.
+func (t *retryTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		// The request may have reached GitHub, so only retry when sending it
+		// again is harmless or when it never left.
+		if req.Context().Err() != nil || !isSafeMethod(req.Method) && !isDialError(err) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		// Retry-After is either a number of seconds or an HTTP date.
+		if s := resp.Header.Get("Retry-After"); s != "" {
+			if secs, err := strconv.Atoi(s); err == nil {
+				return t.limit(time.Duration(secs) * time.Second)
+			}
+			if at, err := http.ParseTime(s); err == nil {
+				return t.limit(time.Until(at))
+			}
+			return 0, false
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			if err != nil {
+				return t.backoff(attempt), true
+			}
+			return t.limit(time.Until(time.Unix(reset, 0)) + time.Second)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return t.backoff(attempt), true
+		}
+		// A plain 403 is a permission problem, retrying won't help.
+		return 0, false
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		// A PUT or DELETE may have been applied before the gateway gave
+		// up, and sending it again could repeat a merge or a deletion.
+		if !isSafeMethod(req.Method) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// limit accepts a wait requested by GitHub unless it is longer than maxDelay.
+func (t *retryTransport) limit(d time.Duration) (time.Duration, bool) {
+	if d < 0 {
+		d = 0
+	}
+	if d > t.maxDelay {
+		return 0, false
+	}
+	return d, true
+}
+
+// backoff returns an exponentially growing delay with jitter, capped at
+// maxDelay.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.baseDelay << attempt
+	if d <= 0 || d > t.maxDelay {
+		d = t.maxDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// isSafeMethod reports whether requests with method only read, so sending
+// them twice changes nothing.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isDialError reports whether err happened while connecting, before any of
+// the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// withCancel ties the per-attempt context to the lifetime of the response
+// body, so that reading the body isn't cut short when RoundTrip returns.
+func withCancel(resp *http.Response, err error, cancel context.CancelFunc) (*http.Response, error) {
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
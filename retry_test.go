@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRetryTransportOnlyRetriesSafeMethods(t *testing.T) {
+	for method, want := range map[string]int{
+		http.MethodGet:    3,
+		http.MethodPut:    1,
+		http.MethodDelete: 1,
+		http.MethodPost:   1,
+	} {
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 2, baseDelay: time.Millisecond, maxDelay: 10 * time.Millisecond}}
+		req, err := http.NewRequest(method, srv.URL, strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		srv.Close()
+		if attempts != want {
+			t.Errorf("%s answered 502: %d attempts, want %d", method, attempts, want)
+		}
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	rt := &retryTransport{baseDelay: time.Millisecond, maxDelay: time.Minute}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+	delay, retry := rt.retryDelay(req, resp, nil, 0)
+	if !retry || delay < 25*time.Second || delay > 30*time.Second {
+		t.Errorf("Retry-After date: delay %v, retry %v, want about 30s", delay, retry)
+	}
+}
+
+func TestRetryTransportTimeoutOnlyCoversHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		// The body takes longer than the timeout to arrive.
+		for range 4 {
+			w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, timeout: 100 * time.Millisecond, baseDelay: time.Millisecond, maxDelay: time.Millisecond}}
+
+	resp, err := client.Get(srv.URL + "/slow-body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || len(body) != 20 {
+		t.Errorf("slow body: read %q, %v", body, err)
+	}
+
+	if _, err := client.Get(srv.URL + "/slow-headers"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow headers: %v, want a deadline exceeded error", err)
+	}
+}
+
+func TestRetryWaitsForRateLimitReset(t *testing.T) {
+	attempts := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/o/repos", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		jsonHandler(`[{"name":"r","full_name":"o/r"}]`)(w, r)
+	})
+	gh := newTestClient(t, mux, GithubClientOptions{MaxRetries: 2, RetryBaseDelay: time.Millisecond, RetryMaxDelay: 5 * time.Second})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+		"name": "o", "account_type": "org",
+	})
+	if res.IsError || attempts != 2 {
+		t.Errorf("rate limited listing: %q after %d attempts, want success after 2", resultText(res), attempts)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	rt := &retryTransport{baseDelay: time.Second, maxDelay: time.Minute}
+	get, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	for name, tc := range map[string]struct {
+		status  int
+		header  map[string]string
+		retry   bool
+		atLeast time.Duration
+		atMost  time.Duration
+	}{
+		"permission denied":    {status: http.StatusForbidden},
+		"not found":            {status: http.StatusNotFound},
+		"retry after":          {status: http.StatusForbidden, header: map[string]string{"Retry-After": "5"}, retry: true, atLeast: 5 * time.Second, atMost: 5 * time.Second},
+		"retry after too long": {status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "3600"}},
+		"reset too far": {status: http.StatusForbidden, header: map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10),
+		}},
+		"too many requests": {status: http.StatusTooManyRequests, retry: true, atLeast: 500 * time.Millisecond, atMost: time.Second},
+		"bad gateway":       {status: http.StatusBadGateway, retry: true, atLeast: 500 * time.Millisecond, atMost: time.Second},
+	} {
+		resp := &http.Response{StatusCode: tc.status, Header: http.Header{}}
+		for k, v := range tc.header {
+			resp.Header.Set(k, v)
+		}
+		delay, retry := rt.retryDelay(get, resp, nil, 0)
+		if retry != tc.retry || retry && (delay < tc.atLeast || delay > tc.atMost) {
+			t.Errorf("%s: delay %v, retry %v, want retry %v within [%v, %v]", name, delay, retry, tc.retry, tc.atLeast, tc.atMost)
+		}
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	rt := &retryTransport{baseDelay: time.Second, maxDelay: 10 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if d := rt.backoff(attempt); d < want/2 || d > want {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, d, want/2, want)
+		}
+	}
+	if d := rt.backoff(80); d < 5*time.Second || d > 10*time.Second {
+		t.Errorf("backoff(80) = %v, want the capped delay", d)
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 5, baseDelay: time.Hour, maxDelay: time.Hour}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("cancelled retry: %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("cancelled retry returned after %v", elapsed)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var reviewQueueTool = &mcp.Tool{
+	Name:        "review-queue",
+	Description: "A tool to list the open Github pull requests waiting for a review from a user or team, oldest first, with their age, size and CI status",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"user": {
+				Type:        "string",
+				Description: "User whose review is requested (defaults to the authenticated user)",
+			},
+			"team": {
+				Type:        "string",
+				Description: "Team whose review is requested, as org/team-slug (e.g., kubernetes/sig-cli), instead of a user",
+			},
+			"owner": {
+				Type:        "string",
+				Description: "Only return pull requests of this organization or user's repositories",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of pull requests to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type ReviewQueueArgs struct {
+	CommonArgs
+	User  string `json:"user,omitempty"`
+	Team  string `json:"team,omitempty"`
+	Owner string `json:"owner,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type ReviewQueueItem struct {
+	Repository   string    `json:"repository"`
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	Author       string    `json:"author"`
+	Draft        bool      `json:"draft"`
+	CreatedAt    time.Time `json:"created_at"`
+	AgeDays      int       `json:"age_days"`
+	ChangedFiles int       `json:"changed_files"`
+	Additions    int       `json:"additions"`
+	Deletions    int       `json:"deletions"`
+	// CI is success, failure, pending, or none without any check.
+	CI      string `json:"ci"`
+	HTMLURL string `json:"html_url"`
+}
+
+type ReviewQueueOutput struct {
+	Query        string            `json:"query"`
+	TotalCount   int               `json:"total_count"`
+	PullRequests []ReviewQueueItem `json:"pull_requests"`
+}
+
+func (c *GithubClient) ReviewQueue(ctx context.Context, req *mcp.CallToolRequest, args ReviewQueueArgs) (*mcp.CallToolResult, ReviewQueueOutput, error) {
+	if args.User != "" && args.Team != "" {
+		return nil, ReviewQueueOutput{}, fmt.Errorf("give either user or team, not both")
+	}
+	terms := []string{"is:pr", "is:open", "sort:created-asc"}
+	switch {
+	case args.Team != "":
+		if !strings.Contains(args.Team, "/") {
+			return nil, ReviewQueueOutput{}, fmt.Errorf("team must be given as org/team-slug, got %q", args.Team)
+		}
+		terms = append(terms, "team-review-requested:"+args.Team)
+	case args.User != "":
+		terms = append(terms, "review-requested:"+args.User)
+	default:
+		terms = append(terms, "review-requested:@me")
+	}
+	if args.Owner != "" {
+		terms = append(terms, "user:"+args.Owner)
+	}
+	query := strings.Join(terms, " ")
+	baseURL := c.apiURL(args.CommonArgs)
+
+	var found struct {
+		TotalCount int               `json:"total_count"`
+		Items      []issueSearchItem `json:"items"`
+	}
+	if err := c.search(ctx, baseURL, "issues", searchQuery(query, "", "", args.Limit), "", &found); err != nil {
+		return nil, ReviewQueueOutput{}, err
+	}
+
+	// The size and CI status of each pull request are looked up
+	// batchWorkers at a time.
+	now := time.Now()
+	out := ReviewQueueOutput{Query: query, TotalCount: found.TotalCount, PullRequests: make([]ReviewQueueItem, len(found.Items))}
+	errs := make([]error, len(found.Items))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+	for i, item := range found.Items {
+		repo := repoFromAPIURL(item.RepositoryURL)
+		out.PullRequests[i] = ReviewQueueItem{
+			Repository: repo,
+			Number:     item.Number,
+			Title:      item.Title,
+			Author:     item.User.Login,
+			CreatedAt:  item.CreatedAt,
+			AgeDays:    int(now.Sub(item.CreatedAt).Hours() / 24),
+			HTMLURL:    item.HTMLURL,
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			repoURL := baseURL + "/repos/" + repo
+			var pr struct {
+				pullRequest
+				ChangedFiles int `json:"changed_files"`
+				Additions    int `json:"additions"`
+				Deletions    int `json:"deletions"`
+			}
+			if err := c.getJSON(ctx, fmt.Sprintf("%s/pulls/%d", repoURL, item.Number), &pr); err != nil {
+				errs[i] = fmt.Errorf("getting %s#%d: %w", repo, item.Number, err)
+				return
+			}
+			ci, err := c.ciStatus(ctx, repoURL, pr.Head.SHA)
+			if err != nil {
+				errs[i] = fmt.Errorf("getting the CI status of %s#%d: %w", repo, item.Number, err)
+				return
+			}
+			item := &out.PullRequests[i]
+			item.Draft = pr.Draft
+			item.ChangedFiles = pr.ChangedFiles
+			item.Additions = pr.Additions
+			item.Deletions = pr.Deletions
+			item.CI = ci
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, ReviewQueueOutput{}, err
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d pull requests waiting for review, showing %d:\n", out.TotalCount, len(out.PullRequests))
+	for _, pr := range out.PullRequests {
+		draft := ""
+		if pr.Draft {
+			draft = " (draft)"
+		}
+		fmt.Fprintf(&result, "%s#%d %s by %s%s, %d days old, %d files +%d -%d, CI %s %s\n",
+			pr.Repository, pr.Number, pr.Title, pr.Author, draft, pr.AgeDays, pr.ChangedFiles, pr.Additions, pr.Deletions, pr.CI, pr.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// ciStatus sums up the commit statuses and check runs of commit sha, as
+// commitChecks.state does.
+func (c *GithubClient) ciStatus(ctx context.Context, repoURL, sha string) (string, error) {
+	checks, err := c.commitChecks(ctx, repoURL, sha)
+	if err != nil {
+		return "", err
+	}
+	return checks.state(), nil
+}
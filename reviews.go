@@ -0,0 +1,222 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// hunkContextLines is the number of lines of the diff hunk kept with a
+// review thread, those ending at the commented line.
+const hunkContextLines = 4
+
+var getPullRequestReviewsTool = &mcp.Tool{
+	Name:        "get-pr-reviews",
+	Description: "A tool to read the reviews of a pull request on Github, with their state and body, and its inline review comments threaded by file and line",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"number": {
+				Type:        "integer",
+				Description: "Number of the pull request",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo", "number"},
+	},
+}
+
+type GetPullRequestReviewsArgs struct {
+	CommonArgs
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+}
+
+type Review struct {
+	ID       int64  `json:"id"`
+	Reviewer string `json:"reviewer"`
+	// State is APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED or
+	// PENDING.
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	CommitID    string    `json:"commit_id"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+type ReviewComment struct {
+	ID        int64     `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	HTMLURL   string    `json:"html_url"`
+}
+
+type ReviewThread struct {
+	Path string `json:"path"`
+	// Line is the commented line, of the new file unless Side is LEFT, and
+	// StartLine the first one of a multi-line comment.
+	Line      int    `json:"line"`
+	StartLine int    `json:"start_line,omitempty"`
+	Side      string `json:"side"`
+	// Outdated is set when later commits changed the commented lines, Line
+	// is then the line in the commit the comment was made on.
+	Outdated bool            `json:"outdated"`
+	DiffHunk string          `json:"diff_hunk"`
+	Comments []ReviewComment `json:"comments"`
+}
+
+type PullRequestReviewsOutput struct {
+	Reviews []Review       `json:"reviews"`
+	Threads []ReviewThread `json:"threads"`
+}
+
+type pullRequestReview struct {
+	ID          int64       `json:"id"`
+	State       string      `json:"state"`
+	Body        string      `json:"body"`
+	CommitID    string      `json:"commit_id"`
+	SubmittedAt time.Time   `json:"submitted_at"`
+	HTMLURL     string      `json:"html_url"`
+	User        *githubUser `json:"user"`
+}
+
+type pullReviewComment struct {
+	ID           int64       `json:"id"`
+	InReplyToID  int64       `json:"in_reply_to_id"`
+	Path         string      `json:"path"`
+	Line         *int        `json:"line"`
+	OriginalLine int         `json:"original_line"`
+	StartLine    *int        `json:"start_line"`
+	Side         string      `json:"side"`
+	DiffHunk     string      `json:"diff_hunk"`
+	Body         string      `json:"body"`
+	CreatedAt    time.Time   `json:"created_at"`
+	HTMLURL      string      `json:"html_url"`
+	User         *githubUser `json:"user"`
+}
+
+func (c *GithubClient) GetPullRequestReviews(ctx context.Context, req *mcp.CallToolRequest, args GetPullRequestReviewsArgs) (*mcp.CallToolResult, PullRequestReviewsOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, PullRequestReviewsOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" || args.Number <= 0 {
+		return nil, PullRequestReviewsOutput{}, fmt.Errorf("owner, repo and number are required")
+	}
+	prURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo), args.Number)
+
+	var reviews []pullRequestReview
+	var comments []pullReviewComment
+	var reviewsErr, commentsErr error
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		reviews, reviewsErr = getAllPages[pullRequestReview](ctx, c, fmt.Sprintf("%s/reviews?per_page=%d", prURL, c.perPage))
+	})
+	wg.Go(func() {
+		comments, commentsErr = getAllPages[pullReviewComment](ctx, c, fmt.Sprintf("%s/comments?per_page=%d", prURL, c.perPage))
+	})
+	wg.Wait()
+	if err := errors.Join(reviewsErr, commentsErr); err != nil {
+		return nil, PullRequestReviewsOutput{}, err
+	}
+
+	out := PullRequestReviewsOutput{Reviews: []Review{}, Threads: []ReviewThread{}}
+	for _, r := range reviews {
+		out.Reviews = append(out.Reviews, Review{
+			ID:          r.ID,
+			Reviewer:    r.User.login(),
+			State:       r.State,
+			Body:        r.Body,
+			CommitID:    r.CommitID,
+			SubmittedAt: r.SubmittedAt,
+			HTMLURL:     r.HTMLURL,
+		})
+	}
+	out.Threads = reviewThreads(comments)
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d reviews of %s/%s#%d:\n", len(out.Reviews), args.Owner, args.Repo, args.Number)
+	for _, r := range out.Reviews {
+		fmt.Fprintf(&result, "\n[%s] %s, %s %s\n", r.State, r.Reviewer, r.SubmittedAt.Format(time.RFC3339), r.HTMLURL)
+		if r.Body != "" {
+			fmt.Fprintf(&result, "%s\n", r.Body)
+		}
+	}
+	fmt.Fprintf(&result, "\n%d review threads:\n", len(out.Threads))
+	for _, t := range out.Threads {
+		lines := fmt.Sprint(t.Line)
+		if t.StartLine != 0 && t.StartLine != t.Line {
+			lines = fmt.Sprintf("%d-%d", t.StartLine, t.Line)
+		}
+		outdated := ""
+		if t.Outdated {
+			outdated = " (outdated)"
+		}
+		fmt.Fprintf(&result, "\n----- %s:%s%s -----\n%s\n", t.Path, lines, outdated, t.DiffHunk)
+		for _, c := range t.Comments {
+			fmt.Fprintf(&result, "> %s, %s: %s\n", c.Author, c.CreatedAt.Format(time.RFC3339), c.Body)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// reviewThreads groups review comments into threads, the replies following
+// the comment starting the thread, ordered by file and line.
+func reviewThreads(comments []pullReviewComment) []ReviewThread {
+	threads := []ReviewThread{}
+	byID := map[int64]int{}
+	// Replies point at an earlier comment of their thread.
+	slices.SortStableFunc(comments, func(a, b pullReviewComment) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	for _, rc := range comments {
+		comment := ReviewComment{ID: rc.ID, Author: rc.User.login(), Body: rc.Body, CreatedAt: rc.CreatedAt, HTMLURL: rc.HTMLURL}
+		if i, ok := byID[rc.InReplyToID]; ok {
+			threads[i].Comments = append(threads[i].Comments, comment)
+			byID[rc.ID] = i
+			continue
+		}
+		t := ReviewThread{Path: rc.Path, Line: rc.OriginalLine, Side: cmp.Or(rc.Side, "RIGHT"), Outdated: rc.Line == nil, Comments: []ReviewComment{comment}}
+		if rc.Line != nil {
+			t.Line = *rc.Line
+		}
+		if rc.StartLine != nil {
+			t.StartLine = *rc.StartLine
+		}
+		hunk := strings.Split(strings.TrimRight(rc.DiffHunk, "\n"), "\n")
+		t.DiffHunk = strings.Join(hunk[max(len(hunk)-hunkContextLines, 0):], "\n")
+		byID[rc.ID] = len(threads)
+		threads = append(threads, t)
+	}
+	slices.SortStableFunc(threads, func(a, b ReviewThread) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line))
+	})
+	return threads
+}
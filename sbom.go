@@ -0,0 +1,176 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var exportSBOMTool = &mcp.Tool{
+	Name:        "export-sbom",
+	Description: "A tool to export the software bill of materials of a Github repository from its dependency graph, as a list of the packages it depends on with their ecosystem, version and license",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"ecosystem": {
+				Type:        "string",
+				Description: "Only return packages of this ecosystem, as named in package URLs (e.g., golang, npm, pypi, maven, githubactions)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type ExportSBOMArgs struct {
+	CommonArgs
+	Owner     string `json:"owner"`
+	Repo      string `json:"repo"`
+	Ecosystem string `json:"ecosystem,omitempty"`
+}
+
+type SBOMPackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+	// PURL is the package URL identifying the package, like
+	// pkg:golang/github.com/spf13/cobra@v1.8.0.
+	PURL    string `json:"purl,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+type SBOMOutput struct {
+	Repository string `json:"repository"`
+	// SPDXVersion and Created describe the SPDX document GitHub generated.
+	SPDXVersion string        `json:"spdx_version"`
+	Created     time.Time     `json:"created"`
+	Packages    []SBOMPackage `json:"packages"`
+	// Ecosystems counts the packages of every ecosystem, before filtering.
+	Ecosystems map[string]int `json:"ecosystems"`
+}
+
+type spdxDocument struct {
+	SPDXVersion  string `json:"spdxVersion"`
+	CreationInfo struct {
+		Created time.Time `json:"created"`
+	} `json:"creationInfo"`
+	DocumentDescribes []string `json:"documentDescribes"`
+	Packages          []struct {
+		SPDXID           string `json:"SPDXID"`
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+		ExternalRefs     []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+func (c *GithubClient) ExportSBOM(ctx context.Context, req *mcp.CallToolRequest, args ExportSBOMArgs) (*mcp.CallToolResult, SBOMOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, SBOMOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, SBOMOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/dependency-graph/sbom", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	var resp struct {
+		SBOM spdxDocument `json:"sbom"`
+	}
+	if err := c.getJSON(ctx, apiURL, &resp); err != nil {
+		if isNotFound(err) {
+			return nil, SBOMOutput{}, fmt.Errorf("no dependency graph for %s/%s, it may be disabled or the repository not found: %w", args.Owner, args.Repo, err)
+		}
+		return nil, SBOMOutput{}, err
+	}
+	doc := resp.SBOM
+
+	out := SBOMOutput{
+		Repository:  args.Owner + "/" + args.Repo,
+		SPDXVersion: doc.SPDXVersion,
+		Created:     doc.CreationInfo.Created,
+		Packages:    []SBOMPackage{},
+		Ecosystems:  map[string]int{},
+	}
+	for _, p := range doc.Packages {
+		// The document describes the repository itself, which isn't one of
+		// its dependencies.
+		if slices.Contains(doc.DocumentDescribes, p.SPDXID) {
+			continue
+		}
+		pkg := SBOMPackage{Name: p.Name, Version: p.VersionInfo, License: spdxLicense(p.LicenseConcluded, p.LicenseDeclared)}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				pkg.PURL = ref.ReferenceLocator
+				pkg.Ecosystem = purlType(ref.ReferenceLocator)
+				break
+			}
+		}
+		out.Ecosystems[pkg.Ecosystem]++
+		if args.Ecosystem != "" && !strings.EqualFold(pkg.Ecosystem, args.Ecosystem) {
+			continue
+		}
+		out.Packages = append(out.Packages, pkg)
+	}
+	slices.SortFunc(out.Packages, func(a, b SBOMPackage) int {
+		return cmp.Or(strings.Compare(a.Ecosystem, b.Ecosystem), strings.Compare(a.Name, b.Name), strings.Compare(a.Version, b.Version))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d packages in the dependency graph of %s (%s, generated %s):\n", len(out.Packages), out.Repository, out.SPDXVersion, out.Created.Format(time.RFC3339))
+	for _, p := range out.Packages {
+		fmt.Fprintf(&result, "%s %s %s", p.Ecosystem, p.Name, cmp.Or(p.Version, "(unknown version)"))
+		if p.License != "" {
+			fmt.Fprintf(&result, " [%s]", p.License)
+		}
+		result.WriteString("\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// spdxLicense returns the concluded license of a package, or its declared
+// one, leaving out the NOASSERTION placeholder.
+func spdxLicense(concluded, declared string) string {
+	for _, l := range []string{concluded, declared} {
+		if l != "" && l != "NOASSERTION" {
+			return l
+		}
+	}
+	return ""
+}
+
+// purlType returns the type of a package URL, like npm for
+// pkg:npm/%40babel/core@7.0.0.
+func purlType(purl string) string {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return ""
+	}
+	typ, _, _ := strings.Cut(rest, "/")
+	return strings.ToLower(typ)
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listCodeScanningAlertsTool = &mcp.Tool{
+	Name:        "list-code-scanning-alerts",
+	Description: "A tool to list the code scanning alerts of a Github repository, or of every repository of an organization, with their rule, severity and location. The token needs the security_events scope",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository, or the organization to list the alerts of (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl). Omit it to list the alerts of every repository of the organization",
+			},
+			"state": {
+				Type:        "string",
+				Description: "State of the alerts to return (defaults to open)",
+				Enum:        []any{"open", "closed", "dismissed", "fixed"},
+			},
+			"severity": {
+				Type:        "string",
+				Description: "Only return alerts of this severity",
+				Enum:        []any{"critical", "high", "medium", "low", "warning", "note", "error"},
+			},
+			"tool_name": {
+				Type:        "string",
+				Description: "Only return alerts of this analysis tool (e.g., CodeQL)",
+			},
+			"ref": {
+				Type:        "string",
+				Description: "Git reference the alerts were found on, like refs/heads/main or refs/pull/42/merge (defaults to the default branch, repositories only)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of alerts to return, most recent first (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type ListCodeScanningAlertsArgs struct {
+	CommonArgs
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo,omitempty"`
+	State    string `json:"state,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	ToolName string `json:"tool_name,omitempty"`
+	Ref      string `json:"ref,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type CodeScanningAlert struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	State      string `json:"state"`
+	Rule       string `json:"rule"`
+	// Severity is the security severity of the rule, critical to low, or
+	// for rules that aren't about security its error, warning or note
+	// severity.
+	Severity    string    `json:"severity"`
+	Description string    `json:"description"`
+	Tool        string    `json:"tool"`
+	Path        string    `json:"path"`
+	Line        int       `json:"line"`
+	CreatedAt   time.Time `json:"created_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+type CodeScanningAlertsOutput struct {
+	Alerts []CodeScanningAlert `json:"alerts"`
+}
+
+type codeScanningAlert struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Rule   struct {
+		ID                    string `json:"id"`
+		Severity              string `json:"severity"`
+		SecuritySeverityLevel string `json:"security_severity_level"`
+		Description           string `json:"description"`
+	} `json:"rule"`
+	Tool struct {
+		Name string `json:"name"`
+	} `json:"tool"`
+	MostRecentInstance struct {
+		Location struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+		} `json:"location"`
+	} `json:"most_recent_instance"`
+	CreatedAt time.Time `json:"created_at"`
+	HTMLURL   string    `json:"html_url"`
+	// Repository is only set by the organization endpoint.
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (c *GithubClient) ListCodeScanningAlerts(ctx context.Context, req *mcp.CallToolRequest, args ListCodeScanningAlertsArgs) (*mcp.CallToolResult, CodeScanningAlertsOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose code scanning alerts should be listed?", ownerField(&args.Owner)); err != nil {
+		return nil, CodeScanningAlertsOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, CodeScanningAlertsOutput{}, fmt.Errorf("owner is required")
+	}
+	query := url.Values{}
+	query.Set("state", cmp.Or(args.State, "open"))
+	query.Set("sort", "created")
+	query.Set("direction", "desc")
+	query.Set("per_page", fmt.Sprint(cmp.Or(args.Limit, 30)))
+	if args.Severity != "" {
+		query.Set("severity", args.Severity)
+	}
+	if args.ToolName != "" {
+		query.Set("tool_name", args.ToolName)
+	}
+	if args.Ref != "" {
+		if args.Repo == "" {
+			return nil, CodeScanningAlertsOutput{}, fmt.Errorf("ref can only be given with a repo")
+		}
+		query.Set("ref", args.Ref)
+	}
+	apiURL, target := c.alertsURL(args.CommonArgs, args.Owner, args.Repo, "code-scanning", query)
+
+	var alerts []codeScanningAlert
+	if err := c.getJSON(ctx, apiURL, &alerts); err != nil {
+		return nil, CodeScanningAlertsOutput{}, scopeError(err, "listing the code scanning alerts of "+target, "security_events", "Code scanning alerts")
+	}
+
+	out := CodeScanningAlertsOutput{Alerts: []CodeScanningAlert{}}
+	for _, a := range alerts {
+		if !c.scope.allowsFullName(cmp.Or(a.Repository.FullName, target)) {
+			continue
+		}
+		out.Alerts = append(out.Alerts, CodeScanningAlert{
+			Repository:  cmp.Or(a.Repository.FullName, target),
+			Number:      a.Number,
+			State:       a.State,
+			Rule:        a.Rule.ID,
+			Severity:    cmp.Or(a.Rule.SecuritySeverityLevel, a.Rule.Severity),
+			Description: a.Rule.Description,
+			Tool:        a.Tool.Name,
+			Path:        a.MostRecentInstance.Location.Path,
+			Line:        a.MostRecentInstance.Location.StartLine,
+			CreatedAt:   a.CreatedAt,
+			HTMLURL:     a.HTMLURL,
+		})
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d code scanning alerts in %s:\n", len(out.Alerts), target)
+	for _, a := range out.Alerts {
+		fmt.Fprintf(&result, "%s#%d [%s] %s %s (%s) at %s:%d, %s %s\n",
+			a.Repository, a.Number, a.Severity, a.Rule, a.Description, a.Tool, a.Path, a.Line, a.State, a.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var listSecretScanningAlertsTool = &mcp.Tool{
+	Name:        "list-secret-scanning-alerts",
+	Description: "A tool to list the secret scanning alerts of a Github repository, or of every repository of an organization, with the type and state of the leaked secret but never the secret itself. The token needs the repo or security_events scope",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository, or the organization to list the alerts of (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl). Omit it to list the alerts of every repository of the organization",
+			},
+			"state": {
+				Type:        "string",
+				Description: "State of the alerts to return (defaults to open)",
+				Enum:        []any{"open", "resolved"},
+			},
+			"secret_type": {
+				Type:        "string",
+				Description: "Only return alerts of these comma separated secret types (e.g., github_personal_access_token,aws_access_key_id)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of alerts to return, most recent first (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type ListSecretScanningAlertsArgs struct {
+	CommonArgs
+	Owner      string `json:"owner"`
+	Repo       string `json:"repo,omitempty"`
+	State      string `json:"state,omitempty"`
+	SecretType string `json:"secret_type,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+type SecretScanningAlert struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	State      string `json:"state"`
+	// Resolution tells why a resolved alert was closed, like revoked or
+	// false_positive.
+	Resolution string `json:"resolution,omitempty"`
+	SecretType string `json:"secret_type"`
+	// Validity is active, inactive or unknown, when the provider of the
+	// secret lets GitHub check it.
+	Validity               string    `json:"validity,omitempty"`
+	PushProtectionBypassed bool      `json:"push_protection_bypassed"`
+	CreatedAt              time.Time `json:"created_at"`
+	HTMLURL                string    `json:"html_url"`
+}
+
+type SecretScanningAlertsOutput struct {
+	Alerts []SecretScanningAlert `json:"alerts"`
+}
+
+// secretScanningAlert leaves out the secret field of the alerts, so that
+// the leaked secrets never reach the model.
+type secretScanningAlert struct {
+	Number                 int       `json:"number"`
+	State                  string    `json:"state"`
+	Resolution             string    `json:"resolution"`
+	SecretType             string    `json:"secret_type"`
+	SecretTypeDisplayName  string    `json:"secret_type_display_name"`
+	Validity               string    `json:"validity"`
+	PushProtectionBypassed bool      `json:"push_protection_bypassed"`
+	CreatedAt              time.Time `json:"created_at"`
+	HTMLURL                string    `json:"html_url"`
+	Repository             struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (c *GithubClient) ListSecretScanningAlerts(ctx context.Context, req *mcp.CallToolRequest, args ListSecretScanningAlertsArgs) (*mcp.CallToolResult, SecretScanningAlertsOutput, error) {
+	if err := elicitMissing(ctx, req, "Whose secret scanning alerts should be listed?", ownerField(&args.Owner)); err != nil {
+		return nil, SecretScanningAlertsOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, SecretScanningAlertsOutput{}, fmt.Errorf("owner is required")
+	}
+	query := url.Values{}
+	query.Set("state", cmp.Or(args.State, "open"))
+	query.Set("sort", "created")
+	query.Set("direction", "desc")
+	query.Set("per_page", fmt.Sprint(cmp.Or(args.Limit, 30)))
+	if args.SecretType != "" {
+		query.Set("secret_type", args.SecretType)
+	}
+	apiURL, target := c.alertsURL(args.CommonArgs, args.Owner, args.Repo, "secret-scanning", query)
+
+	var alerts []secretScanningAlert
+	if err := c.getJSON(ctx, apiURL, &alerts); err != nil {
+		return nil, SecretScanningAlertsOutput{}, scopeError(err, "listing the secret scanning alerts of "+target, "repo or security_events", "Secret scanning alerts")
+	}
+
+	out := SecretScanningAlertsOutput{Alerts: []SecretScanningAlert{}}
+	for _, a := range alerts {
+		if !c.scope.allowsFullName(cmp.Or(a.Repository.FullName, target)) {
+			continue
+		}
+		out.Alerts = append(out.Alerts, SecretScanningAlert{
+			Repository:             cmp.Or(a.Repository.FullName, target),
+			Number:                 a.Number,
+			State:                  a.State,
+			Resolution:             a.Resolution,
+			SecretType:             cmp.Or(a.SecretTypeDisplayName, a.SecretType),
+			Validity:               a.Validity,
+			PushProtectionBypassed: a.PushProtectionBypassed,
+			CreatedAt:              a.CreatedAt,
+			HTMLURL:                a.HTMLURL,
+		})
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d secret scanning alerts in %s:\n", len(out.Alerts), target)
+	for _, a := range out.Alerts {
+		fmt.Fprintf(&result, "%s#%d %s, %s", a.Repository, a.Number, a.SecretType, a.State)
+		if a.Resolution != "" {
+			fmt.Fprintf(&result, " as %s", a.Resolution)
+		}
+		if a.Validity != "" {
+			fmt.Fprintf(&result, ", validity %s", a.Validity)
+		}
+		if a.PushProtectionBypassed {
+			result.WriteString(", push protection bypassed")
+		}
+		fmt.Fprintf(&result, ", found %s %s\n", a.CreatedAt.Format(time.DateOnly), a.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// alertsURL returns the URL listing the alerts of kind, like code-scanning,
+// of owner/repo, or of the organization owner without a repo, and the name
+// of what they are listed for.
+func (c *GithubClient) alertsURL(common CommonArgs, owner, repo, kind string, query url.Values) (string, string) {
+	baseURL := c.apiURL(common)
+	if repo == "" {
+		return fmt.Sprintf("%s/orgs/%s/%s/alerts?%s", baseURL, url.PathEscape(owner), kind, query.Encode()), owner
+	}
+	return fmt.Sprintf("%s/repos/%s/%s/%s/alerts?%s", baseURL, url.PathEscape(owner), url.PathEscape(repo), kind, query.Encode()), owner + "/" + repo
+}
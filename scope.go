@@ -0,0 +1,240 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// patternList is a list of owner/repo patterns, like mycorp/* or
+// */secrets-*. It can be given as a YAML list or, like on the command line,
+// as a comma separated string.
+type patternList []string
+
+func (l *patternList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *patternList) Set(s string) error {
+	*l = nil
+	for p := range strings.SplitSeq(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			*l = append(*l, p)
+		}
+	}
+	return nil
+}
+
+func (l *patternList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return l.Set(node.Value)
+	}
+	var patterns []string
+	if err := node.Decode(&patterns); err != nil {
+		return err
+	}
+	*l = patterns
+	return nil
+}
+
+// validate checks that every pattern is an owner/repo pair of path.Match
+// patterns. Owners nested in an organization, like Azure DevOps projects,
+// may be spelled out as organization/project/repo.
+func (l patternList) validate() error {
+	for _, p := range l {
+		if !strings.Contains(p, "/") || slices.Contains(strings.Split(p, "/"), "") {
+			return fmt.Errorf("invalid pattern %q, expected owner/repo", p)
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// repoScope limits the repositories the server may read or change. A
+// repository is in scope when it matches an allow pattern, or there are
+// none, and no deny pattern. Names are matched case-insensitively, as GitHub
+// does.
+type repoScope struct {
+	allow []string
+	deny  []string
+}
+
+// newRepoScope returns nil, allowing everything, when there are no patterns.
+func newRepoScope(allow, deny []string) *repoScope {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil
+	}
+	lower := func(patterns []string) []string {
+		out := make([]string, len(patterns))
+		for i, p := range patterns {
+			out[i] = strings.ToLower(p)
+		}
+		return out
+	}
+	return &repoScope{allow: lower(allow), deny: lower(deny)}
+}
+
+// allowsRepo reports whether owner/repo is in scope.
+func (s *repoScope) allowsRepo(owner, repo string) bool {
+	if s == nil {
+		return true
+	}
+	name := strings.ToLower(owner + "/" + repo)
+	matches := func(p string) bool { return matchRepo(p, name) }
+	if slices.ContainsFunc(s.deny, matches) {
+		return false
+	}
+	return len(s.allow) == 0 || slices.ContainsFunc(s.allow, matches)
+}
+
+// matchRepo reports whether pattern matches name, an owner/repo name. When
+// the owner is nested in an organization, like an Azure DevOps project or a
+// GitLab subgroup, an owner/repo pattern also matches the organization and
+// the repository alone: myorg/* matches myorg/project/repo.
+func matchRepo(pattern, name string) bool {
+	if ok, _ := path.Match(pattern, name); ok {
+		return true
+	}
+	first, last := strings.Index(name, "/"), strings.LastIndex(name, "/")
+	if strings.Count(pattern, "/") != 1 || first == last {
+		return false
+	}
+	ok, _ := path.Match(pattern, name[:first]+name[last:])
+	return ok
+}
+
+// allowsOwner reports whether the user or organization owner itself may be
+// looked at: when some of its repositories are allowed and it isn't denied
+// as a whole by an owner/* pattern.
+func (s *repoScope) allowsOwner(owner string) bool {
+	if s == nil {
+		return true
+	}
+	owner = strings.ToLower(owner)
+	org, _, _ := strings.Cut(owner, "/")
+	ownerMatches := func(wholeOwner bool) func(string) bool {
+		return func(p string) bool {
+			i := strings.LastIndex(p, "/")
+			o, repo := p[:i], p[i+1:]
+			ok, _ := path.Match(o, owner)
+			if !ok && !strings.Contains(o, "/") {
+				ok, _ = path.Match(o, org)
+			}
+			return ok && (!wholeOwner || repo == "*")
+		}
+	}
+	if slices.ContainsFunc(s.deny, ownerMatches(true)) {
+		return false
+	}
+	return len(s.allow) == 0 || slices.ContainsFunc(s.allow, ownerMatches(false))
+}
+
+// check returns an error when the API request to u is about a repository or
+// owner out of scope. Requests that aren't, like searches, are allowed.
+func (s *repoScope) check(u *url.URL) error {
+	if s == nil {
+		return nil
+	}
+	// Skip the API root, which is /api/v3 on GitHub Enterprise Server, by
+	// looking for the first segment naming a repository or an owner.
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, seg := range segments {
+		rest := segments[i+1:]
+		switch seg {
+		case "repos":
+			if len(rest) < 2 {
+				return nil
+			}
+			return s.checkRepo(rest[0], rest[1])
+		case "orgs", "users":
+			if len(rest) < 1 {
+				return nil
+			}
+			return s.checkOwner(rest[0])
+		case "projects":
+			// GitLab paths, checked by its Forge.
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkRepo returns an error when owner/repo is out of scope. Providers
+// whose API paths check doesn't understand call it before their requests.
+func (s *repoScope) checkRepo(owner, repo string) error {
+	if !s.allowsRepo(owner, repo) {
+		return fmt.Errorf("repository %s/%s is outside the repositories this server may access", owner, repo)
+	}
+	return nil
+}
+
+func (s *repoScope) checkOwner(owner string) error {
+	if !s.allowsOwner(owner) {
+		return fmt.Errorf("%s is outside the owners this server may access", owner)
+	}
+	return nil
+}
+
+// allowsFullName reports whether the repository named fullName, as in the
+// full_name the APIs return, is in scope. The owner is everything before the
+// last slash, for GitLab's nested groups.
+func (s *repoScope) allowsFullName(fullName string) bool {
+	i := strings.LastIndex(fullName, "/")
+	return s.allowsRepo(fullName[:max(i, 0)], fullName[i+1:])
+}
+
+// filterRepositories drops the repositories out of scope from repos, so
+// that listings don't reveal what the server may not access.
+func (s *repoScope) filterRepositories(repos []Repository) []Repository {
+	if s == nil {
+		return repos
+	}
+	return slices.DeleteFunc(repos, func(r Repository) bool { return !s.allowsFullName(r.FullName) })
+}
+
+// filterSearchItems drops the items of the search result body about a
+// repository out of scope. Repositories name themselves with full_name,
+// code and commits with repository.full_name, and issues with
+// repository_url.
+func (s *repoScope) filterSearchItems(body []byte) ([]byte, error) {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(result["items"], &items); err != nil || items == nil {
+		return body, nil
+	}
+	items = slices.DeleteFunc(items, func(raw json.RawMessage) bool {
+		var item struct {
+			FullName   string `json:"full_name"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			RepositoryURL string `json:"repository_url"`
+		}
+		if json.Unmarshal(raw, &item) != nil {
+			return true
+		}
+		name := cmp.Or(item.FullName, item.Repository.FullName)
+		if name == "" {
+			if _, after, ok := strings.Cut(item.RepositoryURL, "/repos/"); ok {
+				name = after
+			}
+		}
+		return !s.allowsFullName(name)
+	})
+	var err error
+	if result["items"], err = json.Marshal(items); err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
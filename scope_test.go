@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// checkScoped fails unless the text of res names o/public but not the
+// denied o/secrets-db.
+func checkScoped(t *testing.T, res *mcp.CallToolResult) {
+	t.Helper()
+	text := resultText(res)
+	if res.IsError {
+		t.Fatalf("call failed: %s", text)
+	}
+	if !strings.Contains(text, "public") {
+		t.Errorf("allowed repository missing:\n%s", text)
+	}
+	if strings.Contains(text, "secrets-db") {
+		t.Errorf("denied repository listed:\n%s", text)
+	}
+}
+
+func jsonHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+func TestScopeFiltersRepositoryListing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/o/repos", jsonHandler(`[{"name":"public","full_name":"o/public"},{"name":"secrets-db","full_name":"o/secrets-db"}]`))
+	gh := newTestClient(t, mux, GithubClientOptions{Deny: []string{"*/secrets-*"}})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+		"name": "o", "account_type": "org",
+	})
+	checkScoped(t, res)
+}
+
+func TestScopeFiltersGraphQLRepositoryListing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", jsonHandler(`{"data":{"repositoryOwner":{"repositories":{"totalCount":2,"pageInfo":{"hasNextPage":false},"nodes":[
+		{"name":"public","nameWithOwner":"o/public"},{"name":"secrets-db","nameWithOwner":"o/secrets-db"}]}}}}`))
+	gh := newTestClient(t, mux, GithubClientOptions{Deny: []string{"*/secrets-*"}, GraphQL: true})
+
+	res := callTool(t, func(s *mcp.Server) { addTool(s, listRepositoriesTool, gh.ListRepositories) }, "list-repositories", map[string]any{
+		"name": "o", "account_type": "org",
+	})
+	checkScoped(t, res)
+}
+
+func TestScopeFiltersSearchResults(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search/repositories", jsonHandler(`{"total_count":2,"items":[{"name":"public","full_name":"o/public"},{"name":"secrets-db","full_name":"o/secrets-db"}]}`))
+	mux.HandleFunc("GET /search/issues", jsonHandler(`{"total_count":2,"items":[
+		{"number":1,"title":"Leak","repository_url":"https://api.github.com/repos/o/secrets-db","html_url":"https://github.com/o/secrets-db/issues/1"},
+		{"number":2,"title":"Fine","repository_url":"https://api.github.com/repos/o/public","html_url":"https://github.com/o/public/issues/2"}]}`))
+	gh := newTestClient(t, mux, GithubClientOptions{Deny: []string{"*/secrets-*"}})
+
+	t.Run("repositories", func(t *testing.T) {
+		res := callTool(t, func(s *mcp.Server) { addTool(s, searchRepositoriesTool, gh.SearchRepositories) }, "search-repositories", map[string]any{
+			"query": "db",
+		})
+		checkScoped(t, res)
+	})
+	t.Run("issues", func(t *testing.T) {
+		res := callTool(t, func(s *mcp.Server) { addTool(s, searchIssuesTool, gh.SearchIssues) }, "search-issues-and-prs", map[string]any{
+			"query": "leak",
+		})
+		checkScoped(t, res)
+	})
+}
+
+func TestScopeNestedOwners(t *testing.T) {
+	scope := newRepoScope([]string{"myorg/*"}, []string{"*/secrets-*", "myorg/legacy/*"})
+	for _, tt := range []struct {
+		owner, repo string
+		want        bool
+	}{
+		{"myorg", "api", true},
+		{"myorg/project", "api", true},
+		{"MyOrg/Project", "API", true},
+		{"myorg/project", "secrets-db", false},
+		{"myorg/legacy", "api", false},
+		{"other/project", "api", false},
+		{"group/sub/deeper", "api", false},
+	} {
+		if got := scope.allowsRepo(tt.owner, tt.repo); got != tt.want {
+			t.Errorf("allowsRepo(%q, %q) = %v, want %v", tt.owner, tt.repo, got, tt.want)
+		}
+	}
+	for owner, want := range map[string]bool{
+		"myorg":         true,
+		"myorg/project": true,
+		"myorg/legacy":  false,
+		"other/project": false,
+	} {
+		if got := scope.allowsOwner(owner); got != want {
+			t.Errorf("allowsOwner(%q) = %v, want %v", owner, got, want)
+		}
+	}
+}
+
+func TestPatternListValidate(t *testing.T) {
+	for p, valid := range map[string]bool{
+		"mycorp/*":          true,
+		"*/secrets-*":       true,
+		"myorg/project/api": true,
+		"mycorp":            false,
+		"mycorp/":           false,
+		"/repo":             false,
+		"a//b":              false,
+		"mycorp/[":          false,
+	} {
+		if err := (patternList{p}).validate(); (err == nil) != valid {
+			t.Errorf("validate(%q) = %v, want valid %v", p, err, valid)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultSearchLimit is the number of search results returned when the
+// caller doesn't ask for a specific amount.
+const defaultSearchLimit = 30
+
+var searchCodeTool = &mcp.Tool{
+	Name:        "search-code",
+	Description: "A tool to search for code across Github repositories. Requires a GitHub token",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"query": {
+				Type:        "string",
+				Description: "Text to search for, may contain any GitHub code search qualifier (e.g., \"NewClient in:file\")",
+			},
+			"language": {
+				Type:        "string",
+				Description: "Only match files in this language (e.g., go)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Only search this repository, as owner/name (e.g., kubernetes/kubectl)",
+			},
+			"org": {
+				Type:        "string",
+				Description: "Only search repositories of this organization (e.g., kubernetes)",
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of results to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"query"},
+	},
+}
+
+type SearchCodeArgs struct {
+	CommonArgs
+	Query    string `json:"query"`
+	Language string `json:"language,omitempty"`
+	Repo     string `json:"repo,omitempty"`
+	Org      string `json:"org,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type codeSearchResult struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		Path       string `json:"path"`
+		HTMLURL    string `json:"html_url"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		TextMatches []struct {
+			Fragment string `json:"fragment"`
+		} `json:"text_matches"`
+	} `json:"items"`
+}
+
+func (c *GithubClient) SearchCode(ctx context.Context, req *mcp.CallToolRequest, args SearchCodeArgs) (*mcp.CallToolResult, any, error) {
+	err := elicitMissing(ctx, req, "What code should be searched for?",
+		elicitField{name: "query", description: "Code to search for", value: &args.Query})
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Query == "" {
+		return nil, nil, fmt.Errorf("query is required")
+	}
+	q := qualify(args.Query, "language", args.Language)
+	q = qualify(q, "repo", args.Repo)
+	q = qualify(q, "org", args.Org)
+	query := searchQuery(q, "", "", args.Limit)
+
+	// Ask for the matching fragments along with the file locations.
+	var found codeSearchResult
+	if err := c.search(ctx, c.apiURL(args.CommonArgs), "code", query, "application/vnd.github.text-match+json", &found); err != nil {
+		return nil, nil, err
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d results for %q, showing %d:\n", found.TotalCount, q, len(found.Items))
+	for _, item := range found.Items {
+		fmt.Fprintf(&result, "\n%s: %s (%s)\n", item.Repository.FullName, item.Path, item.HTMLURL)
+		for _, m := range item.TextMatches {
+			fmt.Fprintf(&result, "---\n%s\n", m.Fragment)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, nil, nil
+}
+
+var searchRepositoriesTool = &mcp.Tool{
+	Name:        "search-repositories",
+	Description: "A tool to search for Github repositories",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"query": {
+				Type:        "string",
+				Description: "Free text to search for, may contain any GitHub repository search qualifier (e.g., \"service mesh in:description\")",
+			},
+			"language": {
+				Type:        "string",
+				Description: "Only match repositories whose primary language is this (e.g., go)",
+			},
+			"stars": {
+				Type:        "string",
+				Description: "Only match repositories with this many stars (e.g., >1000 or 10..50)",
+			},
+			"org": {
+				Type:        "string",
+				Description: "Only match repositories of this organization (e.g., kubernetes)",
+			},
+			"topic": {
+				Type:        "string",
+				Description: "Only match repositories tagged with this topic (e.g., cli)",
+			},
+			"pushed": {
+				Type:        "string",
+				Description: "Only match repositories pushed to in this date range (e.g., >2024-01-01)",
+			},
+			"sort": {
+				Type:        "string",
+				Description: "Sort field (defaults to best match)",
+				Enum:        []any{"stars", "forks", "help-wanted-issues", "updated"},
+			},
+			"order": {
+				Type:        "string",
+				Description: "Sort order (defaults to desc)",
+				Enum:        []any{"asc", "desc"},
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of results to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type SearchRepositoriesArgs struct {
+	CommonArgs
+	Query    string `json:"query,omitempty"`
+	Language string `json:"language,omitempty"`
+	Stars    string `json:"stars,omitempty"`
+	Org      string `json:"org,omitempty"`
+	Topic    string `json:"topic,omitempty"`
+	Pushed   string `json:"pushed,omitempty"`
+	Sort     string `json:"sort,omitempty"`
+	Order    string `json:"order,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type RepositorySearchResult struct {
+	FullName    string    `json:"full_name"`
+	Description string    `json:"description"`
+	HTMLURL     string    `json:"html_url"`
+	Language    string    `json:"language"`
+	Stars       int       `json:"stargazers_count"`
+	Forks       int       `json:"forks_count"`
+	Topics      []string  `json:"topics"`
+	Archived    bool      `json:"archived"`
+	PushedAt    time.Time `json:"pushed_at"`
+}
+
+type RepositorySearchOutput struct {
+	Query        string                   `json:"query"`
+	TotalCount   int                      `json:"total_count"`
+	Repositories []RepositorySearchResult `json:"repositories"`
+}
+
+func (c *GithubClient) SearchRepositories(ctx context.Context, req *mcp.CallToolRequest, args SearchRepositoriesArgs) (*mcp.CallToolResult, RepositorySearchOutput, error) {
+	q := qualify(args.Query, "language", args.Language)
+	q = qualify(q, "stars", args.Stars)
+	q = qualify(q, "org", args.Org)
+	q = qualify(q, "topic", args.Topic)
+	q = qualify(q, "pushed", args.Pushed)
+	if q == "" {
+		return nil, RepositorySearchOutput{}, fmt.Errorf("a query or at least one qualifier is required")
+	}
+
+	var found struct {
+		TotalCount int                      `json:"total_count"`
+		Items      []RepositorySearchResult `json:"items"`
+	}
+	if err := c.search(ctx, c.apiURL(args.CommonArgs), "repositories", searchQuery(q, args.Sort, args.Order, args.Limit), "", &found); err != nil {
+		return nil, RepositorySearchOutput{}, err
+	}
+	out := RepositorySearchOutput{
+		Query:        q,
+		TotalCount:   found.TotalCount,
+		Repositories: found.Items,
+	}
+	if out.Repositories == nil {
+		out.Repositories = []RepositorySearchResult{}
+	}
+	discovered := map[string]string{}
+	for _, repo := range out.Repositories {
+		discovered[repo.FullName] = repo.Description
+	}
+	c.addRepositoryResources(args.CommonArgs, discovered)
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d repositories for %q, showing %d:\n", out.TotalCount, q, len(out.Repositories))
+	for _, repo := range out.Repositories {
+		archived := ""
+		if repo.Archived {
+			archived = " (archived)"
+		}
+		fmt.Fprintf(&result, "%s%s [%s, %d stars] %s %s\n", repo.FullName, archived, repo.Language, repo.Stars, repo.Description, repo.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// qualify appends a "key:value" search qualifier to q, unless value is empty.
+func qualify(q, key, value string) string {
+	if value == "" {
+		return q
+	}
+	return strings.TrimSpace(q + " " + key + ":" + value)
+}
+
+// searchQuery builds the query string of a search API request.
+func searchQuery(q, sort, order string, limit int) url.Values {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	query := url.Values{}
+	query.Set("q", q)
+	query.Set("per_page", strconv.Itoa(limit))
+	if sort != "" {
+		query.Set("sort", sort)
+	}
+	if order != "" {
+		query.Set("order", order)
+	}
+	return query
+}
+
+// search calls the search API for kind (code, repositories, issues...) and
+// decodes the result into v. A non-empty accept overrides the media type.
+func (c *GithubClient) search(ctx context.Context, baseURL, kind string, query url.Values, accept string, v any) error {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("%s/search/%s?%s", baseURL, kind, query.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	if c.scope == nil {
+		return decodeJSON(resp, v)
+	}
+	// Searches span every repository, so drop the results out of scope
+	// before anything reads them.
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if body, err = c.scope.filterSearchItems(body); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+var searchIssuesTool = &mcp.Tool{
+	Name:        "search-issues-and-prs",
+	Description: "A tool to search for issues and pull requests across Github using the GitHub search syntax",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"query": {
+				Type:        "string",
+				Description: "GitHub issue search query (e.g., \"is:open label:bug org:kubernetes\")",
+			},
+			"sort": {
+				Type:        "string",
+				Description: "Sort field (defaults to best match)",
+				Enum:        []any{"comments", "reactions", "created", "updated"},
+			},
+			"order": {
+				Type:        "string",
+				Description: "Sort order (defaults to desc)",
+				Enum:        []any{"asc", "desc"},
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of results to return (defaults to 30)",
+				Minimum:     jsonschema.Ptr(1.0),
+				Maximum:     jsonschema.Ptr(100.0),
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"query"},
+	},
+}
+
+type SearchIssuesArgs struct {
+	CommonArgs
+	Query string `json:"query"`
+	Sort  string `json:"sort,omitempty"`
+	Order string `json:"order,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type IssueSearchResult struct {
+	Repository    string    `json:"repository"`
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	State         string    `json:"state"`
+	IsPullRequest bool      `json:"is_pull_request"`
+	Author        string    `json:"author"`
+	Labels        []string  `json:"labels"`
+	Comments      int       `json:"comments"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	HTMLURL       string    `json:"html_url"`
+}
+
+type IssueSearchOutput struct {
+	Query      string              `json:"query"`
+	TotalCount int                 `json:"total_count"`
+	Items      []IssueSearchResult `json:"items"`
+}
+
+type issueSearchItem struct {
+	issue
+	RepositoryURL string    `json:"repository_url"`
+	Comments      int       `json:"comments"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	User          struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (c *GithubClient) SearchIssues(ctx context.Context, req *mcp.CallToolRequest, args SearchIssuesArgs) (*mcp.CallToolResult, IssueSearchOutput, error) {
+	err := elicitMissing(ctx, req, "What issues or pull requests should be searched for?",
+		elicitField{name: "query", description: "Search terms", value: &args.Query})
+	if err != nil {
+		return nil, IssueSearchOutput{}, err
+	}
+	if args.Query == "" {
+		return nil, IssueSearchOutput{}, fmt.Errorf("query is required")
+	}
+	var found struct {
+		TotalCount int               `json:"total_count"`
+		Items      []issueSearchItem `json:"items"`
+	}
+	if err := c.search(ctx, c.apiURL(args.CommonArgs), "issues", searchQuery(args.Query, args.Sort, args.Order, args.Limit), "", &found); err != nil {
+		return nil, IssueSearchOutput{}, err
+	}
+	out := IssueSearchOutput{
+		Query:      args.Query,
+		TotalCount: found.TotalCount,
+		Items:      []IssueSearchResult{},
+	}
+	for _, item := range found.Items {
+		out.Items = append(out.Items, IssueSearchResult{
+			// repository_url is https://api.github.com/repos/{owner}/{repo}.
+			Repository:    repoFromAPIURL(item.RepositoryURL),
+			Number:        item.Number,
+			Title:         item.Title,
+			State:         item.State,
+			IsPullRequest: item.PullRequest != nil,
+			Author:        item.User.Login,
+			Labels:        item.labelNames(),
+			Comments:      item.Comments,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.UpdatedAt,
+			HTMLURL:       item.HTMLURL,
+		})
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d issues and pull requests for %q, showing %d:\n", out.TotalCount, args.Query, len(out.Items))
+	for _, item := range out.Items {
+		kind := "issue"
+		if item.IsPullRequest {
+			kind = "pull request"
+		}
+		fmt.Fprintf(&result, "%s#%d (%s) [%s] %s by %s (labels: %s) %s\n",
+			item.Repository, item.Number, kind, item.State, item.Title, item.Author, strings.Join(item.Labels, ", "), item.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+// repoFromAPIURL returns the owner/repo part of a repository API URL.
+func repoFromAPIURL(apiURL string) string {
+	_, repo, ok := strings.Cut(apiURL, "/repos/")
+	if !ok {
+		return apiURL
+	}
+	return repo
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var findStaleTool = &mcp.Tool{
+	Name:        "find-stale",
+	Description: "A tool to find the open issues and pull requests of a Github repository, or of every repository of an organization, without activity for a number of days, most stale first",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository, or the organization or user to search (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl). Omit it to search every repository of the owner",
+			},
+			"days": {
+				Type:        "integer",
+				Description: "Number of days without activity after which an issue or pull request is stale (defaults to 90)",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"kind": {
+				Type:        "string",
+				Description: "Whether to find issues, pull requests or both (defaults to all)",
+				Enum:        []any{"issue", "pr", "all"},
+			},
+			"labels": {
+				Type:        "array",
+				Description: "Only return issues and pull requests with all these labels",
+				Items:       &jsonschema.Schema{Type: "string"},
+			},
+			"assignee": {
+				Type:        "string",
+				Description: "Only return issues and pull requests assigned to this user, \"none\" for unassigned ones",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner"},
+	},
+}
+
+type FindStaleArgs struct {
+	CommonArgs
+	Owner    string   `json:"owner"`
+	Repo     string   `json:"repo,omitempty"`
+	Days     int      `json:"days,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	Assignee string   `json:"assignee,omitempty"`
+}
+
+type StaleItem struct {
+	Repository    string    `json:"repository"`
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	IsPullRequest bool      `json:"is_pull_request"`
+	Author        string    `json:"author"`
+	Labels        []string  `json:"labels"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	DaysStale     int       `json:"days_stale"`
+	HTMLURL       string    `json:"html_url"`
+}
+
+type StaleOutput struct {
+	Query      string      `json:"query"`
+	TotalCount int         `json:"total_count"`
+	Items      []StaleItem `json:"items"`
+}
+
+func (c *GithubClient) FindStale(ctx context.Context, req *mcp.CallToolRequest, args FindStaleArgs) (*mcp.CallToolResult, StaleOutput, error) {
+	if err := elicitMissing(ctx, req, "Where should stale issues and pull requests be looked for?", ownerField(&args.Owner)); err != nil {
+		return nil, StaleOutput{}, err
+	}
+	if args.Owner == "" {
+		return nil, StaleOutput{}, fmt.Errorf("owner is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, 0, -cmp.Or(args.Days, 90))
+
+	terms := []string{"is:open", "updated:<" + cutoff.Format(time.DateOnly), "sort:updated-asc"}
+	if args.Repo != "" {
+		terms = append(terms, "repo:"+args.Owner+"/"+args.Repo)
+	} else {
+		accountType, err := c.accountType(ctx, baseURL, args.Owner)
+		if err != nil {
+			return nil, StaleOutput{}, err
+		}
+		terms = append(terms, map[string]string{"org": "org:", "user": "user:"}[accountType]+args.Owner)
+	}
+	switch args.Kind {
+	case "issue":
+		terms = append(terms, "is:issue")
+	case "pr":
+		terms = append(terms, "is:pr")
+	}
+	for _, l := range args.Labels {
+		terms = append(terms, fmt.Sprintf("label:%q", l))
+	}
+	switch args.Assignee {
+	case "":
+	case "none":
+		terms = append(terms, "no:assignee")
+	default:
+		terms = append(terms, "assignee:"+args.Assignee)
+	}
+	query := strings.Join(terms, " ")
+
+	found, total, err := c.searchAllIssues(ctx, baseURL, query)
+	if err != nil {
+		return nil, StaleOutput{}, err
+	}
+	out := StaleOutput{Query: query, TotalCount: total, Items: []StaleItem{}}
+	for _, item := range found {
+		out.Items = append(out.Items, StaleItem{
+			Repository:    repoFromAPIURL(item.RepositoryURL),
+			Number:        item.Number,
+			Title:         item.Title,
+			IsPullRequest: item.PullRequest != nil,
+			Author:        item.User.Login,
+			Labels:        item.labelNames(),
+			UpdatedAt:     item.UpdatedAt,
+			DaysStale:     int(now.Sub(item.UpdatedAt).Hours() / 24),
+			HTMLURL:       item.HTMLURL,
+		})
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d issues and pull requests without activity since %s, showing %d:\n", out.TotalCount, cutoff.Format(time.DateOnly), len(out.Items))
+	for _, item := range out.Items {
+		kind := "issue"
+		if item.IsPullRequest {
+			kind = "pull request"
+		}
+		fmt.Fprintf(&result, "%s#%d (%s) %s by %s, %d days stale (labels: %s) %s\n",
+			item.Repository, item.Number, kind, item.Title, item.Author, item.DaysStale, strings.Join(item.Labels, ", "), item.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Clients can subscribe to github://{owner}/{repo} resources. A background
+// loop polls the events of every subscribed repository and sends
+// notifications/resources/updated when a push moves the head of its default
+// branch. Polls bypass the response cache, but are revalidated with their
+// ETag, so an idle repository doesn't use up the rate limit. Sessions closed
+// without unsubscribing are dropped before each round of polls, and with them
+// the repositories no session is subscribed to anymore.
+
+// subscription is the state kept for a subscribed repository.
+type subscription struct {
+	owner, repo string
+	// sessions are the client sessions subscribed to the repository.
+	sessions map[*mcp.ServerSession]bool
+	// defaultBranch and head are looked up on the first poll.
+	polled        bool
+	defaultBranch string
+	head          string
+}
+
+// repoEvent is the part of an event of the events API needed to spot pushes.
+type repoEvent struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Ref  string `json:"ref"`
+		Head string `json:"head"`
+	} `json:"payload"`
+}
+
+// subscriptionOptions returns the server options handling resource
+// subscriptions.
+func (c *GithubClient) subscriptionOptions() *mcp.ServerOptions {
+	return &mcp.ServerOptions{
+		SubscribeHandler:   c.subscribe,
+		UnsubscribeHandler: c.unsubscribe,
+	}
+}
+
+func (c *GithubClient) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	owner, repo, ok := parseRepoResourceURI(uri)
+	if !ok || !c.scope.allowsRepo(owner, repo) {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	if c.subs == nil {
+		c.subs = map[string]*subscription{}
+	}
+	sub := c.subs[uri]
+	if sub == nil {
+		sub = &subscription{owner: owner, repo: repo, sessions: map[*mcp.ServerSession]bool{}}
+		c.subs[uri] = sub
+	}
+	sub.sessions[req.Session] = true
+	return nil
+}
+
+func (c *GithubClient) unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	if sub := c.subs[req.Params.URI]; sub != nil {
+		delete(sub.sessions, req.Session)
+		if len(sub.sessions) == 0 {
+			delete(c.subs, req.Params.URI)
+		}
+	}
+	return nil
+}
+
+// watchSubscriptions polls the subscribed repositories every interval until
+// ctx is done.
+func (c *GithubClient) watchSubscriptions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.pruneSubscriptions()
+		c.subsMu.Lock()
+		subs := make(map[string]*subscription, len(c.subs))
+		for uri, sub := range c.subs {
+			subs[uri] = sub
+		}
+		c.subsMu.Unlock()
+
+		for uri, sub := range subs {
+			changed, err := c.pollSubscription(ctx, sub)
+			if err != nil {
+				slog.WarnContext(ctx, "Polling subscribed repository failed", "uri", uri, "err", err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			slog.InfoContext(ctx, "Default branch moved", "repo", sub.owner+"/"+sub.repo, "head", sub.head)
+			if err := c.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+				slog.WarnContext(ctx, "Notifying resource update failed", "uri", uri, "err", err)
+			}
+		}
+	}
+}
+
+// pruneSubscriptions drops the sessions of the subscriptions that are
+// closed, and the subscriptions left without sessions.
+func (c *GithubClient) pruneSubscriptions() {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+	// The sessions are listed with subsMu held, so they include every
+	// session that subscribed so far and is still open.
+	open := map[*mcp.ServerSession]bool{}
+	for ss := range c.server.Sessions() {
+		open[ss] = true
+	}
+	for uri, sub := range c.subs {
+		for ss := range sub.sessions {
+			if !open[ss] {
+				delete(sub.sessions, ss)
+			}
+		}
+		if len(sub.sessions) == 0 {
+			delete(c.subs, uri)
+		}
+	}
+}
+
+// pollSubscription reports whether the default branch of the repository of
+// sub has a new head since the last poll. The first poll only records it.
+func (c *GithubClient) pollSubscription(ctx context.Context, sub *subscription) (bool, error) {
+	ctx = context.WithValue(ctx, commonArgsKey{}, CommonArgs{NoCache: true})
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, url.PathEscape(sub.owner), url.PathEscape(sub.repo))
+	if sub.defaultBranch == "" {
+		var details repositoryDetails
+		if err := c.getJSON(ctx, repoURL, &details); err != nil {
+			return false, err
+		}
+		sub.defaultBranch = details.DefaultBranch
+	}
+
+	var events []repoEvent
+	if err := c.getJSON(ctx, repoURL+"/events?per_page=100", &events); err != nil {
+		return false, err
+	}
+	head := sub.head
+	for _, e := range events {
+		if e.Type == "PushEvent" && e.Payload.Ref == "refs/heads/"+sub.defaultBranch {
+			head = e.Payload.Head
+			break
+		}
+	}
+	changed := sub.polled && head != sub.head
+	sub.polled, sub.head = true, head
+	return changed, nil
+}
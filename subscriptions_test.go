@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestClosedSessionsLeaveSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	gh := newTestClient(t, http.NotFoundHandler(), GithubClientOptions{})
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0"}, gh.subscriptionOptions())
+	gh.registerResources(server)
+	connect := func(uris ...string) (*mcp.ServerSession, *mcp.ClientSession) {
+		t.Helper()
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		ss, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { ss.Close() })
+		// Clients of the protocol versions before 2026-07-28 subscribe with
+		// resources/subscribe, which lasts until resources/unsubscribe.
+		cs, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0"}, nil).Connect(ctx, clientTransport,
+			&mcp.ClientSessionOptions{ProtocolVersion: "2025-11-25"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		for _, uri := range uris {
+			if err := cs.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return ss, cs
+	}
+	gone, goneClient := connect("github://o/r", "github://o/other")
+	kept, _ := connect("github://o/r")
+
+	// The client goes away without unsubscribing.
+	goneClient.Close()
+	gone.Wait()
+	gh.pruneSubscriptions()
+
+	gh.subsMu.Lock()
+	defer gh.subsMu.Unlock()
+	if sub := gh.subs["github://o/r"]; sub == nil || len(sub.sessions) != 1 || !sub.sessions[kept] {
+		t.Errorf("subscription of o/r = %+v, want only the open session", sub)
+	}
+	if sub := gh.subs["github://o/other"]; sub != nil {
+		t.Errorf("subscription of o/other = %+v, want it dropped with its only session", sub)
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var listOrgMembersTool = &mcp.Tool{
+	Name:        "list-org-members",
+	Description: "A tool to list the members of a Github organization and whether their membership is public. Only the public members are visible to tokens of users outside the organization",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"org": {
+				Type:        "string",
+				Description: "Organization whose members are listed (e.g., kubernetes)",
+			},
+			"role": {
+				Type:        "string",
+				Description: "Only list the members with this role (defaults to all)",
+				Enum:        []any{"all", "admin", "member"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"org"},
+	},
+}
+
+type ListOrgMembersArgs struct {
+	CommonArgs
+	Org  string `json:"org"`
+	Role string `json:"role,omitempty"`
+}
+
+type OrgMember struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+	// Public is set when the member made their membership public.
+	Public bool `json:"public"`
+}
+
+type OrgMembersOutput struct {
+	Org     string      `json:"org"`
+	Members []OrgMember `json:"members"`
+	// Concealed is set when members keeping their membership private are
+	// listed, which only organization members can see.
+	Concealed bool `json:"concealed"`
+}
+
+type orgMember struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (c *GithubClient) ListOrgMembers(ctx context.Context, req *mcp.CallToolRequest, args ListOrgMembersArgs) (*mcp.CallToolResult, OrgMembersOutput, error) {
+	err := elicitMissing(ctx, req, "Which organization's members should be listed?",
+		elicitField{name: "org", description: "Organization (e.g., kubernetes)", value: &args.Org})
+	if err != nil {
+		return nil, OrgMembersOutput{}, err
+	}
+	if args.Org == "" {
+		return nil, OrgMembersOutput{}, fmt.Errorf("org is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	orgURL := fmt.Sprintf("%s/orgs/%s", baseURL, url.PathEscape(args.Org))
+
+	// GitHub silently drops the concealed members from the members of an
+	// organization the token's user isn't in, the public members tell them
+	// apart.
+	var members, public []orgMember
+	var membersErr, publicErr error
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		members, membersErr = getAllPages[orgMember](ctx, c, fmt.Sprintf("%s/members?role=%s&per_page=%d", orgURL, cmp.Or(args.Role, "all"), c.perPage))
+	})
+	wg.Go(func() {
+		public, publicErr = getAllPages[orgMember](ctx, c, fmt.Sprintf("%s/public_members?per_page=%d", orgURL, c.perPage))
+	})
+	wg.Wait()
+	if err := errors.Join(membersErr, publicErr); err != nil {
+		return nil, OrgMembersOutput{}, err
+	}
+
+	out := OrgMembersOutput{Org: args.Org, Members: []OrgMember{}}
+	for _, m := range members {
+		isPublic := slices.ContainsFunc(public, func(p orgMember) bool { return p.Login == m.Login })
+		out.Concealed = out.Concealed || !isPublic
+		out.Members = append(out.Members, OrgMember{Login: m.Login, HTMLURL: m.HTMLURL, Public: isPublic})
+	}
+	slices.SortFunc(out.Members, func(a, b OrgMember) int {
+		return strings.Compare(strings.ToLower(a.Login), strings.ToLower(b.Login))
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d members of %s", len(out.Members), args.Org)
+	if args.Role != "" && args.Role != "all" {
+		fmt.Fprintf(&result, " with the %s role", args.Role)
+	}
+	result.WriteString(":\n")
+	if !out.Concealed {
+		result.WriteString("Every member listed is public, members concealing their membership are only visible to organization members\n")
+	}
+	for _, m := range out.Members {
+		visibility := ""
+		if !m.Public {
+			visibility = " (private)"
+		}
+		fmt.Fprintf(&result, "%s%s %s\n", m.Login, visibility, m.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var listTeamsTool = &mcp.Tool{
+	Name:        "list-teams",
+	Description: "A tool to list the teams of a Github organization, or the teams with access to one of its repositories and their permission, to tell which team owns a repository. Secret teams are only visible to organization members",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"org": {
+				Type:        "string",
+				Description: "Organization whose teams are listed (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Only list the teams with access to this repository of the organization (e.g., kubectl)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"org"},
+	},
+}
+
+type ListTeamsArgs struct {
+	CommonArgs
+	Org  string `json:"org"`
+	Repo string `json:"repo,omitempty"`
+}
+
+type Team struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	// Privacy is closed for teams visible to all organization members, or
+	// secret.
+	Privacy string `json:"privacy"`
+	Parent  string `json:"parent,omitempty"`
+	// Permission is the team's permission on the repository, when listing
+	// the teams of one.
+	Permission string `json:"permission,omitempty"`
+	HTMLURL    string `json:"html_url"`
+}
+
+type TeamsOutput struct {
+	Org   string `json:"org"`
+	Repo  string `json:"repo,omitempty"`
+	Teams []Team `json:"teams"`
+}
+
+func (c *GithubClient) ListTeams(ctx context.Context, req *mcp.CallToolRequest, args ListTeamsArgs) (*mcp.CallToolResult, TeamsOutput, error) {
+	err := elicitMissing(ctx, req, "Which organization's teams should be listed?",
+		elicitField{name: "org", description: "Organization (e.g., kubernetes)", value: &args.Org})
+	if err != nil {
+		return nil, TeamsOutput{}, err
+	}
+	if args.Org == "" {
+		return nil, TeamsOutput{}, fmt.Errorf("org is required")
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	apiURL := fmt.Sprintf("%s/orgs/%s/teams?per_page=%d", baseURL, url.PathEscape(args.Org), c.perPage)
+	target := args.Org
+	if args.Repo != "" {
+		apiURL = fmt.Sprintf("%s/repos/%s/%s/teams?per_page=%d", baseURL, url.PathEscape(args.Org), url.PathEscape(args.Repo), c.perPage)
+		target = args.Org + "/" + args.Repo
+	}
+	teams, err := getAllPages[struct {
+		Name        string `json:"name"`
+		Slug        string `json:"slug"`
+		Description string `json:"description"`
+		Privacy     string `json:"privacy"`
+		Permission  string `json:"permission"`
+		HTMLURL     string `json:"html_url"`
+		Parent      *struct {
+			Slug string `json:"slug"`
+		} `json:"parent"`
+	}](ctx, c, apiURL)
+	if err != nil {
+		return nil, TeamsOutput{}, scopeError(err, "listing the teams of "+target, "read:org", "Members")
+	}
+
+	out := TeamsOutput{Org: args.Org, Repo: args.Repo, Teams: []Team{}}
+	for _, t := range teams {
+		team := Team{Name: t.Name, Slug: t.Slug, Description: t.Description, Privacy: t.Privacy, HTMLURL: t.HTMLURL}
+		if t.Parent != nil {
+			team.Parent = t.Parent.Slug
+		}
+		if args.Repo != "" {
+			team.Permission = t.Permission
+		}
+		out.Teams = append(out.Teams, team)
+	}
+	slices.SortFunc(out.Teams, func(a, b Team) int {
+		return strings.Compare(a.Slug, b.Slug)
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d teams of %s:\n", len(out.Teams), target)
+	for _, t := range out.Teams {
+		fmt.Fprintf(&result, "%s (%s", t.Slug, t.Privacy)
+		if t.Permission != "" {
+			fmt.Fprintf(&result, ", %s permission", t.Permission)
+		}
+		if t.Parent != "" {
+			fmt.Fprintf(&result, ", child of %s", t.Parent)
+		}
+		fmt.Fprintf(&result, "): %s %s\n", cmp.Or(t.Description, t.Name), t.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
+
+var listTeamReposTool = &mcp.Tool{
+	Name:        "list-team-repos",
+	Description: "A tool to list the repositories a team of a Github organization has access to, with the team's permission on each",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"org": {
+				Type:        "string",
+				Description: "Organization of the team (e.g., kubernetes)",
+			},
+			"team": {
+				Type:        "string",
+				Description: "Slug of the team, as listed by list-teams (e.g., sig-cli)",
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"org", "team"},
+	},
+}
+
+type ListTeamReposArgs struct {
+	CommonArgs
+	Org  string `json:"org"`
+	Team string `json:"team"`
+}
+
+type TeamRepository struct {
+	FullName   string `json:"full_name"`
+	Permission string `json:"permission"`
+	Private    bool   `json:"private"`
+	Archived   bool   `json:"archived"`
+	HTMLURL    string `json:"html_url"`
+}
+
+type TeamReposOutput struct {
+	Org          string           `json:"org"`
+	Team         string           `json:"team"`
+	Repositories []TeamRepository `json:"repositories"`
+}
+
+func (c *GithubClient) ListTeamRepos(ctx context.Context, req *mcp.CallToolRequest, args ListTeamReposArgs) (*mcp.CallToolResult, TeamReposOutput, error) {
+	err := elicitMissing(ctx, req, "Which team's repositories should be listed?",
+		elicitField{name: "org", description: "Organization (e.g., kubernetes)", value: &args.Org},
+		elicitField{name: "team", description: "Slug of the team (e.g., sig-cli)", value: &args.Team})
+	if err != nil {
+		return nil, TeamReposOutput{}, err
+	}
+	if args.Org == "" || args.Team == "" {
+		return nil, TeamReposOutput{}, fmt.Errorf("org and team are required")
+	}
+	repos, err := getAllPages[struct {
+		FullName    string          `json:"full_name"`
+		RoleName    string          `json:"role_name"`
+		Private     bool            `json:"private"`
+		Archived    bool            `json:"archived"`
+		HTMLURL     string          `json:"html_url"`
+		Permissions map[string]bool `json:"permissions"`
+	}](ctx, c, fmt.Sprintf("%s/orgs/%s/teams/%s/repos?per_page=%d", c.apiURL(args.CommonArgs), url.PathEscape(args.Org), url.PathEscape(args.Team), c.perPage))
+	if err != nil {
+		return nil, TeamReposOutput{}, scopeError(err, "listing the repositories of team "+args.Org+"/"+args.Team, "read:org", "Members")
+	}
+
+	out := TeamReposOutput{Org: args.Org, Team: args.Team, Repositories: []TeamRepository{}}
+	for _, r := range repos {
+		if !c.scope.allowsFullName(r.FullName) {
+			continue
+		}
+		// Older GitHub Enterprise servers don't report role_name, the
+		// highest of the permissions is the role then.
+		permission := r.RoleName
+		for _, p := range []string{"admin", "maintain", "push", "triage", "pull"} {
+			if permission == "" && r.Permissions[p] {
+				permission = p
+			}
+		}
+		out.Repositories = append(out.Repositories, TeamRepository{
+			FullName:   r.FullName,
+			Permission: permission,
+			Private:    r.Private,
+			Archived:   r.Archived,
+			HTMLURL:    r.HTMLURL,
+		})
+	}
+	slices.SortFunc(out.Repositories, func(a, b TeamRepository) int {
+		return strings.Compare(a.FullName, b.FullName)
+	})
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%d repositories of team %s/%s:\n", len(out.Repositories), args.Org, args.Team)
+	for _, r := range out.Repositories {
+		var flags []string
+		if r.Private {
+			flags = append(flags, "private")
+		}
+		if r.Archived {
+			flags = append(flags, "archived")
+		}
+		fmt.Fprintf(&result, "%s: %s", r.FullName, r.Permission)
+		if len(flags) > 0 {
+			fmt.Fprintf(&result, " (%s)", strings.Join(flags, ", "))
+		}
+		fmt.Fprintf(&result, " %s\n", r.HTMLURL)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// shutdownTimeout bounds how long the spans still queued at exit may take
+// to be exported.
+const shutdownTimeout = 5 * time.Second
+
+// tracer records spans for the tool calls and the requests they send to
+// GitHub with the OpenTelemetry SDK. A nil tracer records nothing, so it can
+// be used whether tracing is enabled or not.
+type tracer struct {
+	provider   *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// spanTags are copied from a tool call to the spans started within it, so
+// the GitHub requests can be told apart by the tool calls sending them.
+type spanTags struct {
+	tool, org string
+}
+
+type spanTagsKey struct{}
+
+func (t spanTags) attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if t.tool != "" {
+		attrs = append(attrs, attribute.String("magnet.tool", t.tool))
+	}
+	if t.org != "" {
+		attrs = append(attrs, attribute.String("magnet.org", t.org))
+	}
+	return attrs
+}
+
+// newTracerFromEnv configures a tracer with the standard OpenTelemetry
+// environment variables. It returns nil, disabling tracing, unless an OTLP
+// endpoint is set, and an error when the exporter or protocol asked for isn't
+// one it has.
+func newTracerFromEnv() (*tracer, error) {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil, nil
+	}
+	switch exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter {
+	case "", "otlp":
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported traces exporter %q, only otlp is supported", exporter)
+	}
+	endpoint := cmp.Or(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"), os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	// The exporters read the endpoint, headers, timeout and TLS settings
+	// from the environment themselves.
+	ctx := context.Background()
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch protocol := cmp.Or(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"), os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"), "http/protobuf"); protocol {
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	case "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		// The Go SDK has no http/json exporter.
+		return nil, fmt.Errorf("unsupported OTLP protocol %q, only grpc and http/protobuf are supported", protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the name.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "magnet")),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		slog.Warn("Invalid OpenTelemetry resource attributes", "err", err)
+	}
+	slog.Info("Exporting traces", "endpoint", endpoint)
+	return newTracer(sdktrace.NewBatchSpanProcessor(exporter), res), nil
+}
+
+// newTracer returns a tracer handing its spans to processor. The sampler is
+// the one of OTEL_TRACES_SAMPLER, parent based and always on by default.
+func newTracer(processor sdktrace.SpanProcessor, res *resource.Resource) *tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+	)
+	return &tracer{
+		provider:   provider,
+		tracer:     provider.Tracer("github.com/alwindoss/magnet"),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+}
+
+// shutdown exports the spans still queued.
+func (t *tracer) shutdown() {
+	if t == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		slog.Warn("Exporting spans failed", "err", err)
+	}
+}
+
+// remoteContext returns ctx with the trace of the caller: the W3C trace
+// context in the _meta of call, or else in the headers of its HTTP request.
+func (t *tracer) remoteContext(ctx context.Context, call *mcp.CallToolRequest) context.Context {
+	carrier := propagation.MapCarrier{}
+	for _, key := range t.propagator.Fields() {
+		if v, ok := call.Params.Meta[key].(string); ok {
+			carrier[key] = v
+		}
+	}
+	if carrier.Get("traceparent") != "" {
+		return t.propagator.Extract(ctx, carrier)
+	}
+	if call.Extra != nil && call.Extra.Header != nil {
+		return t.propagator.Extract(ctx, propagation.HeaderCarrier(call.Extra.Header))
+	}
+	return ctx
+}
+
+// middleware traces the tool calls.
+func (t *tracer) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if t == nil || !ok {
+			return next(ctx, method, req)
+		}
+		tags := spanTags{tool: call.Params.Name}
+		var args struct {
+			Owner string `json:"owner"`
+			Org   string `json:"org"`
+			Name  string `json:"name"`
+		}
+		if json.Unmarshal(call.Params.Arguments, &args) == nil {
+			tags.org = cmp.Or(args.Owner, args.Org, args.Name)
+		}
+		ctx = context.WithValue(t.remoteContext(ctx, call), spanTagsKey{}, tags)
+		ctx, span := t.tracer.Start(ctx, "tools/call "+call.Params.Name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(tags.attributes()...),
+			trace.WithAttributes(
+				attribute.String("mcp.method.name", method),
+				attribute.String("gen_ai.tool.name", call.Params.Name),
+			),
+		)
+		defer span.End()
+		if call.Session != nil && call.Session.ID() != "" {
+			span.SetAttributes(attribute.String("mcp.session.id", call.Session.ID()))
+		}
+
+		ctx, tc := withToolCall(ctx)
+		res, err := next(ctx, method, req)
+		callErr, failed := err, err != nil
+		if r, ok := res.(*mcp.CallToolResult); ok && r.IsError && err == nil {
+			callErr, failed = tc.err, true
+		}
+		switch {
+		case callErr != nil:
+			span.SetStatus(codes.Error, callErr.Error())
+			span.SetAttributes(attribute.String("error.type", errorClass(callErr)))
+		case failed:
+			// The tool reported the failure itself, like a missing
+			// argument.
+			span.SetStatus(codes.Error, "")
+			span.SetAttributes(attribute.String("error.type", "tool"))
+		default:
+			span.SetStatus(codes.Ok, "")
+		}
+		status := "ok"
+		if failed {
+			status = "error"
+		}
+		span.SetAttributes(attribute.String("magnet.status", status))
+		return res, err
+	}
+}
+
+// transport traces the requests sent to GitHub through base, passing the
+// trace on with a traceparent header.
+func (t *tracer) transport(base http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return base
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		tags, _ := req.Context().Value(spanTagsKey{}).(spanTags)
+		ctx, span := t.tracer.Start(req.Context(), req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(tags.attributes()...),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.full", req.URL.Redacted()),
+				attribute.String("server.address", req.URL.Hostname()),
+			),
+		)
+		defer span.End()
+		req = req.Clone(ctx)
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(attribute.String("error.type", errorClass(err)))
+			return resp, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, "")
+			span.SetAttributes(attribute.String("error.type", strconv.Itoa(resp.StatusCode)))
+		}
+		return resp, err
+	})
+}
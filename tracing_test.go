@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewTracerFromEnv(t *testing.T) {
+	if tr, err := newTracerFromEnv(); err != nil || tr != nil {
+		t.Errorf("without an endpoint: tracer %v, error %v, want tracing off", tr, err)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	for _, protocol := range []string{"", "grpc", "http/protobuf"} {
+		t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", protocol)
+		tr, err := newTracerFromEnv()
+		if err != nil || tr == nil {
+			t.Errorf("protocol %q: tracer %v, error %v", protocol, tr, err)
+			continue
+		}
+		tr.shutdown()
+	}
+
+	// Settings it can't honor stop the server.
+	for _, protocol := range []string{"http/json", "thrift"} {
+		t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", protocol)
+		if tr, err := newTracerFromEnv(); err == nil || tr != nil {
+			t.Errorf("protocol %s: tracer %v, error %v, want an error", protocol, tr, err)
+		}
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+	if tr, err := newTracerFromEnv(); err == nil || tr != nil {
+		t.Errorf("exporter zipkin: tracer %v, error %v, want an error", tr, err)
+	}
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	if tr, err := newTracerFromEnv(); err != nil || tr != nil {
+		t.Errorf("exporter none: tracer %v, error %v, want tracing off", tr, err)
+	}
+}
+
+func spanAttr(s tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracerSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tr := newTracer(sdktrace.NewSimpleSpanProcessor(exporter), resource.Empty())
+	defer tr.shutdown()
+	var sent trace.SpanContext
+	gh := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(r.Header)))
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+	}), GithubClientOptions{Tracer: tr})
+
+	// The caller's trace is continued.
+	const traceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	args, _ := json.Marshal(map[string]any{"owner": "octo", "repo": "r", "path": "a.md"})
+	handler := tr.middleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		params := req.(*mcp.CallToolRequest).Params
+		var in GetFileContentsArgs
+		json.Unmarshal(params.Arguments, &in)
+		if _, _, err := gh.GetFileContents(ctx, nil, in); err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{}, nil
+	})
+	_, err := handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name:      "get-file-contents",
+		Arguments: args,
+		Meta:      mcp.Meta{"traceparent": traceparent},
+	}})
+	if err == nil {
+		t.Fatal("get-file-contents of a missing file succeeded")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the request and the call", len(spans))
+	}
+	request, call := spans[0], spans[1]
+	if call.Name != "tools/call get-file-contents" || call.SpanKind != trace.SpanKindServer ||
+		call.Parent.TraceID().String() != "0af7651916cd43dd8448eb211c80319c" || !call.Parent.IsRemote() {
+		t.Errorf("call span %s (%s), parent %v", call.Name, call.SpanKind, call.Parent)
+	}
+	if call.Status.Code != codes.Error || spanAttr(call, "error.type") != "not_found" || spanAttr(call, "magnet.status") != "error" {
+		t.Errorf("call span status %v, attributes %v", call.Status, call.Attributes)
+	}
+	if request.SpanKind != trace.SpanKindClient || request.Parent.SpanID() != call.SpanContext.SpanID() {
+		t.Errorf("request span %s (%s) isn't within the call", request.Name, request.SpanKind)
+	}
+	if spanAttr(request, "http.response.status_code") != "404" || request.Status.Code != codes.Error {
+		t.Errorf("request span status %v, attributes %v", request.Status, request.Attributes)
+	}
+	for _, s := range spans {
+		if spanAttr(s, "magnet.tool") != "get-file-contents" || spanAttr(s, "magnet.org") != "octo" {
+			t.Errorf("span %s attributes %v, want the tool and owner", s.Name, s.Attributes)
+		}
+	}
+	if sent.SpanID() != request.SpanContext.SpanID() || sent.TraceID() != call.SpanContext.TraceID() {
+		t.Errorf("GitHub got trace context %v, want the request span", sent)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var getTrafficTool = &mcp.Tool{
+	Name:        "get-traffic",
+	Description: "A tool to get the traffic of a Github repository over the last 14 days: views, clones, top referrers and top paths. It needs push access to the repository",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"owner": {
+				Type:        "string",
+				Description: "Owner of the repository (e.g., kubernetes)",
+			},
+			"repo": {
+				Type:        "string",
+				Description: "Name of the repository (e.g., kubectl)",
+			},
+			"per": {
+				Type:        "string",
+				Description: "Whether to break views and clones down per day or per week (defaults to day)",
+				Enum:        []any{"day", "week"},
+			},
+			"base_url": baseURLProperty(),
+			"no_cache": noCacheProperty(),
+			"profile":  profileProperty(),
+		},
+		Required: []string{"owner", "repo"},
+	},
+}
+
+type GetTrafficArgs struct {
+	CommonArgs
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Per   string `json:"per,omitempty"`
+}
+
+type TrafficCount struct {
+	Timestamp time.Time `json:"timestamp"`
+	Count     int       `json:"count"`
+	Uniques   int       `json:"uniques"`
+}
+
+type TrafficTotals struct {
+	Count   int            `json:"count"`
+	Uniques int            `json:"uniques"`
+	Periods []TrafficCount `json:"periods"`
+}
+
+type TrafficReferrer struct {
+	Referrer string `json:"referrer"`
+	Count    int    `json:"count"`
+	Uniques  int    `json:"uniques"`
+}
+
+type TrafficPath struct {
+	Path    string `json:"path"`
+	Title   string `json:"title"`
+	Count   int    `json:"count"`
+	Uniques int    `json:"uniques"`
+}
+
+type TrafficOutput struct {
+	Repository string            `json:"repository"`
+	Views      TrafficTotals     `json:"views"`
+	Clones     TrafficTotals     `json:"clones"`
+	Referrers  []TrafficReferrer `json:"referrers"`
+	Paths      []TrafficPath     `json:"paths"`
+}
+
+func (c *GithubClient) GetTraffic(ctx context.Context, req *mcp.CallToolRequest, args GetTrafficArgs) (*mcp.CallToolResult, TrafficOutput, error) {
+	if err := elicitMissing(ctx, req, "Which repository should be used?", ownerField(&args.Owner), repoField(&args.Repo)); err != nil {
+		return nil, TrafficOutput{}, err
+	}
+	if args.Owner == "" || args.Repo == "" {
+		return nil, TrafficOutput{}, fmt.Errorf("owner and repo are required")
+	}
+	trafficURL := fmt.Sprintf("%s/repos/%s/%s/traffic", c.apiURL(args.CommonArgs), url.PathEscape(args.Owner), url.PathEscape(args.Repo))
+	per := url.Values{}
+	if args.Per != "" {
+		per.Set("per", args.Per)
+	}
+
+	out := TrafficOutput{Repository: args.Owner + "/" + args.Repo, Referrers: []TrafficReferrer{}, Paths: []TrafficPath{}}
+	var views, clones struct {
+		Count   int            `json:"count"`
+		Uniques int            `json:"uniques"`
+		Views   []TrafficCount `json:"views"`
+		Clones  []TrafficCount `json:"clones"`
+	}
+	for _, get := range []struct {
+		url string
+		v   any
+	}{
+		{trafficURL + "/views?" + per.Encode(), &views},
+		{trafficURL + "/clones?" + per.Encode(), &clones},
+		{trafficURL + "/popular/referrers", &out.Referrers},
+		{trafficURL + "/popular/paths", &out.Paths},
+	} {
+		if err := c.getJSON(ctx, get.url, get.v); err != nil {
+			var apiErr *apiError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+				return nil, TrafficOutput{}, fmt.Errorf("the traffic of %s is only available with push access to it: %w", out.Repository, err)
+			}
+			return nil, TrafficOutput{}, err
+		}
+	}
+	out.Views = TrafficTotals{Count: views.Count, Uniques: views.Uniques, Periods: views.Views}
+	out.Clones = TrafficTotals{Count: clones.Count, Uniques: clones.Uniques, Periods: clones.Clones}
+	if out.Views.Periods == nil {
+		out.Views.Periods = []TrafficCount{}
+	}
+	if out.Clones.Periods == nil {
+		out.Clones.Periods = []TrafficCount{}
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Traffic of %s over the last 14 days\n", out.Repository)
+	fmt.Fprintf(&result, "Views: %d (%d unique visitors)\n", out.Views.Count, out.Views.Uniques)
+	for _, v := range out.Views.Periods {
+		fmt.Fprintf(&result, "  %s: %d (%d unique)\n", v.Timestamp.Format(time.DateOnly), v.Count, v.Uniques)
+	}
+	fmt.Fprintf(&result, "Clones: %d (%d unique cloners)\n", out.Clones.Count, out.Clones.Uniques)
+	for _, v := range out.Clones.Periods {
+		fmt.Fprintf(&result, "  %s: %d (%d unique)\n", v.Timestamp.Format(time.DateOnly), v.Count, v.Uniques)
+	}
+	if len(out.Referrers) > 0 {
+		result.WriteString("Top referrers:\n")
+		for _, r := range out.Referrers {
+			fmt.Fprintf(&result, "  %s: %d (%d unique)\n", r.Referrer, r.Count, r.Uniques)
+		}
+	}
+	if len(out.Paths) > 0 {
+		result.WriteString("Top paths:\n")
+		for _, p := range out.Paths {
+			fmt.Fprintf(&result, "  %s (%s): %d (%d unique)\n", p.Path, p.Title, p.Count, p.Uniques)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// tokenKinds maps the prefixes of GitHub tokens to the kind of token they
+// start.
+var tokenKinds = []struct{ prefix, kind string }{
+	{"github_pat_", "fine-grained personal access token"},
+	{"ghp_", "classic personal access token"},
+	{"gho_", "OAuth app token"},
+	{"ghu_", "GitHub App user token"},
+	{"ghs_", "GitHub App installation token"},
+}
+
+var whoamiTool = &mcp.Tool{
+	Name:        "whoami",
+	Description: "A tool to tell which GitHub identity the server operates as: the authenticated login, the kind and scopes of its token, and the organizations it can access",
+	Annotations: readOnlyAnnotations(),
+	InputSchema: &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"base_url": baseURLProperty(),
+			"profile":  profileProperty(),
+		},
+	},
+}
+
+type WhoamiArgs struct {
+	CommonArgs
+}
+
+type WhoamiOutput struct {
+	Authenticated bool   `json:"authenticated"`
+	Login         string `json:"login,omitempty"`
+	Name          string `json:"name,omitempty"`
+	HTMLURL       string `json:"html_url,omitempty"`
+	TokenKind     string `json:"token_kind,omitempty"`
+	// Scopes are only reported for classic and OAuth app tokens, the
+	// permissions of the other kinds being configured on GitHub.
+	Scopes []string `json:"scopes"`
+	// TokenExpiration is set when the token expires, as GitHub reports it.
+	TokenExpiration string `json:"token_expiration,omitempty"`
+	// AppID and InstallationID identify the GitHub App the server
+	// authenticates as.
+	AppID          int64    `json:"app_id,omitempty"`
+	InstallationID int64    `json:"installation_id,omitempty"`
+	Organizations  []string `json:"organizations"`
+}
+
+func (c *GithubClient) Whoami(ctx context.Context, req *mcp.CallToolRequest, args WhoamiArgs) (*mcp.CallToolResult, WhoamiOutput, error) {
+	// The identity must be the current one, not a cached answer.
+	common := args.CommonArgs
+	common.NoCache = true
+	ctx = context.WithValue(ctx, commonArgsKey{}, common)
+	_, auth, err := c.profile(args.Profile)
+	if err != nil {
+		return nil, WhoamiOutput{}, err
+	}
+	baseURL := c.apiURL(args.CommonArgs)
+	out := WhoamiOutput{Scopes: []string{}, Organizations: []string{}}
+
+	switch auth := auth.(type) {
+	case nil:
+	case *appTokenSource:
+		// Installation tokens can't read /user, the installation is who
+		// the server is.
+		if _, err := auth.Token(ctx); err != nil {
+			return nil, WhoamiOutput{}, err
+		}
+		out.Authenticated = true
+		out.TokenKind = "GitHub App installation token"
+		out.AppID = auth.appID
+		auth.mu.Lock()
+		out.InstallationID = auth.installationID
+		auth.mu.Unlock()
+	default:
+		token, err := auth.Token(ctx)
+		if err != nil {
+			return nil, WhoamiOutput{}, err
+		}
+		out.TokenKind = "token"
+		for _, k := range tokenKinds {
+			if strings.HasPrefix(token, k.prefix) {
+				out.TokenKind = k.kind
+				break
+			}
+		}
+		resp, err := c.get(ctx, baseURL+"/user")
+		if err != nil {
+			return nil, WhoamiOutput{}, err
+		}
+		for s := range strings.SplitSeq(resp.Header.Get("X-OAuth-Scopes"), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out.Scopes = append(out.Scopes, s)
+			}
+		}
+		out.TokenExpiration = resp.Header.Get("GitHub-Authentication-Token-Expiration")
+		var user struct {
+			Login   string `json:"login"`
+			Name    string `json:"name"`
+			HTMLURL string `json:"html_url"`
+		}
+		if err := decodeJSON(resp, &user); err != nil {
+			return nil, WhoamiOutput{}, err
+		}
+		out.Authenticated = true
+		out.Login = user.Login
+		out.Name = user.Name
+		out.HTMLURL = user.HTMLURL
+
+		orgs, err := getAllPages[struct {
+			Login string `json:"login"`
+		}](ctx, c, fmt.Sprintf("%s/user/orgs?per_page=%d", baseURL, c.perPage))
+		if err != nil {
+			return nil, WhoamiOutput{}, err
+		}
+		for _, org := range orgs {
+			out.Organizations = append(out.Organizations, org.Login)
+		}
+	}
+
+	var result strings.Builder
+	switch {
+	case !out.Authenticated:
+		result.WriteString("The server is unauthenticated, no token is configured\n")
+	case out.AppID != 0:
+		fmt.Fprintf(&result, "Authenticated as installation %d of GitHub App %d\n", out.InstallationID, out.AppID)
+	default:
+		fmt.Fprintf(&result, "Authenticated as %s (%s) %s with a %s\n", out.Login, cmp.Or(out.Name, "no name"), out.HTMLURL, out.TokenKind)
+		if len(out.Scopes) > 0 {
+			fmt.Fprintf(&result, "Scopes: %s\n", strings.Join(out.Scopes, ", "))
+		}
+		if out.TokenExpiration != "" {
+			fmt.Fprintf(&result, "Token expires: %s\n", out.TokenExpiration)
+		}
+		fmt.Fprintf(&result, "Organizations: %s\n", cmp.Or(strings.Join(out.Organizations, ", "), "none"))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, out, nil
+}